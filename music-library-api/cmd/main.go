@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"time"
 
 	configs "music-library-api/configs"
 	"music-library-api/internal/handlers"
@@ -41,25 +42,128 @@ func main() {
 		log.Fatal("❌ Failed to init Cloudinary: ", err)
 	}
 
+	trustedProxies, err := utils.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal("❌ Invalid TRUSTED_PROXIES: ", err)
+	}
+
 	// 3. Initialize repositories
 	userRepo := repositories.NewUserRepository(mongodb)
 	trackRepo := repositories.NewTrackRepository(mongodb)
 	playlistRepo := repositories.NewPlaylistRepository()
+	albumRepo := repositories.NewAlbumRepository(mongodb)
+	artistRepo := repositories.NewArtistRepository(mongodb)
+	genreRepo := repositories.NewGenreRepository(mongodb)
+	lyricsRepo := repositories.NewLyricsRepository(mongodb)
+	suggestRepo := repositories.NewSuggestRepository(mongodb)
+	playlistImportRepo := repositories.NewPlaylistImportRepository(mongodb)
+	playlistInvitationRepo := repositories.NewPlaylistInvitationRepository(mongodb)
+	playlistFollowRepo := repositories.NewPlaylistFollowRepository(mongodb)
+	playlistVersionRepo := repositories.NewPlaylistVersionRepository(mongodb)
+	playlistFolderRepo := repositories.NewPlaylistFolderRepository(mongodb)
+	playlistCoverRepo := repositories.NewPlaylistCoverRepository(mongodb)
+	playQueueRepo := repositories.NewPlayQueueRepository(mongodb)
+	playRepo := repositories.NewPlayRepository(mongodb)
+	likeRepo := repositories.NewLikeRepository(mongodb)
+	recommendationRepo := repositories.NewRecommendationRepository(mongodb)
 
 	// 4. Initialize services
 	authService := services.NewAuthService(userRepo, cfg)
 	userService := services.NewUserService(userRepo)
 	trackService := services.NewTrackService(trackRepo, mongodb)
-	playlistService := services.NewPlaylistService(playlistRepo, trackService, cloudUtil)
+	albumService := services.NewAlbumService(albumRepo, likeRepo, trackService, cloudUtil)
+	playlistCoverService := services.NewPlaylistCoverService(playlistCoverRepo, playlistRepo, likeRepo, trackService, albumService, cloudUtil)
+	playlistService := services.NewPlaylistService(playlistRepo, playlistFollowRepo, playlistVersionRepo, likeRepo, trackService, playlistCoverService, cloudUtil)
+	artistService := services.NewArtistService(artistRepo, userRepo, likeRepo, trackService, cloudUtil)
+	genreService := services.NewGenreService(genreRepo, trackService)
+	lyricsService := services.NewLyricsService(lyricsRepo, trackService)
+	suggestService := services.NewSuggestService(suggestRepo)
+	playlistImportService := services.NewPlaylistImportService(playlistImportRepo, playlistRepo, playlistVersionRepo, trackService, playlistCoverService)
+	authzService := services.NewAuthorizationService()
+	playlistCollaborationService := services.NewPlaylistCollaborationService(playlistInvitationRepo, playlistRepo, userRepo)
+	playlistFollowService := services.NewPlaylistFollowService(playlistFollowRepo, playlistService, authzService)
+	playlistVersionService := services.NewPlaylistVersionService(playlistVersionRepo)
+	playlistFolderService := services.NewPlaylistFolderService(playlistFolderRepo, playlistService, trackService, authzService)
+	playQueueService := services.NewPlayQueueService(playQueueRepo, trackService, playlistService, albumService, authzService)
+	playService := services.NewPlayService(playRepo, trackService)
+	likeService := services.NewLikeService(likeRepo, trackService, albumService, artistService, playlistService)
+	recommendationService := services.NewRecommendationService(recommendationRepo, playRepo, likeRepo, trackService)
+
+	if err := trackService.EnsureSearchIndex(); err != nil {
+		log.Printf("Failed to create track search index: %v", err)
+	}
+	if err := artistService.EnsureIndexes(); err != nil {
+		log.Printf("Failed to create artist indexes: %v", err)
+	}
+	if err := playQueueService.EnsureIndexes(); err != nil {
+		log.Printf("Failed to create play queue indexes: %v", err)
+	}
+	if err := playService.EnsureCollections(); err != nil {
+		log.Printf("Failed to create plays collections: %v", err)
+	}
+	if err := likeService.EnsureIndexes(); err != nil {
+		log.Printf("Failed to create like indexes: %v", err)
+	}
+	if err := playlistService.EnsureIndexes(); err != nil {
+		log.Printf("Failed to create playlist indexes: %v", err)
+	}
+	if err := recommendationService.EnsureIndexes(); err != nil {
+		log.Printf("Failed to create recommendation indexes: %v", err)
+	}
+	if n, err := trackService.BackfillSearchFields(); err != nil {
+		log.Printf("Failed to backfill track search fields: %v", err)
+	} else if n > 0 {
+		log.Printf("Backfilled search fields of %d tracks", n)
+	}
+	if n, err := trackService.BackfillPlayCounts(); err != nil {
+		log.Printf("Failed to backfill track play counts: %v", err)
+	} else if n > 0 {
+		log.Printf("Backfilled play counts of %d tracks", n)
+	}
+
+	if n, err := playlistService.BackfillPlaylistEntries(); err != nil {
+		log.Printf("Failed to backfill playlist entries: %v", err)
+	} else if n > 0 {
+		log.Printf("Backfilled entries of %d playlists", n)
+	}
+	if n, err := playlistService.BackfillPlaylistVisibility(); err != nil {
+		log.Printf("Failed to backfill playlist visibility: %v", err)
+	} else if n > 0 {
+		log.Printf("Backfilled visibility of %d playlists", n)
+	}
+	suggestService.Start(30 * time.Second)
+
+	recommendationService.Start(6 * time.Hour)
+
+	playlistCoverService.Start()
+	if n, err := playlistCoverService.EnqueueMissing(); err != nil {
+		log.Printf("Failed to schedule playlist covers: %v", err)
+	} else if n > 0 {
+		log.Printf("Scheduled covers of %d playlists", n)
+	}
 
 	// 5. Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	userHandler := handlers.NewUserHandler(userService)
-	trackHandler := handlers.NewTrackHandler(trackService, mongodb)
-	playlistHandler := handlers.NewPlaylistHandler(playlistService)
+	trackHandler := handlers.NewTrackHandler(trackService, albumService, genreService, lyricsService, mongodb)
+	playlistHandler := handlers.NewPlaylistHandler(playlistService, authzService, cfg.PublicBaseURL, trustedProxies)
+	albumHandler := handlers.NewAlbumHandler(albumService)
+	artistHandler := handlers.NewArtistHandler(artistService)
+	genreHandler := handlers.NewGenreHandler(genreService)
+	lyricsHandler := handlers.NewLyricsHandler(lyricsService, trackService)
+	searchHandler := handlers.NewSearchHandler(suggestService)
+	playlistImportHandler := handlers.NewPlaylistImportHandler(playlistImportService)
+	playlistCollaborationHandler := handlers.NewPlaylistCollaborationHandler(playlistCollaborationService, playlistService, authzService)
+	playlistFollowHandler := handlers.NewPlaylistFollowHandler(playlistFollowService, playlistService, authzService)
+	playlistVersionHandler := handlers.NewPlaylistVersionHandler(playlistVersionService, playlistService, authzService)
+	playlistFolderHandler := handlers.NewPlaylistFolderHandler(playlistFolderService, playlistService, authzService)
+	playQueueHandler := handlers.NewPlayQueueHandler(playQueueService)
+	playHandler := handlers.NewPlayHandler(playService)
+	likeHandler := handlers.NewLikeHandler(likeService, playlistService, authzService)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
 
 	// 6. Initialize router
-	server := router.NewRouter(cfg, authHandler, userHandler, trackHandler, playlistHandler)
+	server := router.NewRouter(cfg, trustedProxies, authHandler, userHandler, trackHandler, playlistHandler, albumHandler, artistHandler, genreHandler, lyricsHandler, searchHandler, playlistImportHandler, playlistCollaborationHandler, playlistFollowHandler, playlistVersionHandler, playlistFolderHandler, playQueueHandler, playHandler, likeHandler, recommendationHandler)
 
 	// 7. Swagger
 	server.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
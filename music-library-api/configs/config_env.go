@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -24,6 +25,15 @@ type Config struct {
 
 	// Admin
 	AdminRoleKey string
+
+	// PublicBaseURL is the address clients reach the API at, e.g.
+	// "https://music.example.com". Used for absolute URLs in exported
+	// playlists; the request host is used when empty.
+	PublicBaseURL string
+
+	// TrustedProxies lists the reverse proxies (IPs or CIDR ranges) whose
+	// X-Forwarded-* headers are honoured. Empty trusts no proxy.
+	TrustedProxies []string
 }
 
 func LoadConfig() *Config {
@@ -55,5 +65,9 @@ func LoadConfig() *Config {
 		JWTSecret:     os.Getenv("JWT_SECRET"),
 		JWTExpiration: os.Getenv("JWT_EXPIRATION"),
 		AdminRoleKey:  os.Getenv("ADMIN_ROLE_KEY"),
+		PublicBaseURL: os.Getenv("PUBLIC_BASE_URL"),
+		TrustedProxies: strings.FieldsFunc(os.Getenv("TRUSTED_PROXIES"), func(r rune) bool {
+			return r == ',' || r == ' '
+		}),
 	}
 }
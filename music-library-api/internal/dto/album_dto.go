@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+type UpdateAlbumRequest struct {
+	Title       string             `json:"title"`
+	Artist      string             `json:"artist"`
+	ReleaseYear int                `json:"release_year"`
+	Discs       []AlbumDiscRequest `json:"discs" binding:"omitempty,dive"`
+}
+
+type AlbumDiscRequest struct {
+	Number   int    `json:"number" binding:"required,min=1"`
+	Subtitle string `json:"subtitle"`
+}
+
+type AlbumResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID        string              `json:"user_id"`
+	Title         string              `json:"title"`
+	Artist        string              `json:"artist"`
+	ReleaseYear   int                 `json:"release_year"`
+	Cover         string              `json:"cover"`
+	TrackCount    int                 `json:"track_count"`
+	TotalDuration int                 `json:"total_duration"` // in seconds
+	LikeCount     int64               `json:"like_count"`
+	Discs         []AlbumDiscResponse `json:"discs"`
+}
+
+type AlbumDiscResponse struct {
+	Number   int                  `json:"number"`
+	Subtitle string               `json:"subtitle"`
+	Duration int                  `json:"duration"` // in seconds
+	Tracks   []AlbumTrackResponse `json:"tracks"`
+}
+
+type AlbumTrackResponse struct {
+	DiscNumber  int           `json:"disc_number"`
+	TrackNumber int           `json:"track_number"`
+	Track       TrackResponse `json:"track"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type UpdateArtistProfileRequest struct {
+	Name   string                `form:"name"`
+	Bio    string                `form:"bio"`
+	Avatar *multipart.FileHeader `form:"avatar"`
+	Links  []string              `form:"links"` // http(s) URLs, replaces existing links when set
+}
+
+type ArtistLinkResponse struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+type ArtistProfileResponse struct {
+	ID        string    `json:"id,omitempty"` // empty until the artist saves a profile
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID      string                    `json:"user_id"`
+	Name        string                    `json:"name"`
+	Bio         string                    `json:"bio"`
+	Avatar      string                    `json:"avatar"`
+	Links       []ArtistLinkResponse      `json:"links"`
+	Stats       ArtistStatsResponse       `json:"stats"`
+	Discography []DiscographyYearResponse `json:"discography"`
+}
+
+type ArtistStatsResponse struct {
+	TrackCount    int64 `json:"track_count"`
+	AlbumCount    int   `json:"album_count"`
+	TotalDuration int64 `json:"total_duration"` // in seconds
+	PlayCount     int64 `json:"play_count"`
+	LikeCount     int64 `json:"like_count"`
+}
+
+type DiscographyYearResponse struct {
+	ReleaseYear int                        `json:"release_year"` // 0 = unknown
+	Albums      []DiscographyAlbumResponse `json:"albums"`
+}
+
+type DiscographyAlbumResponse struct {
+	AlbumID       string          `json:"album_id,omitempty"` // empty for singles
+	Title         string          `json:"title"`
+	TrackCount    int             `json:"track_count"`
+	TotalDuration int             `json:"total_duration"` // in seconds
+	Tracks        []TrackResponse `json:"tracks"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+type CreateGenreRequest struct {
+	Name         string            `json:"name" binding:"required"`
+	Slug         string            `json:"slug"` // derived from name when empty
+	ParentSlug   string            `json:"parent_slug"`
+	Aliases      []string          `json:"aliases"`
+	DisplayNames map[string]string `json:"display_names"` // locale -> name
+}
+
+// UpdateGenreRequest is a partial update: nil fields are left unchanged.
+// ParentSlug set to "" moves the genre to the top level.
+type UpdateGenreRequest struct {
+	Name         string            `json:"name"`
+	ParentSlug   *string           `json:"parent_slug"`
+	Aliases      []string          `json:"aliases"`
+	DisplayNames map[string]string `json:"display_names"`
+}
+
+type MergeGenreRequest struct {
+	TargetSlug string `json:"target_slug" binding:"required"`
+}
+
+type GenreResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Slug         string            `json:"slug"`
+	Name         string            `json:"name"`
+	ParentID     string            `json:"parent_id,omitempty"`
+	Aliases      []string          `json:"aliases"`
+	DisplayNames map[string]string `json:"display_names"`
+	Children     []GenreResponse   `json:"children,omitempty"`
+}
+
+type MergeGenreResponse struct {
+	Genre       GenreResponse `json:"genre"`
+	TracksMoved int64         `json:"tracks_moved"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// LikeResponse is the state of a like after liking or unliking a target.
+type LikeResponse struct {
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	Liked      bool   `json:"liked"`
+	LikeCount  int64  `json:"like_count"`
+}
+
+type LikeItemResponse struct {
+	TargetType string    `json:"target_type"`
+	TargetID   string    `json:"target_id"` // the user ID for artists
+	LikedAt    time.Time `json:"liked_at"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+const (
+	LyricsFormatJSON = "json"
+	LyricsFormatLRC  = "lrc"
+)
+
+// UpsertLyricsRequest takes either an uploaded .lrc/.txt file or the lyrics as text.
+// Text containing LRC time tags is stored as synced lyrics.
+type UpsertLyricsRequest struct {
+	Language string                `form:"language"`
+	Text     string                `form:"text"`
+	File     *multipart.FileHeader `form:"file"`
+}
+
+type LyricLineResponse struct {
+	TimeMs int64  `json:"time_ms"`
+	Text   string `json:"text"`
+}
+
+type LyricsResponse struct {
+	TrackID   string              `json:"track_id"`
+	Language  string              `json:"language"`
+	Synced    bool                `json:"synced"`
+	Source    string              `json:"source"`
+	Text      string              `json:"text"`
+	Lines     []LyricLineResponse `json:"lines"`
+	Languages []string            `json:"languages"` // every language available for the track
+	UpdatedAt time.Time           `json:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+// ScrobbleRequest reports what a device is playing. Send now_playing when a
+// track starts and played when it stops or ends; the play only counts if
+// played_ms reaches the threshold.
+type ScrobbleRequest struct {
+	TrackID   string     `json:"track_id" binding:"required"`
+	Event     string     `json:"event" binding:"required,oneof=now_playing played"`
+	PlayedMs  int64      `json:"played_ms" binding:"min=0"` // time actually listened, without the skipped parts; capped at the track duration
+	StartedAt *time.Time `json:"started_at"`                // RFC 3339; required for played, identifies the play so retries are not counted twice
+}
+
+type ScrobbleResponse struct {
+	Event     string `json:"event"`
+	TrackID   string `json:"track_id"`
+	Counted   bool   `json:"counted"`              // a played event was recorded as a play
+	PlayCount int64  `json:"play_count,omitempty"` // the track's play count after this play
+	Reason    string `json:"reason,omitempty"`     // why a played event was not counted
+}
+
+type PlayHistoryItemResponse struct {
+	TrackID  string         `json:"track_id"`
+	PlayedAt time.Time      `json:"played_at"`
+	PlayedMs int64          `json:"played_ms"`
+	Track    *TrackResponse `json:"track"` // nil if the track was deleted
+}
+
+type NowPlayingResponse struct {
+	TrackID   string         `json:"track_id"`
+	StartedAt time.Time      `json:"started_at"`
+	Track     *TrackResponse `json:"track"`
+}
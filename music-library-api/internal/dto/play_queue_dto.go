@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+// BuildPlayQueueRequest replaces the queue with the tracks of a playlist, an
+// album or a list of tracks, and starts playing.
+type BuildPlayQueueRequest struct {
+	SourceType string   `json:"source_type" binding:"required,oneof=playlist album tracks"`
+	SourceID   string   `json:"source_id" binding:"required_unless=SourceType tracks"`
+	TrackIDs   []string `json:"track_ids" binding:"required_if=SourceType tracks"`
+	StartIndex int      `json:"start_index" binding:"min=0"` // in the source order
+	Shuffle    bool     `json:"shuffle"`
+	Repeat     string   `json:"repeat" binding:"omitempty,oneof=off all one"`
+}
+
+// UpdatePlaybackRequest changes the playback state; omitted fields are left
+// unchanged.
+type UpdatePlaybackRequest struct {
+	CurrentItemID *string `json:"current_item_id"`
+	PositionMs    *int64  `json:"position_ms" binding:"omitempty,min=0"`
+	Playing       *bool   `json:"playing"`
+	Shuffle       *bool   `json:"shuffle"`
+	Repeat        *string `json:"repeat" binding:"omitempty,oneof=off all one"`
+}
+
+type AddToPlayQueueRequest struct {
+	TrackIDs []string `json:"track_ids" binding:"required,min=1"`
+	Next     bool     `json:"next"` // play right after the current track instead of at the end
+}
+
+type PlayQueueItemResponse struct {
+	ID      string         `json:"id"`
+	TrackID string         `json:"track_id"`
+	Track   *TrackResponse `json:"track"` // nil if the track was deleted
+}
+
+type PlayQueueSourceResponse struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+}
+
+type PlayQueueResponse struct {
+	Current    *PlayQueueItemResponse   `json:"current"`
+	PositionMs int64                    `json:"position_ms"`
+	PositionAt time.Time                `json:"position_at"` // when position_ms was reported; add the time since if playing
+	Playing    bool                     `json:"playing"`
+	Shuffle    bool                     `json:"shuffle"`
+	Repeat     string                   `json:"repeat"`
+	Upcoming   []PlayQueueItemResponse  `json:"upcoming"` // in play order, after the current track
+	Source     *PlayQueueSourceResponse `json:"source"`
+	Device     string                   `json:"device"` // X-Device-ID of the device that made the change
+	Version    int64                    `json:"version"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+}
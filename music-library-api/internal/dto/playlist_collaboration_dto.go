@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// InvitePlaylistCollaboratorRequest identifies the invitee by user ID or email.
+type InvitePlaylistCollaboratorRequest struct {
+	UserID string `json:"user_id" binding:"required_without=Email"`
+	Email  string `json:"email" binding:"omitempty,email"`
+	Role   string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type UpdatePlaylistCollaboratorRequest struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type PlaylistCollaboratorResponse struct {
+	UserID  string    `json:"user_id"`
+	Role    string    `json:"role"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type PlaylistInvitationResponse struct {
+	ID            string     `json:"id"`
+	PlaylistID    string     `json:"playlist_id"`
+	PlaylistTitle string     `json:"playlist_title"`
+	InviteeID     string     `json:"invitee_id"`
+	InviterID     string     `json:"inviter_id"`
+	Role          string     `json:"role"`
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"created_at"`
+	RespondedAt   *time.Time `json:"responded_at,omitempty"`
+}
+
+type CreatePlaylistShareLinkRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"` // RFC 3339; omitted = never expires
+}
+
+type PlaylistShareLinkResponse struct {
+	ID        string     `json:"id"`
+	Token     string     `json:"token"`
+	URL       string     `json:"url"` // read-only link to the playlist, usable without logging in
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Expired   bool       `json:"expired"`
+}
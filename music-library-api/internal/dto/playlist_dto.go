@@ -1,6 +1,9 @@
 package dto
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+)
 
 type PlaylistUpdateMode string
 
@@ -13,6 +16,7 @@ type CreatePlaylistRequest struct {
 	Title      string                `form:"title" binding:"required"`
 	AlbumCover *multipart.FileHeader `form:"album_cover"`
 	TrackIDs   []string              `form:"track_ids"`
+	Visibility string                `form:"visibility" binding:"omitempty,oneof=public unlisted private"` // default public
 }
 
 type UpdatePlaylistRequest struct {
@@ -20,14 +24,161 @@ type UpdatePlaylistRequest struct {
 	AlbumCover *multipart.FileHeader `form:"album_cover"`
 	TrackIDs   []string              `form:"track_ids"`
 	Mode       PlaylistUpdateMode    `form:"mode" binding:"omitempty,oneof=overwrite append"`
+	Visibility string                `form:"visibility" binding:"omitempty,oneof=public unlisted private"` // owner only
+}
+
+const (
+	PlaylistSortTitle       = "title"
+	PlaylistSortArtist      = "artist"
+	PlaylistSortAlbum       = "album"
+	PlaylistSortDuration    = "duration"
+	PlaylistSortReleaseYear = "release_year"
+	PlaylistSortAddedAt     = "added_at"
+)
+
+// Playlist export formats, chosen by file extension or ?format=
+const (
+	PlaylistFormatM3U8 = "m3u8"
+	PlaylistFormatM3U  = "m3u" // same content as m3u8
+	PlaylistFormatPLS  = "pls"
+	PlaylistFormatXSPF = "xspf"
+	PlaylistFormatJSPF = "jspf"
+)
+
+type InsertPlaylistEntriesRequest struct {
+	TrackIDs []string `json:"track_ids" binding:"required,min=1"`
+	Position *int     `json:"position" binding:"omitempty,min=0"` // 0-based; omitted = append
+}
+
+type MovePlaylistEntryRequest struct {
+	Position *int `json:"position" binding:"required,min=0"` // 0-based target position
+}
+
+type SortPlaylistRequest struct {
+	Field string `json:"field" binding:"required,oneof=title artist album duration release_year added_at"`
+	Order string `json:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+// SmartRule is a node of a smart playlist's rule tree: a group with a
+// combinator ("all" or "any") and child rules, or a condition such as
+// {"field": "release_year", "operator": "gte", "value": 2015}.
+type SmartRule struct {
+	Combinator string      `json:"combinator,omitempty"`
+	Rules      []SmartRule `json:"rules,omitempty"`
+	Field      string      `json:"field,omitempty"`
+	Operator   string      `json:"operator,omitempty"`
+	Value      interface{} `json:"value,omitempty"`
+}
+
+type CreateSmartPlaylistRequest struct {
+	Title      string    `json:"title" binding:"required"`
+	Rules      SmartRule `json:"rules"`
+	Sort       string    `json:"sort" binding:"omitempty,oneof=title artist album release_year duration play_count like_count created_at"`
+	Order      string    `json:"order" binding:"omitempty,oneof=asc desc"`
+	Limit      int       `json:"limit" binding:"omitempty,min=1,max=500"` // default 100
+	Visibility string    `json:"visibility" binding:"omitempty,oneof=public unlisted private"`
+}
+
+type UpdateSmartPlaylistRequest struct {
+	Rules SmartRule `json:"rules"`
+	Sort  string    `json:"sort" binding:"omitempty,oneof=title artist album release_year duration play_count like_count created_at"`
+	Order string    `json:"order" binding:"omitempty,oneof=asc desc"`
+	Limit int       `json:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+type SmartPlaylistResponse struct {
+	Rules SmartRule `json:"rules"`
+	Sort  string    `json:"sort,omitempty"`
+	Order string    `json:"order"`
+	Limit int       `json:"limit"`
 }
 
 type PlaylistResponse struct {
-	ID         string   `json:"id"`
-	UserID     string   `json:"user_id"`
-	Title      string   `json:"title"`
-	AlbumCover string   `json:"album_cover"`
-	TrackIDs   []string `json:"track_ids"`
-	CreatedAt  string   `json:"created_at"`
-	UpdatedAt  string   `json:"updated_at"`
+	ID             string                         `json:"id"`
+	UserID         string                         `json:"user_id"`
+	Title          string                         `json:"title"`
+	AlbumCover     string                         `json:"album_cover"`
+	CoverGenerated bool                           `json:"cover_generated"` // album_cover was generated from the playlist's albums, not uploaded
+	TrackIDs       []string                       `json:"track_ids"`
+	Entries        []PlaylistEntryResponse        `json:"entries"`
+	Version        int64                          `json:"version"`
+	Smart          *SmartPlaylistResponse         `json:"smart,omitempty"` // set for smart playlists, whose entries are computed
+	Collaborators  []PlaylistCollaboratorResponse `json:"collaborators"`
+	Visibility     string                         `json:"visibility"`
+	ForkedFrom     *PlaylistForkSourceResponse    `json:"forked_from"` // set for forks
+	FollowerCount  int64                          `json:"follower_count"`
+	LikeCount      int64                          `json:"like_count"`
+	Kind           string                         `json:"kind,omitempty"` // "liked_songs" for the user's Liked Songs
+	CreatedAt      string                         `json:"created_at"`
+	UpdatedAt      string                         `json:"updated_at"`
+}
+
+type PlaylistEntryResponse struct {
+	ID      string    `json:"id"`
+	TrackID string    `json:"track_id"`
+	AddedAt time.Time `json:"added_at"`
+	AddedBy string    `json:"added_by"` // user who added the entry
+}
+
+// ExpandedPlaylistResponse is returned by GET /playlists/:id?expand=tracks.
+type ExpandedPlaylistResponse struct {
+	PlaylistResponse
+	TrackCount    int                    `json:"track_count"`    // entries, deleted tracks included
+	TotalDuration int                    `json:"total_duration"` // in seconds
+	Tracks        []PlaylistItemResponse `json:"tracks"`
+}
+
+// PlaylistItemResponse is one playlist entry with its track. Track is null and
+// Deleted is true when the track no longer exists.
+type PlaylistItemResponse struct {
+	EntryID  string                 `json:"entry_id"`
+	Position int                    `json:"position"` // 0-based
+	AddedAt  time.Time              `json:"added_at"`
+	AddedBy  string                 `json:"added_by"`
+	TrackID  string                 `json:"track_id"`
+	Deleted  bool                   `json:"deleted"`
+	Track    *PlaylistTrackResponse `json:"track"`
+}
+
+type PlaylistTrackResponse struct {
+	TrackResponse
+	AlbumInfo     *PlaylistAlbumResponse  `json:"album_info,omitempty"`
+	ArtistProfile *PlaylistArtistResponse `json:"artist_profile,omitempty"`
+}
+
+type PlaylistAlbumResponse struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Artist      string `json:"artist"`
+	ReleaseYear int    `json:"release_year"`
+	Cover       string `json:"cover"`
+}
+
+type PlaylistArtistResponse struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+}
+
+type PlaylistForkSourceResponse struct {
+	PlaylistID string    `json:"playlist_id"`
+	Version    int64     `json:"version"` // source version at the last sync
+	SyncedAt   time.Time `json:"synced_at"`
+}
+
+type ForkPlaylistRequest struct {
+	Title      string `json:"title"` // default: the source's title
+	Visibility string `json:"visibility" binding:"omitempty,oneof=public unlisted private"`
+}
+
+// PullForkResponse is the fork after pulling, with the tracks the source added and removed.
+type PullForkResponse struct {
+	Playlist PlaylistResponse `json:"playlist"`
+	Added    []string         `json:"added"`
+	Removed  []string         `json:"removed"`
+}
+
+type FollowPlaylistResponse struct {
+	Following     bool  `json:"following"`
+	FollowerCount int64 `json:"follower_count"`
 }
@@ -0,0 +1,64 @@
+package dto
+
+import "time"
+
+type CreatePlaylistFolderRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	ParentID string `json:"parent_id"`                          // empty = top level
+	Position *int   `json:"position" binding:"omitempty,min=0"` // among the siblings; omitted = last
+}
+
+type RenamePlaylistFolderRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type MovePlaylistFolderRequest struct {
+	ParentID string `json:"parent_id"` // empty = top level
+	Position *int   `json:"position" binding:"omitempty,min=0"`
+}
+
+type MovePlaylistToFolderRequest struct {
+	FolderID string `json:"folder_id"`                          // empty = out of any folder
+	Position *int   `json:"position" binding:"omitempty,min=0"` // among the folder's playlists; omitted = last
+}
+
+type PlaylistFolderResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	ParentID    *string   `json:"parent_id"`
+	Position    int       `json:"position"`
+	PlaylistIDs []string  `json:"playlist_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PlaylistTreeItem is a playlist in the folder tree.
+type PlaylistTreeItem struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	AlbumCover    string `json:"album_cover"`
+	Visibility    string `json:"visibility"`
+	Smart         bool   `json:"smart"`
+	TrackCount    int    `json:"track_count"`
+	TotalDuration int    `json:"total_duration"` // in seconds
+}
+
+// PlaylistFolderNode is a folder with its subfolders and playlists. The counts
+// include the subfolders.
+type PlaylistFolderNode struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	Folders       []PlaylistFolderNode `json:"folders"`
+	Playlists     []PlaylistTreeItem   `json:"playlists"`
+	PlaylistCount int                  `json:"playlist_count"`
+	TotalDuration int                  `json:"total_duration"` // in seconds
+}
+
+// PlaylistFolderTreeResponse is the root of a user's tree: top-level folders,
+// then the playlists that are in no folder.
+type PlaylistFolderTreeResponse struct {
+	Folders       []PlaylistFolderNode `json:"folders"`
+	Playlists     []PlaylistTreeItem   `json:"playlists"`
+	PlaylistCount int                  `json:"playlist_count"`
+	TotalDuration int                  `json:"total_duration"` // in seconds
+}
@@ -0,0 +1,49 @@
+package dto
+
+import "mime/multipart"
+
+type ImportPlaylistRequest struct {
+	File  *multipart.FileHeader `form:"file" binding:"required"`
+	Title string                `form:"title"` // defaults to the title in the file, then the file name
+}
+
+type ResolvePlaylistImportRequest struct {
+	Resolutions []PlaylistImportResolution `json:"resolutions" binding:"required,min=1,dive"`
+}
+
+// PlaylistImportResolution picks the track for one entry. An empty TrackID
+// dismisses the entry, leaving it unmatched.
+type PlaylistImportResolution struct {
+	Index   *int   `json:"index" binding:"required,min=0"`
+	TrackID string `json:"track_id"`
+}
+
+type PlaylistImportResponse struct {
+	ID         string                        `json:"id"`
+	PlaylistID string                        `json:"playlist_id"`
+	FileName   string                        `json:"file_name"`
+	Format     string                        `json:"format"`
+	Summary    PlaylistImportSummary         `json:"summary"`
+	Entries    []PlaylistImportEntryResponse `json:"entries"`
+}
+
+type PlaylistImportSummary struct {
+	Total     int `json:"total"`
+	Matched   int `json:"matched"`
+	Ambiguous int `json:"ambiguous"`
+	Unmatched int `json:"unmatched"`
+}
+
+type PlaylistImportEntryResponse struct {
+	Index      int             `json:"index"`
+	Location   string          `json:"location,omitempty"`
+	Title      string          `json:"title,omitempty"`
+	Artist     string          `json:"artist,omitempty"`
+	Album      string          `json:"album,omitempty"`
+	Duration   int             `json:"duration,omitempty"`
+	Status     string          `json:"status"`
+	Method     string          `json:"method,omitempty"`
+	TrackID    string          `json:"track_id,omitempty"`
+	EntryID    string          `json:"entry_id,omitempty"`
+	Candidates []TrackResponse `json:"candidates,omitempty"`
+}
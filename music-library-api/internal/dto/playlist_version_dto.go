@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+type PlaylistVersionSummaryResponse struct {
+	Version      int64     `json:"version"`
+	Title        string    `json:"title"`
+	AlbumCover   string    `json:"album_cover"`
+	TrackCount   int       `json:"track_count"`
+	Smart        bool      `json:"smart"`
+	ChangedBy    *string   `json:"changed_by"`
+	RestoredFrom *int64    `json:"restored_from,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PlaylistVersionResponse struct {
+	PlaylistVersionSummaryResponse
+	Entries []PlaylistEntryResponse `json:"entries"`
+	Rules   *SmartPlaylistResponse  `json:"rules,omitempty"` // smart playlists only
+}
+
+type PlaylistFieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type PlaylistDiffEntry struct {
+	EntryID  string `json:"entry_id"`
+	TrackID  string `json:"track_id"`
+	Position int    `json:"position"` // in the version it appears in
+}
+
+type PlaylistDiffMove struct {
+	EntryID string `json:"entry_id"`
+	TrackID string `json:"track_id"`
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+}
+
+type PlaylistVersionDiffResponse struct {
+	From         int64                `json:"from"`
+	To           int64                `json:"to"`
+	Title        *PlaylistFieldChange `json:"title,omitempty"`
+	AlbumCover   *PlaylistFieldChange `json:"album_cover,omitempty"`
+	SmartChanged bool                 `json:"smart_changed"`
+	Added        []PlaylistDiffEntry  `json:"added"`
+	Removed      []PlaylistDiffEntry  `json:"removed"`
+	Moved        []PlaylistDiffMove   `json:"moved"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type RecommendedTrackResponse struct {
+	Track     TrackResponse `json:"track"`
+	Score     float64       `json:"score"`                // relative within one list, 0 for popular fallbacks
+	Reason    string        `json:"reason"`               // listened_together, similar_style or popular
+	BecauseOf string        `json:"because_of,omitempty"` // the track of yours it is most similar to
+}
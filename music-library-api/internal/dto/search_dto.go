@@ -0,0 +1,17 @@
+package dto
+
+type SuggestionResponse struct {
+	ID        string `json:"id,omitempty"` // empty for artists and tags
+	Text      string `json:"text"`
+	Subtitle  string `json:"subtitle,omitempty"`
+	Highlight string `json:"highlight"` // Text with the matched prefixes wrapped in <em>
+}
+
+type SuggestResponse struct {
+	Query     string               `json:"query"`
+	Tracks    []SuggestionResponse `json:"tracks"`
+	Artists   []SuggestionResponse `json:"artists"`
+	Albums    []SuggestionResponse `json:"albums"`
+	Playlists []SuggestionResponse `json:"playlists"`
+	Tags      []SuggestionResponse `json:"tags"`
+}
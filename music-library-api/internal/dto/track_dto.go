@@ -11,6 +11,17 @@ type TrackCreateRequest struct {
 	Genre       string                `form:"genre"`
 	ReleaseYear int                   `form:"release_year"`
 	File        *multipart.FileHeader `form:"file" binding:"required"`
+
+	// Credits and licensing, falling back to the ID3 TSRC/TCOM/TEXT/TCOP frames
+	ISRC      string   `form:"isrc"`
+	Composers []string `form:"composers"`
+	Lyricists []string `form:"lyricists"`
+	Producers []string `form:"producers"`
+	Copyright string   `form:"copyright"`
+	License   string   `form:"license" binding:"omitempty,oneof=all-rights-reserved cc0 cc-by cc-by-sa cc-by-nd cc-by-nc cc-by-nc-sa cc-by-nc-nd public-domain"`
+	Explicit  bool     `form:"explicit"`
+
+	Tags []string `form:"tags"`
 }
 
 type UpdateTrackRequest struct {
@@ -19,6 +30,19 @@ type UpdateTrackRequest struct {
 	Album       string `json:"album"`
 	Genre       string `json:"genre"`
 	ReleaseYear int    `json:"release_year"`
+	DiscNumber  int    `json:"disc_number" binding:"omitempty,min=1"`
+	TrackNumber int    `json:"track_number" binding:"omitempty,min=1"`
+
+	// Credits and licensing; omitted fields are left unchanged, empty values clear them
+	ISRC      *string  `json:"isrc"`
+	Composers []string `json:"composers"`
+	Lyricists []string `json:"lyricists"`
+	Producers []string `json:"producers"`
+	Copyright *string  `json:"copyright"`
+	License   *string  `json:"license" binding:"omitempty,oneof=all-rights-reserved cc0 cc-by cc-by-sa cc-by-nd cc-by-nc cc-by-nc-sa cc-by-nc-nd public-domain"`
+	Explicit  *bool    `json:"explicit"`
+
+	Tags []string `json:"tags"`
 }
 
 type TrackResponse struct {
@@ -30,15 +54,68 @@ type TrackResponse struct {
 	Artist      string `json:"artist"`
 	Album       string `json:"album"`
 	Genre       string `json:"genre"`
+	GenreID     string `json:"genre_id,omitempty"`
 	ReleaseYear int    `json:"release_year"`
 	Duration    int    `json:"duration"`
 	FileID      string `json:"file_id"`
 	UserID      string `json:"user_id"`
+	AlbumID     string `json:"album_id,omitempty"`
+	DiscNumber  int    `json:"disc_number"`
+	TrackNumber int    `json:"track_number"`
+	PlayCount   int64  `json:"play_count"`
+	LikeCount   int64  `json:"like_count"`
+
+	ISRC      string   `json:"isrc,omitempty"`
+	Composers []string `json:"composers"`
+	Lyricists []string `json:"lyricists"`
+	Producers []string `json:"producers"`
+	Copyright string   `json:"copyright,omitempty"`
+	License   string   `json:"license,omitempty"`
+	Explicit  bool     `json:"explicit"`
+
+	Tags []string `json:"tags"`
 }
 
 type TrackListResponse struct {
-	Page       int             `json:"page"`
+	Page       int             `json:"page,omitempty"` // omitted in cursor mode
 	Limit      int             `json:"limit"`
 	TotalCount int64           `json:"total_count"`
 	Data       []TrackResponse `json:"data"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	PrevCursor string          `json:"prev_cursor,omitempty"`
+}
+
+type TrackSearchResult struct {
+	TrackResponse
+	Score float64 `json:"score"`
+	// Matched fields with the matching words wrapped in <em>, e.g. {"title": "<em>Anh</em> Ơi"}
+	Highlights map[string]string `json:"highlights"`
+}
+
+type TrackSearchResponse struct {
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalCount int64               `json:"total_count"`
+	Data       []TrackSearchResult `json:"data"`
+	Facets     TrackFacetsResponse `json:"facets"`
+}
+
+type FacetCountResponse struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type DurationFacetResponse struct {
+	Min   int   `json:"min"`           // seconds, inclusive
+	Max   int   `json:"max,omitempty"` // seconds, exclusive; omitted for the last bucket
+	Count int64 `json:"count"`
+}
+
+type TrackFacetsResponse struct {
+	Genres    []FacetCountResponse    `json:"genres"`
+	Artists   []FacetCountResponse    `json:"artists"`
+	Years     []FacetCountResponse    `json:"years"`
+	Durations []DurationFacetResponse `json:"durations"`
+	Tags      []FacetCountResponse    `json:"tags"`
+	Uploaders []FacetCountResponse    `json:"uploaders"`
 }
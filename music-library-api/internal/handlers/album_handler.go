@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AlbumHandler struct {
+	service services.IAlbumService
+}
+
+func NewAlbumHandler(service services.IAlbumService) *AlbumHandler {
+	return &AlbumHandler{service: service}
+}
+
+// GetAlbumByID godoc
+// @Summary      Get album by ID
+// @Description  Retrieve an album with its ordered tracklist (grouped by disc) and total duration
+// @Tags         albums
+// @Produce      json
+// @Param        id     path      string  true  "Album ID"
+// @Success      200    {object}  dto.AlbumResponse
+// @Failure      404    {object}  map[string]string
+// @Router       /albums/{id} [get]
+func (h *AlbumHandler) GetAlbumByID(c *gin.Context) {
+	id := c.Param("id")
+
+	album, err := h.service.GetAlbumResponse(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "album not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// UpdateAlbum godoc
+// @Summary      Update an album
+// @Description  Update album metadata and per-disc subtitles
+// @Tags         albums
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                  true  "Album ID"
+// @Param        album  body      dto.UpdateAlbumRequest  true  "Album update info"
+// @Success      200    {object}  dto.AlbumResponse
+// @Failure      400    {object}  map[string]string
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /albums/{id} [patch]
+func (h *AlbumHandler) UpdateAlbum(c *gin.Context) {
+	id := c.Param("id")
+
+	album, err := h.service.GetAlbumByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "album not found"})
+		return
+	}
+
+	// Ownership check
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	if role != "admin" && album.UserID.Hex() != userID.(string) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only update your own albums"})
+		return
+	}
+
+	var req dto.UpdateAlbumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.UpdateAlbum(id, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ArtistHandler struct {
+	service services.IArtistService
+}
+
+func NewArtistHandler(service services.IArtistService) *ArtistHandler {
+	return &ArtistHandler{service: service}
+}
+
+// GetArtist godoc
+// @Summary      Get artist profile
+// @Description  Public artist page: bio, avatar, links, discography grouped by release year and album, and statistics. Accepts an artist profile ID or the managing user's ID.
+// @Tags         artists
+// @Produce      json
+// @Param        id     path      string  true  "Artist profile ID or user ID"
+// @Success      200    {object}  dto.ArtistProfileResponse
+// @Failure      404    {object}  map[string]string
+// @Failure      500    {object}  map[string]string
+// @Router       /artists/{id} [get]
+func (h *ArtistHandler) GetArtist(c *gin.Context) {
+	id := c.Param("id")
+
+	profile, err := h.service.GetArtistProfile(id)
+	if err != nil {
+		respondArtistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateMyArtistProfile godoc
+// @Summary      Update own artist profile
+// @Description  Create or update the artist profile managed by the current user
+// @Tags         artists
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        name    formData  string    false  "Display name"
+// @Param        bio     formData  string    false  "Biography"
+// @Param        avatar  formData  file      false  "Avatar image"
+// @Param        links   formData  []string  false  "Links (http/https URLs)"
+// @Success      200     {object}  dto.ArtistProfileResponse
+// @Failure      400     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /artists/me [patch]
+func (h *ArtistHandler) UpdateMyArtistProfile(c *gin.Context) {
+	var req dto.UpdateArtistProfileRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	profile, err := h.service.UpdateMyArtistProfile(userID.(string), &req)
+	if err != nil {
+		respondArtistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateArtistProfile godoc
+// @Summary      Update an artist profile
+// @Description  Update an artist profile by ID (owner or admin)
+// @Tags         artists
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id      path      string    true   "Artist profile ID"
+// @Param        name    formData  string    false  "Display name"
+// @Param        bio     formData  string    false  "Biography"
+// @Param        avatar  formData  file      false  "Avatar image"
+// @Param        links   formData  []string  false  "Links (http/https URLs)"
+// @Success      200     {object}  dto.ArtistProfileResponse
+// @Failure      400     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /artists/{id} [patch]
+func (h *ArtistHandler) UpdateArtistProfile(c *gin.Context) {
+	id := c.Param("id")
+
+	artist, err := h.service.GetArtistByID(id)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "artist not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Ownership check
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	if role != "admin" && artist.UserID.Hex() != userID.(string) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can only update your own artist profile"})
+		return
+	}
+
+	var req dto.UpdateArtistProfileRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.service.UpdateArtistProfile(id, &req)
+	if err != nil {
+		respondArtistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func respondArtistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrArtistNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidArtistLink):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/services"
+	"music-library-api/pkg/constants"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GenreHandler struct {
+	service services.IGenreService
+}
+
+func NewGenreHandler(service services.IGenreService) *GenreHandler {
+	return &GenreHandler{service: service}
+}
+
+// GetGenres godoc
+// @Summary      Get genre tree
+// @Description  Retrieve all genres nested by parent/child relationship
+// @Tags         genres
+// @Produce      json
+// @Success      200    {array}   dto.GenreResponse
+// @Failure      500    {object}  map[string]string
+// @Router       /genres [get]
+func (h *GenreHandler) GetGenres(c *gin.Context) {
+	tree, err := h.service.GetGenreTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+// GetGenre godoc
+// @Summary      Get genre by slug
+// @Description  Retrieve a genre with its sub-genres
+// @Tags         genres
+// @Produce      json
+// @Param        slug   path      string  true  "Genre slug"
+// @Success      200    {object}  dto.GenreResponse
+// @Failure      404    {object}  map[string]string
+// @Router       /genres/{slug} [get]
+func (h *GenreHandler) GetGenre(c *gin.Context) {
+	genre, err := h.service.GetGenre(c.Param("slug"))
+	if err != nil {
+		respondGenreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// GetGenreTracks godoc
+// @Summary      Get tracks of a genre
+// @Description  Retrieve a paginated list of tracks in a genre, including its sub-genres
+// @Tags         genres
+// @Produce      json
+// @Param        slug   path      string  true   "Genre slug"
+// @Param        page   query     int     false  "Page number"
+// @Param        limit  query     int     false  "Page size"
+// @Success      200    {object}  dto.TrackListResponse
+// @Failure      404    {object}  map[string]string
+// @Router       /genres/{slug}/tracks [get]
+func (h *GenreHandler) GetGenreTracks(c *gin.Context) {
+	page, limit := constants.ParsePagination(c.Query("page"), c.Query("limit"))
+
+	list, totalCount, err := h.service.GetGenreTracks(c.Param("slug"), page, limit)
+	if err != nil {
+		respondGenreError(c, err)
+		return
+	}
+
+	resp := make([]dto.TrackResponse, 0)
+	for _, t := range list {
+		resp = append(resp, mappers.ToTrackResponse(t))
+	}
+
+	c.JSON(http.StatusOK, dto.TrackListResponse{
+		Page:       page,
+		Limit:      limit,
+		TotalCount: totalCount,
+		Data:       resp,
+	})
+}
+
+// CreateGenre godoc
+// @Summary      Create a genre
+// @Description  Create a genre, optionally under a parent genre (admin only)
+// @Tags         genres
+// @Accept       json
+// @Produce      json
+// @Param        genre  body      dto.CreateGenreRequest  true  "Genre info"
+// @Success      201    {object}  dto.GenreResponse
+// @Failure      400    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /genres [post]
+func (h *GenreHandler) CreateGenre(c *gin.Context) {
+	var req dto.CreateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	genre, err := h.service.CreateGenre(&req)
+	if err != nil {
+		respondGenreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, genre)
+}
+
+// UpdateGenre godoc
+// @Summary      Update a genre
+// @Description  Rename, re-parent or edit aliases and display names of a genre (admin only)
+// @Tags         genres
+// @Accept       json
+// @Produce      json
+// @Param        slug   path      string                  true  "Genre slug"
+// @Param        genre  body      dto.UpdateGenreRequest  true  "Genre update info"
+// @Success      200    {object}  dto.GenreResponse
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /genres/{slug} [patch]
+func (h *GenreHandler) UpdateGenre(c *gin.Context) {
+	var req dto.UpdateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	genre, err := h.service.UpdateGenre(c.Param("slug"), &req)
+	if err != nil {
+		respondGenreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// MergeGenre godoc
+// @Summary      Merge genres
+// @Description  Merge a genre into another: its tracks are re-pointed and its names become aliases of the target (admin only)
+// @Tags         genres
+// @Accept       json
+// @Produce      json
+// @Param        slug   path      string                 true  "Source genre slug"
+// @Param        merge  body      dto.MergeGenreRequest  true  "Target genre"
+// @Success      200    {object}  dto.MergeGenreResponse
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /genres/{slug}/merge [post]
+func (h *GenreHandler) MergeGenre(c *gin.Context) {
+	var req dto.MergeGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.MergeGenre(c.Param("slug"), req.TargetSlug)
+	if err != nil {
+		respondGenreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func respondGenreError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"music-library-api/internal/models"
+	"music-library-api/internal/services"
+	"music-library-api/pkg/constants"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LikeHandler struct {
+	service         services.ILikeService
+	playlistService services.IPlaylistService
+	authz           services.IAuthorizationService
+}
+
+func NewLikeHandler(service services.ILikeService, playlistService services.IPlaylistService, authz services.IAuthorizationService) *LikeHandler {
+	return &LikeHandler{service: service, playlistService: playlistService, authz: authz}
+}
+
+// LikeTrack godoc
+// @Summary      Like a track
+// @Description  Add a track to your Liked Songs. Liking twice is a no-op.
+// @Tags         Likes
+// @Produce      json
+// @Param        id  path  string  true  "Track ID"
+// @Success      200 {object} dto.LikeResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /tracks/{id}/like [post]
+func (h *LikeHandler) LikeTrack(c *gin.Context) {
+	h.like(c, models.LikeTargetTrack)
+}
+
+// UnlikeTrack godoc
+// @Summary      Unlike a track
+// @Tags         Likes
+// @Produce      json
+// @Param        id  path  string  true  "Track ID"
+// @Success      200 {object} dto.LikeResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /tracks/{id}/like [delete]
+func (h *LikeHandler) UnlikeTrack(c *gin.Context) {
+	h.unlike(c, models.LikeTargetTrack)
+}
+
+// LikeAlbum godoc
+// @Summary      Like an album
+// @Tags         Likes
+// @Produce      json
+// @Param        id  path  string  true  "Album ID"
+// @Success      200 {object} dto.LikeResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /albums/{id}/like [post]
+func (h *LikeHandler) LikeAlbum(c *gin.Context) {
+	h.like(c, models.LikeTargetAlbum)
+}
+
+// UnlikeAlbum godoc
+// @Summary      Unlike an album
+// @Tags         Likes
+// @Produce      json
+// @Param        id  path  string  true  "Album ID"
+// @Success      200 {object} dto.LikeResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /albums/{id}/like [delete]
+func (h *LikeHandler) UnlikeAlbum(c *gin.Context) {
+	h.unlike(c, models.LikeTargetAlbum)
+}
+
+// LikeArtist godoc
+// @Summary      Like an artist
+// @Description  The artist is given by profile ID or user ID, like in GET /artists/{id}.
+// @Tags         Likes
+// @Produce      json
+// @Param        id  path  string  true  "Artist profile ID or user ID"
+// @Success      200 {object} dto.LikeResponse "target_id is the artist's user ID"
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /artists/{id}/like [post]
+func (h *LikeHandler) LikeArtist(c *gin.Context) {
+	h.like(c, models.LikeTargetArtist)
+}
+
+// UnlikeArtist godoc
+// @Summary      Unlike an artist
+// @Tags         Likes
+// @Produce      json
+// @Param        id  path  string  true  "Artist profile ID or user ID"
+// @Success      200 {object} dto.LikeResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /artists/{id}/like [delete]
+func (h *LikeHandler) UnlikeArtist(c *gin.Context) {
+	h.unlike(c, models.LikeTargetArtist)
+}
+
+// LikePlaylist godoc
+// @Summary      Like a playlist
+// @Description  Like a playlist you can see
+// @Tags         Likes
+// @Produce      json
+// @Param        id  path  string  true  "Playlist ID"
+// @Success      200 {object} dto.LikeResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/like [post]
+func (h *LikeHandler) LikePlaylist(c *gin.Context) {
+	if _, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleViewer); !ok {
+		return
+	}
+	h.like(c, models.LikeTargetPlaylist)
+}
+
+// UnlikePlaylist godoc
+// @Summary      Unlike a playlist
+// @Tags         Likes
+// @Produce      json
+// @Param        id  path  string  true  "Playlist ID"
+// @Success      200 {object} dto.LikeResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/like [delete]
+func (h *LikeHandler) UnlikePlaylist(c *gin.Context) {
+	// No access check: users can always take back a like
+	h.unlike(c, models.LikeTargetPlaylist)
+}
+
+// GetMyLikes godoc
+// @Summary      List my likes
+// @Description  The user's likes of one type, most recent first. Liked tracks are also the Liked Songs playlist.
+// @Tags         Likes
+// @Produce      json
+// @Param        type   query string true  "What was liked" Enums(track, album, artist, playlist)
+// @Param        page   query int    false "Page number"
+// @Param        limit  query int    false "Page size"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/likes [get]
+func (h *LikeHandler) GetMyLikes(c *gin.Context) {
+	page, limit := constants.ParsePagination(c.Query("page"), c.Query("limit"))
+
+	likes, total, err := h.service.GetLikes(actorFromContext(c), c.Query("type"), page, limit)
+	if err != nil {
+		respondLikeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":        page,
+		"limit":       limit,
+		"total_count": total,
+		"data":        likes,
+	})
+}
+
+func (h *LikeHandler) like(c *gin.Context, targetType string) {
+	resp, err := h.service.Like(actorFromContext(c), targetType, c.Param("id"))
+	if err != nil {
+		respondLikeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *LikeHandler) unlike(c *gin.Context, targetType string) {
+	resp, err := h.service.Unlike(actorFromContext(c), targetType, c.Param("id"))
+	if err != nil {
+		respondLikeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func respondLikeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrLikeTargetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidLikeTarget):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LyricsHandler struct {
+	service      services.ILyricsService
+	trackService services.ITrackService
+}
+
+func NewLyricsHandler(service services.ILyricsService, trackService services.ITrackService) *LyricsHandler {
+	return &LyricsHandler{
+		service:      service,
+		trackService: trackService,
+	}
+}
+
+// GetLyrics godoc
+// @Summary      Get track lyrics
+// @Description  Retrieve plain or time-synchronised lyrics. JSON lines carry millisecond offsets; format=lrc returns an LRC file.
+// @Tags         lyrics
+// @Produce      json
+// @Produce      plain
+// @Param        id      path      string  true   "Track ID"
+// @Param        format  query     string  false  "Response format" Enums(json, lrc) Default(json)
+// @Param        lang    query     string  false  "Language code (defaults to the best available)"
+// @Success      200     {object}  dto.LyricsResponse
+// @Failure      400     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Router       /tracks/{id}/lyrics [get]
+func (h *LyricsHandler) GetLyrics(c *gin.Context) {
+	trackID := c.Param("id")
+	language := c.Query("lang")
+
+	switch c.DefaultQuery("format", dto.LyricsFormatJSON) {
+	case dto.LyricsFormatJSON:
+		lyrics, err := h.service.GetLyrics(trackID, language)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, lyrics)
+
+	case dto.LyricsFormatLRC:
+		content, err := h.service.GetLyricsLRC(trackID, language)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"%s.lrc\"", trackID))
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(content))
+
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or lrc"})
+	}
+}
+
+// UpsertLyrics godoc
+// @Summary      Upload track lyrics
+// @Description  Create or replace the lyrics of a track for one language, from an .lrc/.txt file or text
+// @Tags         lyrics
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id        path      string  true   "Track ID"
+// @Param        language  formData  string  false  "Language code, e.g. vi, en (default und)"
+// @Param        text      formData  string  false  "Plain or LRC lyrics"
+// @Param        file      formData  file    false  "LRC or TXT file"
+// @Success      200       {object}  dto.LyricsResponse
+// @Failure      400       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /tracks/{id}/lyrics [put]
+func (h *LyricsHandler) UpsertLyrics(c *gin.Context) {
+	if !h.checkTrackOwner(c, "update") {
+		return
+	}
+
+	var req dto.UpsertLyricsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lyrics, err := h.service.UpsertLyrics(c.Param("id"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lyrics)
+}
+
+// DeleteLyrics godoc
+// @Summary      Delete track lyrics
+// @Description  Delete the lyrics of one language, or all lyrics when lang is omitted
+// @Tags         lyrics
+// @Param        id     path      string  true   "Track ID"
+// @Param        lang   query     string  false  "Language code"
+// @Success      204    "No Content"
+// @Failure      403    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /tracks/{id}/lyrics [delete]
+func (h *LyricsHandler) DeleteLyrics(c *gin.Context) {
+	if !h.checkTrackOwner(c, "delete") {
+		return
+	}
+
+	if err := h.service.DeleteLyrics(c.Param("id"), c.Query("lang")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// checkTrackOwner allows the track's uploader and admins; it writes the error response otherwise.
+func (h *LyricsHandler) checkTrackOwner(c *gin.Context, action string) bool {
+	track, err := h.trackService.GetTrackByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "track not found"})
+		return false
+	}
+
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	if role != "admin" && track.UserID.Hex() != userID.(string) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("you can only %s lyrics of your own tracks", action)})
+		return false
+	}
+	return true
+}
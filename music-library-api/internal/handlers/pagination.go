@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"music-library-api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseCursor reads the opaque "cursor" query parameter; nil means page-number mode.
+// It writes a 400 response and returns false for a malformed token.
+func parseCursor(c *gin.Context) (*utils.Cursor, bool) {
+	token := c.Query("cursor")
+	if token == "" {
+		return nil, true
+	}
+
+	cursor, err := utils.DecodeCursor(token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return cursor, true
+}
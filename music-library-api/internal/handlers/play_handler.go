@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/services"
+	"music-library-api/pkg/constants"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlayHandler struct {
+	service services.IPlayService
+}
+
+func NewPlayHandler(service services.IPlayService) *PlayHandler {
+	return &PlayHandler{service: service}
+}
+
+// Scrobble godoc
+// @Summary      Report a play
+// @Description  Send now_playing when a track starts and played, with the started_at of the play, when it stops. Like Last.fm, a track longer than 30 seconds counts as played after half of it or 4 minutes of listening; shorter plays are accepted with counted=false.
+// @Tags         Plays
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.ScrobbleRequest true "Play event"
+// @Success      200 {object} dto.ScrobbleResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /plays [post]
+func (h *PlayHandler) Scrobble(c *gin.Context) {
+	var req dto.ScrobbleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.Scrobble(c.GetString("user_id"), &req)
+	if err != nil {
+		respondPlayError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetHistory godoc
+// @Summary      Get listening history
+// @Description  Your counted plays, most recent first, with the track you are listening to now if any
+// @Tags         Plays
+// @Produce      json
+// @Param        page   query int false "Page number"
+// @Param        limit  query int false "Page size"
+// @Success      200 {object} map[string]interface{}
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/history [get]
+func (h *PlayHandler) GetHistory(c *gin.Context) {
+	page, limit := constants.ParsePagination(c.Query("page"), c.Query("limit"))
+	userID := c.GetString("user_id")
+
+	plays, total, err := h.service.GetHistory(userID, page, limit)
+	if err != nil {
+		respondPlayError(c, err)
+		return
+	}
+	nowPlaying, err := h.service.GetNowPlaying(userID)
+	if err != nil {
+		respondPlayError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":        page,
+		"limit":       limit,
+		"total_count": total,
+		"now_playing": nowPlaying,
+		"data":        plays,
+	})
+}
+
+func respondPlayError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPlayTrackNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPlayTime), errors.Is(err, services.ErrMissingPlayTime):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queueHeartbeat keeps idle event streams open through proxies.
+const queueHeartbeat = 25 * time.Second
+
+type PlayQueueHandler struct {
+	service services.IPlayQueueService
+}
+
+func NewPlayQueueHandler(service services.IPlayQueueService) *PlayQueueHandler {
+	return &PlayQueueHandler{service: service}
+}
+
+// GetQueue godoc
+// @Summary      Get the play queue
+// @Description  The current track, playback position, shuffle/repeat modes and upcoming tracks, shared by all your devices
+// @Tags         Play Queue
+// @Produce      json
+// @Success      200 {object} dto.PlayQueueResponse
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/queue [get]
+func (h *PlayQueueHandler) GetQueue(c *gin.Context) {
+	resp, err := h.service.GetQueue(c.GetString("user_id"))
+	if err != nil {
+		respondQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// BuildQueue godoc
+// @Summary      Play a playlist, an album or tracks
+// @Description  Replace the queue with the tracks of a playlist, an album or a list of tracks and start playing at start_index. Deleted tracks are skipped.
+// @Tags         Play Queue
+// @Accept       json
+// @Produce      json
+// @Param        X-Device-ID  header  string                     false  "ID of the device making the change"
+// @Param        request      body    dto.BuildPlayQueueRequest  true   "Source"
+// @Success      200 {object} dto.PlayQueueResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/queue [put]
+func (h *PlayQueueHandler) BuildQueue(c *gin.Context) {
+	var req dto.BuildPlayQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.BuildQueue(actorFromContext(c), deviceID(c), &req)
+	if err != nil {
+		respondQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ClearQueue godoc
+// @Summary      Clear the play queue
+// @Tags         Play Queue
+// @Produce      json
+// @Param        X-Device-ID  header  string  false  "ID of the device making the change"
+// @Success      200 {object} dto.PlayQueueResponse
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/queue [delete]
+func (h *PlayQueueHandler) ClearQueue(c *gin.Context) {
+	resp, err := h.service.Clear(c.GetString("user_id"), deviceID(c))
+	if err != nil {
+		respondQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdatePlayback godoc
+// @Summary      Update the playback state
+// @Description  Change the current track, position, play/pause, shuffle or repeat. Omitted fields are left unchanged.
+// @Tags         Play Queue
+// @Accept       json
+// @Produce      json
+// @Param        X-Device-ID  header  string                     false  "ID of the device making the change"
+// @Param        request      body    dto.UpdatePlaybackRequest  true   "Playback state"
+// @Success      200 {object} dto.PlayQueueResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/queue/playback [patch]
+func (h *PlayQueueHandler) UpdatePlayback(c *gin.Context) {
+	var req dto.UpdatePlaybackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.UpdatePlayback(c.GetString("user_id"), deviceID(c), &req)
+	if err != nil {
+		respondQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddToQueue godoc
+// @Summary      Add tracks to the play queue
+// @Description  Append tracks, or insert them right after the current track with next=true
+// @Tags         Play Queue
+// @Accept       json
+// @Produce      json
+// @Param        X-Device-ID  header  string                     false  "ID of the device making the change"
+// @Param        request      body    dto.AddToPlayQueueRequest  true   "Tracks"
+// @Success      200 {object} dto.PlayQueueResponse
+// @Failure      400 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/queue/items [post]
+func (h *PlayQueueHandler) AddToQueue(c *gin.Context) {
+	var req dto.AddToPlayQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.AddTracks(c.GetString("user_id"), deviceID(c), &req)
+	if err != nil {
+		respondQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RemoveFromQueue godoc
+// @Summary      Remove a track from the play queue
+// @Description  Removing the current track moves on to the next one
+// @Tags         Play Queue
+// @Produce      json
+// @Param        X-Device-ID  header  string  false  "ID of the device making the change"
+// @Param        itemId       path    string  true   "Queue item ID"
+// @Success      200 {object} dto.PlayQueueResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/queue/items/{itemId} [delete]
+func (h *PlayQueueHandler) RemoveFromQueue(c *gin.Context) {
+	resp, err := h.service.RemoveItem(c.GetString("user_id"), deviceID(c), c.Param("itemId"))
+	if err != nil {
+		respondQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Next godoc
+// @Summary      Skip to the next track
+// @Description  With repeat all the queue wraps around; otherwise playback stops after the last track
+// @Tags         Play Queue
+// @Produce      json
+// @Param        X-Device-ID  header  string  false  "ID of the device making the change"
+// @Success      200 {object} dto.PlayQueueResponse
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/queue/next [post]
+func (h *PlayQueueHandler) Next(c *gin.Context) {
+	resp, err := h.service.Next(c.GetString("user_id"), deviceID(c))
+	if err != nil {
+		respondQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Previous godoc
+// @Summary      Go back to the previous track
+// @Description  Restarts the current track if it has played for more than 3 seconds
+// @Tags         Play Queue
+// @Produce      json
+// @Param        X-Device-ID  header  string  false  "ID of the device making the change"
+// @Success      200 {object} dto.PlayQueueResponse
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/queue/previous [post]
+func (h *PlayQueueHandler) Previous(c *gin.Context) {
+	resp, err := h.service.Previous(c.GetString("user_id"), deviceID(c))
+	if err != nil {
+		respondQueueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// QueueEvents godoc
+// @Summary      Follow play queue changes
+// @Description  Server-Sent Events stream. A "queue" event carries the full queue on connect and after every change from any device; "ping" events keep the connection open. EventSource clients can pass the token as access_token.
+// @Tags         Play Queue
+// @Produce      text/event-stream
+// @Param        access_token  query  string  false  "JWT, for clients that cannot set the Authorization header"
+// @Success      200 {object} dto.PlayQueueResponse
+// @Failure      401 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/queue/events [get]
+func (h *PlayQueueHandler) QueueEvents(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	// Subscribe before reading the queue so no change is missed in between
+	updates, unsubscribe := h.service.Subscribe(userID)
+	defer unsubscribe()
+
+	initial, err := h.service.GetQueue(userID)
+	if err != nil {
+		respondQueueError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("queue", initial)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(queueHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case q := <-updates:
+			c.SSEvent("queue", q)
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+		case <-c.Request.Context().Done():
+			return false
+		}
+		return true
+	})
+}
+
+// deviceID identifies the device making a change, so that it can recognize
+// its own changes in the event stream.
+func deviceID(c *gin.Context) string {
+	return c.GetHeader("X-Device-ID")
+}
+
+func respondQueueError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPlayQueueItemNotFound), errors.Is(err, services.ErrPlayQueueSourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidTrackIDs), errors.Is(err, services.ErrEmptyPlayQueueSource),
+		errors.Is(err, services.ErrInvalidQueuePosition):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPlayQueueConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
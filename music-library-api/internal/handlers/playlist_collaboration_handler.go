@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+	"music-library-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaylistCollaborationHandler struct {
+	service         services.IPlaylistCollaborationService
+	playlistService services.IPlaylistService
+	authz           services.IAuthorizationService
+}
+
+func NewPlaylistCollaborationHandler(service services.IPlaylistCollaborationService, playlistService services.IPlaylistService, authz services.IAuthorizationService) *PlaylistCollaborationHandler {
+	return &PlaylistCollaborationHandler{service: service, playlistService: playlistService, authz: authz}
+}
+
+// InviteCollaborator godoc
+// @Summary      Invite a playlist collaborator
+// @Description  Invite a user (by ID or email) as editor or viewer. Owner only. The user becomes a collaborator once they accept.
+// @Tags         Playlists
+// @Accept       json
+// @Produce      json
+// @Param        id    path  string                                 true  "Playlist ID"
+// @Param        body  body  dto.InvitePlaylistCollaboratorRequest  true  "Invitee and role"
+// @Success      201 {object} dto.PlaylistInvitationResponse
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/invitations [post]
+func (h *PlaylistCollaborationHandler) InviteCollaborator(c *gin.Context) {
+	var req dto.InvitePlaylistCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleOwner)
+	if !ok {
+		return
+	}
+
+	invitation, err := h.service.Invite(pl, actorFromContext(c), &req)
+	if err != nil {
+		respondCollaborationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// GetPlaylistInvitations godoc
+// @Summary      List pending invitations of a playlist
+// @Tags         Playlists
+// @Produce      json
+// @Param        id  path  string  true  "Playlist ID"
+// @Success      200 {array}  dto.PlaylistInvitationResponse
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/invitations [get]
+func (h *PlaylistCollaborationHandler) GetPlaylistInvitations(c *gin.Context) {
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleOwner)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.service.GetPlaylistInvitations(pl)
+	if err != nil {
+		respondCollaborationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// RevokeInvitation godoc
+// @Summary      Revoke a pending invitation
+// @Tags         Playlists
+// @Param        id            path  string  true  "Playlist ID"
+// @Param        invitationId  path  string  true  "Invitation ID"
+// @Success      204 {string} string "No Content"
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/invitations/{invitationId} [delete]
+func (h *PlaylistCollaborationHandler) RevokeInvitation(c *gin.Context) {
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleOwner)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeInvitation(pl, c.Param("invitationId")); err != nil {
+		respondCollaborationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMyInvitations godoc
+// @Summary      List my pending playlist invitations
+// @Tags         Playlists
+// @Produce      json
+// @Success      200 {array}  dto.PlaylistInvitationResponse
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/invitations [get]
+func (h *PlaylistCollaborationHandler) GetMyInvitations(c *gin.Context) {
+	invitations, err := h.service.GetMyInvitations(actorFromContext(c))
+	if err != nil {
+		respondCollaborationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// AcceptInvitation godoc
+// @Summary      Accept a playlist invitation
+// @Tags         Playlists
+// @Produce      json
+// @Param        invitationId  path  string  true  "Invitation ID"
+// @Success      200 {object} dto.PlaylistInvitationResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/invitations/{invitationId}/accept [post]
+func (h *PlaylistCollaborationHandler) AcceptInvitation(c *gin.Context) {
+	invitation, err := h.service.AcceptInvitation(c.Param("invitationId"), actorFromContext(c))
+	if err != nil {
+		respondCollaborationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
+
+// DeclineInvitation godoc
+// @Summary      Decline a playlist invitation
+// @Tags         Playlists
+// @Produce      json
+// @Param        invitationId  path  string  true  "Invitation ID"
+// @Success      200 {object} dto.PlaylistInvitationResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/invitations/{invitationId}/decline [post]
+func (h *PlaylistCollaborationHandler) DeclineInvitation(c *gin.Context) {
+	invitation, err := h.service.DeclineInvitation(c.Param("invitationId"), actorFromContext(c))
+	if err != nil {
+		respondCollaborationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
+
+// UpdateCollaborator godoc
+// @Summary      Change a collaborator's role
+// @Tags         Playlists
+// @Accept       json
+// @Param        id      path  string                                 true  "Playlist ID"
+// @Param        userId  path  string                                 true  "Collaborator user ID"
+// @Param        body    body  dto.UpdatePlaylistCollaboratorRequest  true  "New role"
+// @Success      204 {string} string "No Content"
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/collaborators/{userId} [patch]
+func (h *PlaylistCollaborationHandler) UpdateCollaborator(c *gin.Context) {
+	var req dto.UpdatePlaylistCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleOwner)
+	if !ok {
+		return
+	}
+
+	if err := h.service.SetCollaboratorRole(pl, c.Param("userId"), req.Role); err != nil {
+		respondCollaborationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveCollaborator godoc
+// @Summary      Remove a collaborator
+// @Description  The owner can remove anyone; a collaborator can remove themselves to leave the playlist.
+// @Tags         Playlists
+// @Param        id      path  string  true  "Playlist ID"
+// @Param        userId  path  string  true  "Collaborator user ID"
+// @Success      204 {string} string "No Content"
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/collaborators/{userId} [delete]
+func (h *PlaylistCollaborationHandler) RemoveCollaborator(c *gin.Context) {
+	need := models.PlaylistRoleOwner
+	if c.Param("userId") == actorFromContext(c).UserID {
+		need = models.PlaylistRoleViewer
+	}
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), need)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveCollaborator(pl, c.Param("userId")); err != nil {
+		respondCollaborationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func respondCollaborationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvitationNotFound),
+		errors.Is(err, services.ErrInviteeNotFound),
+		errors.Is(err, services.ErrCollaboratorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidInvitation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaylistFolderHandler struct {
+	service         services.IPlaylistFolderService
+	playlistService services.IPlaylistService
+	authz           services.IAuthorizationService
+}
+
+func NewPlaylistFolderHandler(service services.IPlaylistFolderService, playlistService services.IPlaylistService, authz services.IAuthorizationService) *PlaylistFolderHandler {
+	return &PlaylistFolderHandler{service: service, playlistService: playlistService, authz: authz}
+}
+
+// CreateFolder godoc
+// @Summary      Create a playlist folder
+// @Description  Create a folder at the top level or inside another folder
+// @Tags         Playlist Folders
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.CreatePlaylistFolderRequest true "Folder"
+// @Success      201 {object} dto.PlaylistFolderResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/folders [post]
+func (h *PlaylistFolderHandler) CreateFolder(c *gin.Context) {
+	var req dto.CreatePlaylistFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	folder, err := h.service.CreateFolder(actorFromContext(c), &req)
+	if err != nil {
+		respondFolderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mappers.ToPlaylistFolderResponse(folder))
+}
+
+// GetTree godoc
+// @Summary      Get the playlist folder tree
+// @Description  All of the user's folders with their playlists, then the playlists in no folder. Each folder has its playlist count and total duration, subfolders included.
+// @Tags         Playlist Folders
+// @Produce      json
+// @Success      200 {object} dto.PlaylistFolderTreeResponse
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/folders/tree [get]
+func (h *PlaylistFolderHandler) GetTree(c *gin.Context) {
+	tree, err := h.service.GetTree(actorFromContext(c))
+	if err != nil {
+		respondFolderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+// RenameFolder godoc
+// @Summary      Rename a playlist folder
+// @Tags         Playlist Folders
+// @Accept       json
+// @Produce      json
+// @Param        folderId  path  string                           true  "Folder ID"
+// @Param        request   body  dto.RenamePlaylistFolderRequest  true  "New name"
+// @Success      200 {object} dto.PlaylistFolderResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/folders/{folderId} [patch]
+func (h *PlaylistFolderHandler) RenameFolder(c *gin.Context) {
+	var req dto.RenamePlaylistFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	folder, err := h.service.RenameFolder(actorFromContext(c), c.Param("folderId"), req.Name)
+	if err != nil {
+		respondFolderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mappers.ToPlaylistFolderResponse(folder))
+}
+
+// MoveFolder godoc
+// @Summary      Move a playlist folder
+// @Description  Move a folder and its content under another folder (or to the top level) and/or to another position among its siblings
+// @Tags         Playlist Folders
+// @Accept       json
+// @Produce      json
+// @Param        folderId  path  string                         true  "Folder ID"
+// @Param        request   body  dto.MovePlaylistFolderRequest  true  "Destination"
+// @Success      200 {object} dto.PlaylistFolderResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/folders/{folderId}/move [post]
+func (h *PlaylistFolderHandler) MoveFolder(c *gin.Context) {
+	var req dto.MovePlaylistFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	folder, err := h.service.MoveFolder(actorFromContext(c), c.Param("folderId"), &req)
+	if err != nil {
+		respondFolderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mappers.ToPlaylistFolderResponse(folder))
+}
+
+// DeleteFolder godoc
+// @Summary      Delete a playlist folder
+// @Description  Delete a folder. Its subfolders and playlists move up to the parent folder; no playlist is deleted.
+// @Tags         Playlist Folders
+// @Produce      json
+// @Param        folderId  path  string  true  "Folder ID"
+// @Success      200 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/folders/{folderId} [delete]
+func (h *PlaylistFolderHandler) DeleteFolder(c *gin.Context) {
+	if err := h.service.DeleteFolder(actorFromContext(c), c.Param("folderId")); err != nil {
+		respondFolderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "folder deleted"})
+}
+
+// MovePlaylist godoc
+// @Summary      Move a playlist to a folder
+// @Description  File a playlist into one of your folders, or take it out of its folder with an empty folder_id
+// @Tags         Playlist Folders
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                           true  "Playlist ID"
+// @Param        request  body  dto.MovePlaylistToFolderRequest  true  "Destination"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/folder [put]
+func (h *PlaylistFolderHandler) MovePlaylist(c *gin.Context) {
+	var req dto.MovePlaylistToFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleViewer)
+	if !ok {
+		return
+	}
+
+	if err := h.service.MovePlaylist(actorFromContext(c), pl, &req); err != nil {
+		respondFolderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "playlist moved"})
+}
+
+func respondFolderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPlaylistFolderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidFolderMove):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/services"
+	"music-library-api/pkg/constants"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaylistFollowHandler struct {
+	service         services.IPlaylistFollowService
+	playlistService services.IPlaylistService
+	authz           services.IAuthorizationService
+}
+
+func NewPlaylistFollowHandler(service services.IPlaylistFollowService, playlistService services.IPlaylistService, authz services.IAuthorizationService) *PlaylistFollowHandler {
+	return &PlaylistFollowHandler{service: service, playlistService: playlistService, authz: authz}
+}
+
+// FollowPlaylist godoc
+// @Summary      Follow a playlist
+// @Description  Add a playlist you can see to your followed playlists. Following twice is a no-op.
+// @Tags         Playlists
+// @Produce      json
+// @Param        id  path  string  true  "Playlist ID"
+// @Success      200 {object} dto.FollowPlaylistResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/follow [post]
+func (h *PlaylistFollowHandler) FollowPlaylist(c *gin.Context) {
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleViewer)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Follow(pl, actorFromContext(c))
+	if err != nil {
+		respondFollowError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UnfollowPlaylist godoc
+// @Summary      Unfollow a playlist
+// @Tags         Playlists
+// @Produce      json
+// @Param        id  path  string  true  "Playlist ID"
+// @Success      200 {object} dto.FollowPlaylistResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/follow [delete]
+func (h *PlaylistFollowHandler) UnfollowPlaylist(c *gin.Context) {
+	pl, err := h.playlistService.GetPlaylistByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "playlist not found"})
+		return
+	}
+
+	// No access check: users can always stop following a playlist
+	resp, err := h.service.Unfollow(pl, actorFromContext(c))
+	if err != nil {
+		respondFollowError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetFollowedPlaylists godoc
+// @Summary      List followed playlists
+// @Description  Playlists the user follows, most recently followed first
+// @Tags         Playlists
+// @Produce      json
+// @Param        page   query int false "Page number"
+// @Param        limit  query int false "Page size"
+// @Success      200 {object} map[string]interface{}
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/followed [get]
+func (h *PlaylistFollowHandler) GetFollowedPlaylists(c *gin.Context) {
+	page, limit := constants.ParsePagination(c.Query("page"), c.Query("limit"))
+
+	playlists, total, err := h.service.GetFollowedPlaylists(actorFromContext(c), page, limit)
+	if err != nil {
+		respondFollowError(c, err)
+		return
+	}
+
+	resp := make([]dto.PlaylistResponse, len(playlists))
+	for i, p := range playlists {
+		resp[i] = mappers.ToPlaylistResponse(p)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":        page,
+		"limit":       limit,
+		"total_count": total,
+		"data":        resp,
+	})
+}
+
+func respondFollowError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrFollowOwnPlaylist):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -4,32 +4,40 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"music-library-api/internal/dto"
 	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
 	"music-library-api/internal/services"
 	"music-library-api/pkg/constants"
+	"music-library-api/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type PlaylistHandler struct {
-	service services.IPlaylistService
+	service        services.IPlaylistService
+	authz          services.IAuthorizationService
+	publicBaseURL  string               // empty = derive from the request
+	trustedProxies utils.TrustedProxies // peers whose X-Forwarded-* headers are believed
 }
 
-func NewPlaylistHandler(service services.IPlaylistService) *PlaylistHandler {
-	return &PlaylistHandler{service: service}
+func NewPlaylistHandler(service services.IPlaylistService, authz services.IAuthorizationService, publicBaseURL string, trustedProxies utils.TrustedProxies) *PlaylistHandler {
+	return &PlaylistHandler{service: service, authz: authz, publicBaseURL: publicBaseURL, trustedProxies: trustedProxies}
 }
 
 // @Summary      Get all playlists
-// @Description  Retrieve paginated playlists
+// @Description  Retrieve paginated public playlists, or with myPlaylists=true the ones the user owns or collaborates on
 // @Tags         Playlists
 // @Accept       json
 // @Produce      json
 // @Param        page  query int false "Page number"
 // @Param        limit query int false "Page size"
+// @Param        cursor query string false "Opaque cursor from next_cursor/prev_cursor; replaces page"
 // @Success      200 {object} map[string]interface{}
 // @Router       /playlists [get]
 func (h *PlaylistHandler) GetPlaylists(c *gin.Context) {
@@ -45,25 +53,48 @@ func (h *PlaylistHandler) GetPlaylists(c *gin.Context) {
 		uid = userID.(string)
 	}
 
-	playlists, err := h.service.GetPlaylists(page, limit, uid)
+	// Unlisted and private playlists only show up in the user's own listing
+	filter := models.PlaylistFilter{MemberID: uid, PublicOnly: uid == ""}
+
+	cursor, ok := parseCursor(c)
+	if !ok {
+		return
+	}
+
+	var playlists []*models.Playlist
+	var cursors utils.CursorPage
+	var err error
+	if cursor != nil {
+		playlists, cursors, err = h.service.GetPlaylistsByCursor(cursor, limit, filter)
+	} else {
+		playlists, err = h.service.GetPlaylists(page, limit, filter)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	totalCount, _ := h.service.CountPlaylists(uid)
+	totalCount, _ := h.service.CountPlaylists(filter)
+	if cursor == nil {
+		cursors = utils.NewCursorPage(playlists, page > 1, int64((page-1)*limit+len(playlists)) < totalCount)
+	}
 
 	resp := make([]dto.PlaylistResponse, len(playlists))
 	for i, p := range playlists {
 		resp[i] = mappers.ToPlaylistResponse(p)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"page":        page,
+	body := gin.H{
 		"limit":       limit,
 		"total_count": totalCount,
 		"data":        resp,
-	})
+		"next_cursor": cursors.NextCursor,
+		"prev_cursor": cursors.PrevCursor,
+	}
+	if cursor == nil {
+		body["page"] = page
+	}
+	c.JSON(http.StatusOK, body)
 }
 
 // @Summary      Create playlist
@@ -106,19 +137,76 @@ func (h *PlaylistHandler) CreatePlaylist(c *gin.Context) {
 // @Tags         Playlists
 // @Accept       json
 // @Produce      json
-// @Param        id path string true "Playlist ID"
-// @Success      200 {object} dto.PlaylistResponse
+// @Param        id     path  string true  "Playlist ID"
+// @Param        expand query string false "Set to \"tracks\" to include ordered track details" Enums(tracks)
+// @Param        share  query string false "Share link token, for private playlists"
+// @Success      200 {object} dto.PlaylistResponse "dto.ExpandedPlaylistResponse when expand=tracks"
+// @Failure      400 {object} map[string]string
 // @Failure      404 {object} map[string]string
 // @Router       /playlists/{id} [get]
 func (h *PlaylistHandler) GetPlaylistByID(c *gin.Context) {
 	idStr := c.Param("id")
 
-	pl, err := h.service.GetPlaylistByID(idStr)
+	pl, ok := authorizedPlaylist(c, h.service, h.authz, idStr, models.PlaylistRoleViewer)
+	if !ok {
+		return
+	}
+
+	switch c.Query("expand") {
+	case "":
+	case "tracks":
+		expanded, err := h.service.GetExpandedPlaylist(idStr)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		setPlaylistETag(c, &expanded.Playlist)
+		c.JSON(http.StatusOK, mappers.ToExpandedPlaylistResponse(expanded))
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expand must be \"tracks\""})
+		return
+	}
+
+	setPlaylistETag(c, pl)
+	c.JSON(http.StatusOK, mappers.ToPlaylistResponse(pl))
+}
+
+// @Summary      Get Liked Songs
+// @Description  The user's liked tracks, most recently liked first, as a private playlist. It is created on first use
+// @Description  and can be shared, exported and streamed like any playlist, but its tracks change only through likes.
+// @Tags         Playlists
+// @Produce      json
+// @Param        expand query string false "Set to \"tracks\" to include ordered track details" Enums(tracks)
+// @Success      200 {object} dto.PlaylistResponse "dto.ExpandedPlaylistResponse when expand=tracks"
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/liked [get]
+func (h *PlaylistHandler) GetLikedSongs(c *gin.Context) {
+	pl, err := h.service.GetLikedSongs(actorFromContext(c).UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondPlaylistError(c, err)
 		return
 	}
 
+	switch c.Query("expand") {
+	case "":
+	case "tracks":
+		expanded, err := h.service.GetExpandedPlaylist(pl.ID.Hex())
+		if err != nil {
+			respondPlaylistError(c, err)
+			return
+		}
+		setPlaylistETag(c, &expanded.Playlist)
+		c.JSON(http.StatusOK, mappers.ToExpandedPlaylistResponse(expanded))
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expand must be \"tracks\""})
+		return
+	}
+
+	setPlaylistETag(c, pl)
 	c.JSON(http.StatusOK, mappers.ToPlaylistResponse(pl))
 }
 
@@ -132,9 +220,11 @@ func (h *PlaylistHandler) GetPlaylistByID(c *gin.Context) {
 // @Param        album_cover  formData file   false "Album cover image"
 // @Param        track_ids    formData []string false "Track IDs, comma separated"
 // @Param        mode         formData string false "Track update mode" Enums(append, overwrite) Default(append)
+// @Param        If-Match     header   string false "Playlist version (ETag) the edit is based on"
 // @Success      200 {object} dto.PlaylistResponse
 // @Failure      400 {object} map[string]string
 // @Failure      404 {object} map[string]string
+// @Failure      412 {object} map[string]string
 // @Failure      500 {object} map[string]string
 // @Security     BearerAuth
 // @Router       /playlists/{id} [patch]
@@ -147,30 +237,33 @@ func (h *PlaylistHandler) UpdatePlaylist(c *gin.Context) {
 		return
 	}
 
-	// Ownership check: only owner or admin can update
-	userID, _ := c.Get("user_id")
-	role, _ := c.Get("role")
-	pl, err := h.service.GetPlaylistByID(idStr)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "playlist not found"})
-		return
+	// Owner, admin or editor; only the owner can change who can see it
+	need := models.PlaylistRoleEditor
+	if req.Visibility != "" {
+		need = models.PlaylistRoleOwner
 	}
-	if role != "admin" && pl.UserID.Hex() != userID.(string) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you can only update your own playlists"})
+	if _, ok := authorizedPlaylist(c, h.service, h.authz, idStr, need); !ok {
 		return
 	}
 
-	// Gọi service update, service sẽ handle upload file + trackIDs
-	updatedPlaylist, err := h.service.UpdatePlaylistFormData(idStr, &req)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "playlist not found"})
+	// If-Match is optional here so existing clients keep working
+	var expected *int64
+	if c.GetHeader("If-Match") != "" {
+		version, ok := ifMatchVersion(c)
+		if !ok {
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		expected = &version
+	}
+
+	// Gọi service update, service sẽ handle upload file + trackIDs
+	updatedPlaylist, err := h.service.UpdatePlaylistFormData(idStr, actorFromContext(c).UserID, expected, &req)
+	if err != nil {
+		respondPlaylistError(c, err)
 		return
 	}
 
+	setPlaylistETag(c, updatedPlaylist)
 	c.JSON(http.StatusOK, mappers.ToPlaylistResponse(updatedPlaylist))
 }
 
@@ -181,27 +274,20 @@ func (h *PlaylistHandler) UpdatePlaylist(c *gin.Context) {
 // @Produce      json
 // @Param        id path string true "Playlist ID"
 // @Success      204 {string} string "No Content"
+// @Failure      409 {object} map[string]string "Liked Songs cannot be deleted"
 // @Failure      500 {object} map[string]string
 // @Security     BearerAuth
 // @Router       /playlists/{id} [delete]
 func (h *PlaylistHandler) DeletePlaylist(c *gin.Context) {
 	idStr := c.Param("id")
 
-	// Ownership check: only owner or admin can delete
-	userID, _ := c.Get("user_id")
-	role, _ := c.Get("role")
-	pl, err := h.service.GetPlaylistByID(idStr)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "playlist not found"})
-		return
-	}
-	if role != "admin" && pl.UserID.Hex() != userID.(string) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you can only delete your own playlists"})
+	// Only the owner or an admin can delete, collaborators cannot
+	if _, ok := authorizedPlaylist(c, h.service, h.authz, idStr, models.PlaylistRoleOwner); !ok {
 		return
 	}
 
 	if err := h.service.DeletePlaylist(idStr); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondPlaylistError(c, err)
 		return
 	}
 
@@ -209,37 +295,562 @@ func (h *PlaylistHandler) DeletePlaylist(c *gin.Context) {
 }
 
 // @Summary      Stream playlist as M3U
-// @Description  Streams an M3U playlist file containing URLs to the MP3 tracks.
+// @Description  Download the playlist for external players (VLC, foobar2000, mpv...). Track URLs are absolute.
+// @Description  The format comes from ?format=, else from an extension on the ID (e.g. {id}.xspf), else M3U8.
 // @Tags         Playlists
 // @Produce      audio/x-mpegurl
-// @Param        id   path     string  true  "Playlist ID"
-// @Success      200  {string}  string  "M3U playlist content"
+// @Param        id      path   string  true   "Playlist ID, optionally with a format extension"
+// @Param        format  query  string  false  "Export format" Enums(m3u8, m3u, pls, xspf, jspf)
+// @Param        share   query  string  false  "Share link token, for private playlists"
+// @Success      200  {string}  string  "Playlist file"
+// @Failure      400  {object}  map[string]string
 // @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /playlists/{id}/stream [get]
 func (h *PlaylistHandler) StreamPlaylistM3U(c *gin.Context) {
-	playlistID := c.Param("id")
+	playlistID, ext := splitExtension(c.Param("id"))
+	h.exportPlaylist(c, playlistID, c.DefaultQuery("format", ext))
+}
 
-	// The base path for the single track streaming endpoint.
-	// This must match the endpoint defined in your track routes (e.g., /api/tracks/{id}/stream).
-	const trackStreamBaseURL = "/api/tracks"
+// ExportPlaylist godoc
+// @Summary      Export playlist
+// @Description  Download the playlist as a file whose name picks the format, e.g. /export/road-trip.xspf
+// @Tags         Playlists
+// @Produce      audio/x-mpegurl
+// @Param        id        path   string  true   "Playlist ID"
+// @Param        filename  path   string  true   "File name ending in .m3u8, .m3u, .pls, .xspf or .jspf"
+// @Param        format    query  string  false  "Overrides the extension" Enums(m3u8, m3u, pls, xspf, jspf)
+// @Param        share     query  string  false  "Share link token, for private playlists"
+// @Success      200  {string}  string  "Playlist file"
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /playlists/{id}/export/{filename} [get]
+func (h *PlaylistHandler) ExportPlaylist(c *gin.Context) {
+	_, ext := splitExtension(c.Param("filename"))
+	h.exportPlaylist(c, c.Param("id"), c.DefaultQuery("format", ext))
+}
+
+// playlistContentTypes maps export formats to their MIME types.
+var playlistContentTypes = map[string]string{
+	dto.PlaylistFormatM3U8: "audio/x-mpegurl; charset=utf-8",
+	dto.PlaylistFormatM3U:  "audio/x-mpegurl; charset=utf-8",
+	dto.PlaylistFormatPLS:  "audio/x-scpls; charset=utf-8",
+	dto.PlaylistFormatXSPF: "application/xspf+xml; charset=utf-8",
+	dto.PlaylistFormatJSPF: "application/jspf+json; charset=utf-8",
+}
+
+func (h *PlaylistHandler) exportPlaylist(c *gin.Context, playlistID, format string) {
+	format = strings.ToLower(format)
+	if format == "" {
+		format = dto.PlaylistFormatM3U8
+	}
+	contentType, ok := playlistContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of m3u8, m3u, pls, xspf, jspf"})
+		return
+	}
+	if _, ok := authorizedPlaylist(c, h.service, h.authz, playlistID, models.PlaylistRoleViewer); !ok {
+		return
+	}
 
-	fmt.Println("trackStreamBaseURL", trackStreamBaseURL)
-	m3uContent, err := h.service.StreamPlaylistM3U(playlistID, trackStreamBaseURL)
+	content, err := h.service.ExportPlaylist(playlistID, format, h.baseURL(c))
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "playlist not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to generate M3U playlist: %v", err)})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to export playlist: %v", err)})
 		return
 	}
 
-	// 1. Set the correct MIME type for M3U playlist files
-	c.Header("Content-Type", "audio/x-mpegurl")
-	// 2. Set Content-Disposition to attachment to suggest a filename for download
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"playlist_%s.m3u\"", playlistID))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"playlist_%s.%s\"", playlistID, format))
+	c.Data(http.StatusOK, contentType, []byte(content))
+}
 
-	// 3. Write the M3U content directly as the HTTP response body
-	c.String(http.StatusOK, m3uContent)
+// baseURL is the configured public URL, or the origin the client used to
+// reach us. X-Forwarded-* headers are only honoured when the request comes
+// straight from a trusted proxy; anyone else could point links elsewhere.
+func (h *PlaylistHandler) baseURL(c *gin.Context) string {
+	if h.publicBaseURL != "" {
+		return h.publicBaseURL
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	host := c.Request.Host
+	if h.trustedProxies.Contains(c.RemoteIP()) {
+		if proto := strings.TrimSpace(strings.Split(c.GetHeader("X-Forwarded-Proto"), ",")[0]); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if fwd := c.GetHeader("X-Forwarded-Host"); fwd != "" {
+			host = strings.TrimSpace(strings.Split(fwd, ",")[0])
+		}
+	}
+	return scheme + "://" + host
+}
+
+// splitExtension splits "abc.xspf" into "abc" and "xspf".
+func splitExtension(name string) (string, string) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return name, ""
+	}
+	return name[:i], name[i+1:]
+}
+
+// CreateSmartPlaylist godoc
+// @Summary      Create a smart playlist
+// @Description  Create a playlist whose tracks come from rules, evaluated on every read, stream and export.
+// @Description  Fields: title, artist, album, genre (is, is_not, contains, not_contains, starts_with, ends_with, in, not_in; accent-insensitive),
+// @Description  tag, license, isrc, uploader (is, is_not, in, not_in), release_year, duration, play_count (is, is_not, gt, gte, lt, lte, between),
+// @Description  created_at (before, after, in_last days) and explicit (is). Groups combine rules with "all" or "any".
+// @Tags         Playlists
+// @Accept       json
+// @Produce      json
+// @Param        body  body  dto.CreateSmartPlaylistRequest  true  "Title, rules, sort and limit"
+// @Success      201 {object} dto.PlaylistResponse
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/smart [post]
+func (h *PlaylistHandler) CreateSmartPlaylist(c *gin.Context) {
+	var req dto.CreateSmartPlaylistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	pl, err := h.service.CreateSmartPlaylist(userID.(string), &req)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	setPlaylistETag(c, pl)
+	c.JSON(http.StatusCreated, mappers.ToPlaylistResponse(pl))
+}
+
+// UpdateSmartPlaylist godoc
+// @Summary      Update smart playlist rules
+// @Description  Replace the rules, sort and limit of a smart playlist
+// @Tags         Playlists
+// @Accept       json
+// @Produce      json
+// @Param        id        path    string                          true  "Playlist ID"
+// @Param        If-Match  header  string                          true  "Playlist version (ETag)"
+// @Param        body      body    dto.UpdateSmartPlaylistRequest  true  "Rules, sort and limit"
+// @Success      200 {object} dto.PlaylistResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Failure      428 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/smart [put]
+func (h *PlaylistHandler) UpdateSmartPlaylist(c *gin.Context) {
+	var req dto.UpdateSmartPlaylistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.editPlaylist(c, func(id string, version int64) (*models.Playlist, error) {
+		return h.service.UpdateSmartPlaylist(id, actorFromContext(c).UserID, version, &req)
+	})
+}
+
+// ForkPlaylist godoc
+// @Summary      Fork a playlist
+// @Description  Create an owned copy of a playlist you can see. The fork records its source and can pull its later changes.
+// @Tags         Playlists
+// @Accept       json
+// @Produce      json
+// @Param        id    path  string                   true   "Source playlist ID"
+// @Param        body  body  dto.ForkPlaylistRequest  false  "Title and visibility of the fork"
+// @Success      201 {object} dto.PlaylistResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/fork [post]
+func (h *PlaylistHandler) ForkPlaylist(c *gin.Context) {
+	var req dto.ForkPlaylistRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	source, ok := authorizedPlaylist(c, h.service, h.authz, c.Param("id"), models.PlaylistRoleViewer)
+	if !ok {
+		return
+	}
+
+	fork, err := h.service.ForkPlaylist(source, actorFromContext(c).UserID, &req)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	setPlaylistETag(c, fork)
+	c.JSON(http.StatusCreated, mappers.ToPlaylistResponse(fork))
+}
+
+// PullFork godoc
+// @Summary      Pull changes from a fork's source
+// @Description  Apply the tracks added to and removed from the source since the fork was created or last pulled.
+// @Description  Changes made to the fork itself are kept.
+// @Tags         Playlists
+// @Produce      json
+// @Param        id        path    string  true  "Fork playlist ID"
+// @Param        If-Match  header  string  true  "Fork version (ETag)"
+// @Success      200 {object} dto.PullForkResponse
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Failure      428 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/pull [post]
+func (h *PlaylistHandler) PullFork(c *gin.Context) {
+	idStr := c.Param("id")
+	fork, ok := authorizedPlaylist(c, h.service, h.authz, idStr, models.PlaylistRoleEditor)
+	if !ok {
+		return
+	}
+	if fork.ForkedFrom == nil {
+		respondPlaylistError(c, services.ErrNotForked)
+		return
+	}
+
+	source, err := h.service.GetPlaylistByID(fork.ForkedFrom.PlaylistID.Hex())
+	if err != nil || h.authz.PlaylistAccess(source, actorFromContext(c)) == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "the source playlist was deleted or is no longer shared with you"})
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	updated, added, removed, err := h.service.PullFork(idStr, actorFromContext(c).UserID, version, source)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	setPlaylistETag(c, updated)
+	c.JSON(http.StatusOK, dto.PullForkResponse{
+		Playlist: mappers.ToPlaylistResponse(updated),
+		Added:    utils.ConvertToHexIDs(added),
+		Removed:  utils.ConvertToHexIDs(removed),
+	})
+}
+
+// CreateShareLink godoc
+// @Summary      Create a share link
+// @Description  Create a read-only link to the playlist that works without logging in, even when it is private.
+// @Description  Owner only. The link stops working when it expires or is revoked.
+// @Tags         Playlists
+// @Accept       json
+// @Produce      json
+// @Param        id    path  string                              true   "Playlist ID"
+// @Param        body  body  dto.CreatePlaylistShareLinkRequest  false  "Optional expiry"
+// @Success      201 {object} dto.PlaylistShareLinkResponse
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/shares [post]
+func (h *PlaylistHandler) CreateShareLink(c *gin.Context) {
+	var req dto.CreatePlaylistShareLinkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	idStr := c.Param("id")
+	if _, ok := authorizedPlaylist(c, h.service, h.authz, idStr, models.PlaylistRoleOwner); !ok {
+		return
+	}
+
+	pl, link, err := h.service.CreateShareLink(idStr, actorFromContext(c).UserID, req.ExpiresAt)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mappers.ToPlaylistShareLinkResponse(pl, link, h.baseURL(c)))
+}
+
+// GetShareLinks godoc
+// @Summary      List share links
+// @Description  Owner only; expired links are listed until revoked.
+// @Tags         Playlists
+// @Produce      json
+// @Param        id  path  string  true  "Playlist ID"
+// @Success      200 {array}  dto.PlaylistShareLinkResponse
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/shares [get]
+func (h *PlaylistHandler) GetShareLinks(c *gin.Context) {
+	pl, ok := authorizedPlaylist(c, h.service, h.authz, c.Param("id"), models.PlaylistRoleOwner)
+	if !ok {
+		return
+	}
+
+	baseURL := h.baseURL(c)
+	resp := make([]dto.PlaylistShareLinkResponse, len(pl.ShareLinks))
+	for i := range pl.ShareLinks {
+		resp[i] = mappers.ToPlaylistShareLinkResponse(pl, &pl.ShareLinks[i], baseURL)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeShareLink godoc
+// @Summary      Revoke a share link
+// @Tags         Playlists
+// @Param        id       path  string  true  "Playlist ID"
+// @Param        shareId  path  string  true  "Share link ID"
+// @Success      204 {string} string "No Content"
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/shares/{shareId} [delete]
+func (h *PlaylistHandler) RevokeShareLink(c *gin.Context) {
+	idStr := c.Param("id")
+	if _, ok := authorizedPlaylist(c, h.service, h.authz, idStr, models.PlaylistRoleOwner); !ok {
+		return
+	}
+
+	if err := h.service.RevokeShareLink(idStr, c.Param("shareId")); err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// InsertEntries godoc
+// @Summary      Insert tracks into a playlist
+// @Description  Insert tracks at a 0-based position (default: the end). A track may appear several times.
+// @Tags         Playlists
+// @Accept       json
+// @Produce      json
+// @Param        id        path    string                             true  "Playlist ID"
+// @Param        If-Match  header  string                             true  "Playlist version (ETag)"
+// @Param        body      body    dto.InsertPlaylistEntriesRequest   true  "Tracks and position"
+// @Success      200 {object} dto.PlaylistResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Failure      428 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/entries [post]
+func (h *PlaylistHandler) InsertEntries(c *gin.Context) {
+	var req dto.InsertPlaylistEntriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.editPlaylist(c, func(id string, version int64) (*models.Playlist, error) {
+		return h.service.InsertEntries(id, actorFromContext(c).UserID, version, req.TrackIDs, req.Position)
+	})
+}
+
+// MoveEntry godoc
+// @Summary      Move a playlist entry
+// @Description  Move one entry to a 0-based position; its entry ID is kept
+// @Tags         Playlists
+// @Accept       json
+// @Produce      json
+// @Param        id        path    string                        true  "Playlist ID"
+// @Param        entryId   path    string                        true  "Entry ID"
+// @Param        If-Match  header  string                        true  "Playlist version (ETag)"
+// @Param        body      body    dto.MovePlaylistEntryRequest  true  "Target position"
+// @Success      200 {object} dto.PlaylistResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Failure      428 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/entries/{entryId} [patch]
+func (h *PlaylistHandler) MoveEntry(c *gin.Context) {
+	var req dto.MovePlaylistEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.editPlaylist(c, func(id string, version int64) (*models.Playlist, error) {
+		return h.service.MoveEntry(id, actorFromContext(c).UserID, version, c.Param("entryId"), *req.Position)
+	})
+}
+
+// RemoveEntry godoc
+// @Summary      Remove a playlist entry
+// @Description  Remove one entry by its ID; other copies of the same track stay
+// @Tags         Playlists
+// @Produce      json
+// @Param        id        path    string  true  "Playlist ID"
+// @Param        entryId   path    string  true  "Entry ID"
+// @Param        If-Match  header  string  true  "Playlist version (ETag)"
+// @Success      200 {object} dto.PlaylistResponse
+// @Failure      404 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Failure      428 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/entries/{entryId} [delete]
+func (h *PlaylistHandler) RemoveEntry(c *gin.Context) {
+	h.editPlaylist(c, func(id string, version int64) (*models.Playlist, error) {
+		return h.service.RemoveEntry(id, actorFromContext(c).UserID, version, c.Param("entryId"))
+	})
+}
+
+// ReverseEntries godoc
+// @Summary      Reverse a playlist
+// @Tags         Playlists
+// @Produce      json
+// @Param        id        path    string  true  "Playlist ID"
+// @Param        If-Match  header  string  true  "Playlist version (ETag)"
+// @Success      200 {object} dto.PlaylistResponse
+// @Failure      404 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Failure      428 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/entries/reverse [post]
+func (h *PlaylistHandler) ReverseEntries(c *gin.Context) {
+	h.editPlaylist(c, func(id string, version int64) (*models.Playlist, error) {
+		return h.service.ReverseEntries(id, actorFromContext(c).UserID, version)
+	})
+}
+
+// SortEntries godoc
+// @Summary      Sort a playlist
+// @Description  Sort entries by title, artist, album, duration, release_year or added_at (stable; deleted tracks last)
+// @Tags         Playlists
+// @Accept       json
+// @Produce      json
+// @Param        id        path    string                   true  "Playlist ID"
+// @Param        If-Match  header  string                   true  "Playlist version (ETag)"
+// @Param        body      body    dto.SortPlaylistRequest  true  "Sort field and order"
+// @Success      200 {object} dto.PlaylistResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Failure      428 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/entries/sort [post]
+func (h *PlaylistHandler) SortEntries(c *gin.Context) {
+	var req dto.SortPlaylistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.editPlaylist(c, func(id string, version int64) (*models.Playlist, error) {
+		return h.service.SortEntries(id, actorFromContext(c).UserID, version, req.Field, req.Order == "desc")
+	})
+}
+
+// editPlaylist runs the editor access and If-Match checks shared by the edit endpoints.
+func (h *PlaylistHandler) editPlaylist(c *gin.Context, edit func(id string, version int64) (*models.Playlist, error)) {
+	idStr := c.Param("id")
+
+	if _, ok := authorizedPlaylist(c, h.service, h.authz, idStr, models.PlaylistRoleEditor); !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	updated, err := edit(idStr, version)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	setPlaylistETag(c, updated)
+	c.JSON(http.StatusOK, mappers.ToPlaylistResponse(updated))
+}
+
+// actorFromContext returns the user set by the auth middleware, if any, and
+// the share token from ?share= or the X-Share-Token header.
+func actorFromContext(c *gin.Context) services.Actor {
+	token := c.Query("share")
+	if token == "" {
+		token = c.GetHeader("X-Share-Token")
+	}
+	return services.Actor{UserID: c.GetString("user_id"), Role: c.GetString("role"), ShareToken: token}
+}
+
+// authorizedPlaylist loads a playlist and checks the caller has at least the
+// needed role on it, writing the error response otherwise. Playlists the
+// caller cannot see at all are reported as not found.
+func authorizedPlaylist(c *gin.Context, service services.IPlaylistService, authz services.IAuthorizationService, id, need string) (*models.Playlist, bool) {
+	pl, err := service.GetPlaylistByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "playlist not found"})
+		return nil, false
+	}
+	actor := actorFromContext(c)
+	if authz.PlaylistAccess(pl, actor) == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "playlist not found"})
+		return nil, false
+	}
+	if err := authz.AuthorizePlaylist(pl, actor, need); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return pl, true
+}
+
+// ifMatchVersion reads the playlist version from If-Match, e.g. `"3"` or `W/"3"`.
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header with the playlist version is required"})
+		return 0, false
+	}
+
+	value := strings.Trim(strings.TrimPrefix(strings.TrimSpace(header), "W/"), `"`)
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header"})
+		return 0, false
+	}
+	return version, true
+}
+
+func setPlaylistETag(c *gin.Context, pl *models.Playlist) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, pl.Version))
+}
+
+func respondPlaylistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(http.StatusNotFound, gin.H{"error": "playlist not found"})
+	case errors.Is(err, services.ErrPlaylistEntryNotFound), errors.Is(err, services.ErrShareLinkNotFound),
+		errors.Is(err, services.ErrPlaylistVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPlaylistVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSmartPlaylistReadOnly), errors.Is(err, services.ErrNotSmartPlaylist),
+		errors.Is(err, services.ErrNotForked), errors.Is(err, services.ErrLikedSongsReadOnly):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPlaylistPosition), errors.Is(err, services.ErrInvalidTrackIDs),
+		errors.Is(err, services.ErrInvalidSmartRules), errors.Is(err, services.ErrInvalidShareLink):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
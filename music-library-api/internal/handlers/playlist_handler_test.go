@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"music-library-api/pkg/utils"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPlaylistHandlerBaseURL(t *testing.T) {
+	proxies, _ := utils.ParseTrustedProxies([]string{"10.0.0.1"})
+
+	tests := []struct {
+		name          string
+		publicBaseURL string
+		remoteAddr    string
+		headers       map[string]string
+		want          string
+	}{
+		{
+			name:          "configured public URL wins",
+			publicBaseURL: "https://music.example.com",
+			remoteAddr:    "10.0.0.1:5000",
+			headers:       map[string]string{"X-Forwarded-Host": "evil.example"},
+			want:          "https://music.example.com",
+		},
+		{
+			name:       "forwarded headers from a trusted proxy",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Forwarded-Host": "music.example.com, inner", "X-Forwarded-Proto": "https"},
+			want:       "https://music.example.com",
+		},
+		{
+			name:       "forwarded headers from anyone else are ignored",
+			remoteAddr: "203.0.113.9:5000",
+			headers:    map[string]string{"X-Forwarded-Host": "evil.example", "X-Forwarded-Proto": "https"},
+			want:       "http://api.local",
+		},
+		{
+			name:       "unknown forwarded scheme is ignored",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Forwarded-Proto": "javascript"},
+			want:       "http://api.local",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "http://api.local/api/playlists/x/export", nil)
+			c.Request.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				c.Request.Header.Set(k, v)
+			}
+
+			h := NewPlaylistHandler(nil, nil, tt.publicBaseURL, proxies)
+			if got := h.baseURL(c); got != tt.want {
+				t.Errorf("baseURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
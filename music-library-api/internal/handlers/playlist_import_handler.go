@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"music-library-api/internal/dto"
+	"music-library-api/internal/services"
+	"music-library-api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxPlaylistFileSize = 5 << 20 // 5 MB
+
+type PlaylistImportHandler struct {
+	service services.IPlaylistImportService
+}
+
+func NewPlaylistImportHandler(service services.IPlaylistImportService) *PlaylistImportHandler {
+	return &PlaylistImportHandler{service: service}
+}
+
+// ImportPlaylist godoc
+// @Summary      Import a playlist file
+// @Description  Create a playlist from an M3U/M3U8, PLS, XSPF or CSV file. Entries are matched by file path or name,
+// @Description  then artist and title, then title and duration. The report lists matched, ambiguous and unmatched entries.
+// @Tags         Playlists
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file   formData  file    true   "Playlist file (.m3u, .m3u8, .pls, .xspf, .csv)"
+// @Param        title  formData  string  false  "Playlist title (default: from the file)"
+// @Success      201 {object} dto.PlaylistImportResponse
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/import [post]
+func (h *PlaylistImportHandler) ImportPlaylist(c *gin.Context) {
+	var req dto.ImportPlaylistRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.File.Size > maxPlaylistFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "playlist file is too large (max 5 MB)"})
+		return
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open file"})
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxPlaylistFileSize))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	report, err := h.service.ImportPlaylist(userID.(string), req.File.Filename, content, req.Title)
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GetImport godoc
+// @Summary      Get a playlist import report
+// @Tags         Playlists
+// @Produce      json
+// @Param        importId  path  string  true  "Import ID"
+// @Success      200 {object} dto.PlaylistImportResponse
+// @Failure      404 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/imports/{importId} [get]
+func (h *PlaylistImportHandler) GetImport(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	report, err := h.service.GetImport(c.Param("importId"), userID.(string))
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ResolveImport godoc
+// @Summary      Resolve ambiguous import entries
+// @Description  Pick a track for ambiguous or unmatched entries (an empty track_id dismisses the entry).
+// @Description  Picked tracks are inserted next to their neighbours from the file.
+// @Tags         Playlists
+// @Accept       json
+// @Produce      json
+// @Param        importId  path  string                            true  "Import ID"
+// @Param        body      body  dto.ResolvePlaylistImportRequest  true  "Resolutions by entry index"
+// @Success      200 {object} dto.PlaylistImportResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/imports/{importId}/resolve [post]
+func (h *PlaylistImportHandler) ResolveImport(c *gin.Context) {
+	var req dto.ResolvePlaylistImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	report, err := h.service.ResolveImport(c.Param("importId"), userID.(string), req.Resolutions)
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func respondImportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPlaylistImportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPlaylistVersionConflict):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrUnknownPlaylistFormat),
+		errors.Is(err, services.ErrEmptyPlaylistImport),
+		errors.Is(err, services.ErrTooManyImportEntries),
+		errors.Is(err, services.ErrInvalidImportResolution):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/services"
+	"music-library-api/pkg/constants"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaylistVersionHandler struct {
+	service         services.IPlaylistVersionService
+	playlistService services.IPlaylistService
+	authz           services.IAuthorizationService
+}
+
+func NewPlaylistVersionHandler(service services.IPlaylistVersionService, playlistService services.IPlaylistService, authz services.IAuthorizationService) *PlaylistVersionHandler {
+	return &PlaylistVersionHandler{service: service, playlistService: playlistService, authz: authz}
+}
+
+// GetVersions godoc
+// @Summary      List playlist versions
+// @Description  Snapshots of the playlist's title, cover and entries, newest first. One is taken on every change to them.
+// @Tags         Playlists
+// @Produce      json
+// @Param        id     path   string  true   "Playlist ID"
+// @Param        page   query  int     false  "Page number"
+// @Param        limit  query  int     false  "Page size"
+// @Success      200 {object} map[string]interface{}
+// @Failure      404 {object} map[string]string
+// @Router       /playlists/{id}/versions [get]
+func (h *PlaylistVersionHandler) GetVersions(c *gin.Context) {
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleViewer)
+	if !ok {
+		return
+	}
+
+	page, limit := constants.ParsePagination(c.Query("page"), c.Query("limit"))
+	versions, total, err := h.service.GetVersions(pl, page, limit)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":        page,
+		"limit":       limit,
+		"total_count": total,
+		"data":        versions,
+	})
+}
+
+// GetVersion godoc
+// @Summary      Get a playlist version
+// @Tags         Playlists
+// @Produce      json
+// @Param        id       path  string  true  "Playlist ID"
+// @Param        version  path  int     true  "Version number"
+// @Success      200 {object} dto.PlaylistVersionResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Router       /playlists/{id}/versions/{version} [get]
+func (h *PlaylistVersionHandler) GetVersion(c *gin.Context) {
+	number, ok := versionParam(c, c.Param("version"))
+	if !ok {
+		return
+	}
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleViewer)
+	if !ok {
+		return
+	}
+
+	version, err := h.service.GetVersion(pl, number)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+// DiffVersions godoc
+// @Summary      Diff two playlist versions
+// @Description  Title and cover changes plus the entries added, removed and moved between two versions.
+// @Tags         Playlists
+// @Produce      json
+// @Param        id    path   string  true   "Playlist ID"
+// @Param        from  query  int     true   "Older version"
+// @Param        to    query  int     false  "Newer version (default: the latest)"
+// @Success      200 {object} dto.PlaylistVersionDiffResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Router       /playlists/{id}/versions/diff [get]
+func (h *PlaylistVersionHandler) DiffVersions(c *gin.Context) {
+	from, ok := versionParam(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	var to *int64
+	if c.Query("to") != "" {
+		v, ok := versionParam(c, c.Query("to"))
+		if !ok {
+			return
+		}
+		to = &v
+	}
+
+	pl, ok := authorizedPlaylist(c, h.playlistService, h.authz, c.Param("id"), models.PlaylistRoleViewer)
+	if !ok {
+		return
+	}
+
+	diff, err := h.service.DiffVersions(pl, from, to)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreVersion godoc
+// @Summary      Restore a playlist version
+// @Description  Put back the title, cover and entries of an earlier version. The restore is recorded as a new version.
+// @Tags         Playlists
+// @Produce      json
+// @Param        id        path    string  true  "Playlist ID"
+// @Param        version   path    int     true  "Version to restore"
+// @Param        If-Match  header  string  true  "Current playlist version (ETag)"
+// @Success      200 {object} dto.PlaylistResponse
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      412 {object} map[string]string
+// @Failure      428 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /playlists/{id}/versions/{version}/restore [post]
+func (h *PlaylistVersionHandler) RestoreVersion(c *gin.Context) {
+	number, ok := versionParam(c, c.Param("version"))
+	if !ok {
+		return
+	}
+
+	idStr := c.Param("id")
+	if _, ok := authorizedPlaylist(c, h.playlistService, h.authz, idStr, models.PlaylistRoleEditor); !ok {
+		return
+	}
+	current, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	restored, err := h.playlistService.RestoreVersion(idStr, actorFromContext(c).UserID, current, number)
+	if err != nil {
+		respondPlaylistError(c, err)
+		return
+	}
+
+	setPlaylistETag(c, restored)
+	c.JSON(http.StatusOK, mappers.ToPlaylistResponse(restored))
+}
+
+func versionParam(c *gin.Context, value string) (int64, bool) {
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || v < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be a non-negative integer"})
+		return 0, false
+	}
+	return v, true
+}
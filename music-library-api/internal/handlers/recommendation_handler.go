@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"music-library-api/internal/services"
+	"music-library-api/pkg/constants"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	service services.IRecommendationService
+}
+
+func NewRecommendationHandler(service services.IRecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{service: service}
+}
+
+// GetMyRecommendations godoc
+// @Summary      Get recommendations
+// @Description  Tracks picked for you from what you played and liked, best first, refreshed every few hours.
+// @Description  Until there is enough history, or to complete a short list, popular tracks are added.
+// @Tags         Recommendations
+// @Produce      json
+// @Param        limit  query int false "Number of tracks (max 100)"
+// @Success      200 {object} map[string]interface{} "generated_at is null when every track is a popular fallback"
+// @Failure      500 {object} map[string]string
+// @Security     BearerAuth
+// @Router       /users/me/recommendations [get]
+func (h *RecommendationHandler) GetMyRecommendations(c *gin.Context) {
+	_, limit := constants.ParsePagination("", c.Query("limit"))
+
+	tracks, generatedAt, err := h.service.GetUserRecommendations(c.GetString("user_id"), limit)
+	if err != nil {
+		respondRecommendationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"limit":        limit,
+		"generated_at": generatedAt,
+		"data":         tracks,
+	})
+}
+
+// GetSimilarTracks godoc
+// @Summary      Get similar tracks
+// @Description  Tracks listened to by the same people or sharing artist, genre and tags, most similar first.
+// @Description  Tracks without enough history get popular tracks by the same artist, then in the same genre.
+// @Tags         Recommendations
+// @Produce      json
+// @Param        id     path  string true  "Track ID"
+// @Param        limit  query int    false "Number of tracks (max 100)"
+// @Success      200 {object} map[string]interface{}
+// @Failure      404 {object} map[string]string
+// @Router       /tracks/{id}/similar [get]
+func (h *RecommendationHandler) GetSimilarTracks(c *gin.Context) {
+	_, limit := constants.ParsePagination("", c.Query("limit"))
+
+	tracks, generatedAt, err := h.service.GetSimilarTracks(c.Param("id"), limit)
+	if err != nil {
+		respondRecommendationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"limit":        limit,
+		"generated_at": generatedAt,
+		"data":         tracks,
+	})
+}
+
+func respondRecommendationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrRecommendationTrackNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
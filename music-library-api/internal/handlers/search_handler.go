@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"music-library-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 20
+)
+
+type SearchHandler struct {
+	suggestService services.ISuggestService
+}
+
+func NewSearchHandler(suggestService services.ISuggestService) *SearchHandler {
+	return &SearchHandler{suggestService: suggestService}
+}
+
+// Suggest godoc
+// @Summary      Search-as-you-type suggestions
+// @Description  Prefix suggestions for tracks, artists, albums, playlists and tags, ignoring accents and tolerating small typos.
+// @Description  Served from an in-memory index refreshed from the database in the background.
+// @Tags         search
+// @Produce      json
+// @Param        q      query     string  true   "Partial query"
+// @Param        limit  query     int     false  "Suggestions per group (default 5, max 20)"
+// @Success      200    {object}  dto.SuggestResponse
+// @Router       /search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	c.JSON(http.StatusOK, h.suggestService.Suggest(c.Query("q"), limit))
+}
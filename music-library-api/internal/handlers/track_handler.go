@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"music-library-api/internal/dto"
@@ -10,6 +14,7 @@ import (
 	"music-library-api/internal/models"
 	"music-library-api/internal/services"
 	"music-library-api/pkg/constants"
+	"music-library-api/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hajimehoshi/go-mp3"
@@ -18,48 +23,73 @@ import (
 )
 
 type TrackHandler struct {
-	service services.ITrackService
-	mongodb *mongo.Database
+	service       services.ITrackService
+	albumService  services.IAlbumService
+	genreService  services.IGenreService
+	lyricsService services.ILyricsService
+	mongodb       *mongo.Database
 }
 
-func NewTrackHandler(service services.ITrackService, mongodb *mongo.Database) *TrackHandler {
+func NewTrackHandler(service services.ITrackService, albumService services.IAlbumService, genreService services.IGenreService, lyricsService services.ILyricsService, mongodb *mongo.Database) *TrackHandler {
 	return &TrackHandler{
-		service: service,
-		mongodb: mongodb,
+		service:       service,
+		albumService:  albumService,
+		genreService:  genreService,
+		lyricsService: lyricsService,
+		mongodb:       mongodb,
 	}
 }
 
 // GetTracks godoc
 // @Summary      Get list of tracks
-// @Description  Retrieve a paginated list of tracks
+// @Description  Retrieve a paginated list of tracks, by page number or by cursor
 // @Tags         tracks
 // @Accept       json
 // @Produce      json
 // @Param        page   query     int     false  "Page number"
 // @Param        limit  query     int     false  "Page size"
+// @Param        cursor query     string  false  "Opaque cursor from next_cursor/prev_cursor; replaces page"
+// @Param        license           query  string  false  "Comma-separated licenses, e.g. cc-by,cc0"
+// @Param        creative_commons  query  bool    false  "Only Creative Commons licensed tracks"
+// @Param        explicit          query  bool    false  "Filter by explicit flag"
+// @Param        composer          query  string  false  "Composer name"
+// @Param        lyricist          query  string  false  "Lyricist name"
+// @Param        producer          query  string  false  "Producer name"
+// @Param        isrc              query  string  false  "ISRC"
 // @Success      200    {object}  dto.TrackListResponse
+// @Failure      400    {object}  map[string]string
 // @Failure      500    {object}  map[string]string
 // @Router       /tracks [get]
 func (h *TrackHandler) GetTracks(c *gin.Context) {
 	page, limit := constants.ParsePagination(c.Query("page"), c.Query("limit"))
 
-	uid := ""
-	if c.Query("myTracks") == "true" {
-		userID, exists := c.Get("user_id")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-			return
-		}
-		uid = userID.(string)
+	filter, ok := parseTrackFilter(c)
+	if !ok {
+		return
+	}
+	cursor, ok := parseCursor(c)
+	if !ok {
+		return
 	}
 
-	list, err := h.service.GetTracks(page, limit, uid)
+	var list []*models.Track
+	var cursors utils.CursorPage
+	var err error
+	if cursor != nil {
+		page = 0
+		list, cursors, err = h.service.GetTracksByCursor(cursor, limit, filter)
+	} else {
+		list, err = h.service.GetTracks(page, limit, filter)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	totalCount, _ := h.service.CountTracks(uid)
+	totalCount, _ := h.service.CountTracks(filter)
+	if cursor == nil {
+		cursors = utils.NewCursorPage(list, page > 1, int64((page-1)*limit+len(list)) < totalCount)
+	}
 
 	resp := make([]dto.TrackResponse, 0)
 	for _, t := range list {
@@ -71,6 +101,8 @@ func (h *TrackHandler) GetTracks(c *gin.Context) {
 		Limit:      limit,
 		TotalCount: totalCount,
 		Data:       resp,
+		NextCursor: cursors.NextCursor,
+		PrevCursor: cursors.PrevCursor,
 	})
 }
 
@@ -112,6 +144,14 @@ func (h *TrackHandler) GetTrackByID(c *gin.Context) {
 // @Param        genre        formData  string  false "Genre"
 // @Param        release_year formData  int     false "Release Year"
 // @Param        file         formData  file    true  "MP3 File"
+// @Param        isrc         formData  string  false "ISRC, e.g. VN-A0S-23-00001"
+// @Param        composers    formData  []string false "Composers"
+// @Param        lyricists    formData  []string false "Lyricists"
+// @Param        producers    formData  []string false "Producers"
+// @Param        copyright    formData  string  false "Copyright notice"
+// @Param        license      formData  string  false "License (all-rights-reserved, cc0, cc-by, cc-by-sa, cc-by-nd, cc-by-nc, cc-by-nc-sa, cc-by-nc-nd, public-domain)"
+// @Param        explicit     formData  bool    false "Explicit content"
+// @Param        tags         formData  []string false "Tags"
 // @Success      201    {object} dto.TrackResponse
 // @Failure      400    {object} map[string]string
 // @Failure      500    {object} map[string]string
@@ -130,6 +170,12 @@ func (h *TrackHandler) CreateTrack(c *gin.Context) {
 		return
 	}
 
+	isrc, err := utils.NormalizeISRC(req.ISRC)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	file, err := req.File.Open()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open file"})
@@ -137,11 +183,19 @@ func (h *TrackHandler) CreateTrack(c *gin.Context) {
 	}
 	defer file.Close()
 
-	gridFSID, err := h.service.UploadMP3ToGridFS(req.File.Filename, file)
+	// Read ID3 tags (TRCK/TPOS for album ordering, TALB/TCON/TYER and credits as fallbacks, USLT/SYLT lyrics, APIC artwork)
+	// and check the audio before uploading, so a rejected file is never stored
+	tag, err := utils.ReadID3(file)
+	if errors.Is(err, utils.ErrID3TagTooLarge) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "upload to GridFS failed"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read ID3 tags"})
 		return
 	}
+	trackNumber, _ := tag.TrackNumber()
+	discNumber, _ := tag.DiscNumber()
 
 	file.Seek(0, 0)
 	decoder, err := mp3.NewDecoder(file)
@@ -164,6 +218,13 @@ func (h *TrackHandler) CreateTrack(c *gin.Context) {
 		return
 	}
 
+	file.Seek(0, 0)
+	gridFSID, err := h.service.UploadMP3ToGridFS(req.File.Filename, file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "upload to GridFS failed"})
+		return
+	}
+
 	track := &models.Track{
 		UserID:      userIDObj,
 		Title:       req.File.Filename,
@@ -173,16 +234,99 @@ func (h *TrackHandler) CreateTrack(c *gin.Context) {
 		ReleaseYear: req.ReleaseYear,
 		Duration:    duration,
 		FileID:      gridFSID,
+		DiscNumber:  discNumber,
+		TrackNumber: trackNumber,
+		ISRC:        isrc,
+		Composers:   cleanCredits(req.Composers),
+		Lyricists:   cleanCredits(req.Lyricists),
+		Producers:   cleanCredits(req.Producers),
+		Copyright:   strings.TrimSpace(req.Copyright),
+		License:     req.License,
+		Explicit:    req.Explicit,
+		Tags:        cleanTags(req.Tags),
+	}
+	if track.Album == "" {
+		track.Album = tag.Album()
+	}
+	if track.Genre == "" {
+		track.Genre = tag.Genre()
+	}
+	if track.ReleaseYear == 0 {
+		track.ReleaseYear = tag.Year()
+	}
+	if track.ISRC == "" {
+		// Taggers often write garbage here, so an invalid TSRC is just ignored
+		track.ISRC, _ = utils.NormalizeISRC(tag.Text("TSRC"))
+	}
+	if len(track.Composers) == 0 {
+		track.Composers = cleanCredits(tag.Texts("TCOM"))
+	}
+	if len(track.Lyricists) == 0 {
+		track.Lyricists = cleanCredits(tag.Texts("TEXT"))
+	}
+	if track.Copyright == "" {
+		track.Copyright = tag.Text("TCOP")
+	}
+	if track.License == "" {
+		track.License = models.LicenseAllRightsReserved
+	}
+
+	if err := h.genreService.NormalizeTrackGenre(track); err != nil {
+		h.discardUpload(gridFSID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve genre"})
+		return
 	}
 
 	if err := h.service.CreateTrack(track); err != nil {
+		h.discardUpload(gridFSID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save track"})
 		return
 	}
 
+	if err := h.albumService.AttachTrack(track); err != nil {
+		h.discardTrack(track)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add track to album"})
+		return
+	}
+
+	if err := h.lyricsService.ImportID3Lyrics(track, tag); err != nil {
+		h.discardTrack(track)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import lyrics"})
+		return
+	}
+
+	// Embedded artwork becomes the album cover; the upload succeeds without it
+	if pic := tag.Picture(); pic != nil {
+		if err := h.albumService.SetArtwork(track, pic.Data); err != nil {
+			log.Printf("failed to set album artwork from track %s: %v", track.ID.Hex(), err)
+		}
+	}
+
 	c.JSON(http.StatusCreated, mappers.ToTrackResponse(track))
 }
 
+// discardTrack rolls back a half-created upload so a failed request does not
+// leave a track behind that is missing from its album or lyrics, nor its file.
+func (h *TrackHandler) discardTrack(track *models.Track) {
+	if err := h.albumService.DetachTrack(track); err != nil {
+		log.Printf("failed to detach discarded track %s: %v", track.ID.Hex(), err)
+	}
+	if err := h.lyricsService.DeleteLyrics(track.ID.Hex(), ""); err != nil {
+		log.Printf("failed to delete lyrics of discarded track %s: %v", track.ID.Hex(), err)
+	}
+	if err := h.service.DeleteTrack(track.ID.Hex()); err != nil {
+		log.Printf("failed to delete discarded track %s: %v", track.ID.Hex(), err)
+	}
+	h.discardUpload(track.FileID)
+}
+
+// discardUpload removes the GridFS file of an upload that was not saved.
+func (h *TrackHandler) discardUpload(fileID primitive.ObjectID) {
+	if err := h.service.DeleteMP3FromGridFS(fileID); err != nil {
+		log.Printf("failed to delete discarded file %s: %v", fileID.Hex(), err)
+	}
+}
+
 // UpdateTrack godoc
 // @Summary      Update a track
 // @Description  Update track metadata by ID
@@ -227,8 +371,49 @@ func (h *TrackHandler) UpdateTrack(c *gin.Context) {
 	if req.ReleaseYear != 0 {
 		track.ReleaseYear = req.ReleaseYear
 	}
+	if req.DiscNumber != 0 {
+		track.DiscNumber = req.DiscNumber
+	}
+	if req.TrackNumber != 0 {
+		track.TrackNumber = req.TrackNumber
+	}
+	if req.ISRC != nil {
+		isrc, err := utils.NormalizeISRC(*req.ISRC)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		track.ISRC = isrc
+	}
+	if req.Composers != nil {
+		track.Composers = cleanCredits(req.Composers)
+	}
+	if req.Lyricists != nil {
+		track.Lyricists = cleanCredits(req.Lyricists)
+	}
+	if req.Producers != nil {
+		track.Producers = cleanCredits(req.Producers)
+	}
+	if req.Copyright != nil {
+		track.Copyright = strings.TrimSpace(*req.Copyright)
+	}
+	if req.License != nil {
+		track.License = *req.License
+	}
+	if req.Explicit != nil {
+		track.Explicit = *req.Explicit
+	}
+	if req.Tags != nil {
+		track.Tags = cleanTags(req.Tags)
+	}
+
+	if err := h.genreService.NormalizeTrackGenre(track); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	if err := h.service.UpdateTrack(track); err != nil {
+	// Saves the track and re-files it in the (possibly changed) album
+	if err := h.albumService.AttachTrack(track); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -268,52 +453,90 @@ func (h *TrackHandler) DeleteTrack(c *gin.Context) {
 		return
 	}
 
+	if err := h.albumService.DetachTrack(track); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.lyricsService.DeleteLyrics(track.ID.Hex(), ""); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.Status(http.StatusNoContent)
 }
 
 // SearchTracks godoc
 // @Summary      Search tracks
-// @Description  Search tracks by query string
+// @Description  Full-text search over title, artist, album, genre and lyrics, ignoring accents ("bui anh tuan" matches "Bùi Anh Tuấn").
+// @Description  Results are ranked by relevance (title > artist > album > genre > lyrics, exact-diacritic matches boosted) and carry highlighted snippets.
+// @Description  Facet counts per genre, artist, year, duration range, tag and uploader are computed over all matching tracks.
 // @Tags         tracks
 // @Produce      json
 // @Param        q      query     string true  "Search query"
 // @Param        page   query     int    false "Page number"
 // @Param        limit  query     int    false "Page size"
-// @Success      200    {object}  dto.TrackListResponse
+// @Param        license           query  string  false  "Comma-separated licenses, e.g. cc-by,cc0"
+// @Param        creative_commons  query  bool    false  "Only Creative Commons licensed tracks"
+// @Param        explicit          query  bool    false  "Filter by explicit flag"
+// @Param        composer          query  string  false  "Composer name"
+// @Param        lyricist          query  string  false  "Lyricist name"
+// @Param        producer          query  string  false  "Producer name"
+// @Param        isrc              query  string  false  "ISRC"
+// @Param        genre             query  string  false  "Genre name"
+// @Param        artist            query  string  false  "Artist name"
+// @Param        year_from         query  int     false  "Released in or after"
+// @Param        year_to           query  int     false  "Released in or before"
+// @Param        duration_min      query  int     false  "Minimum duration in seconds"
+// @Param        duration_max      query  int     false  "Maximum duration in seconds"
+// @Param        tag               query  string  false  "Tag"
+// @Param        uploader          query  string  false  "Uploader user ID"
+// @Param        sort              query  string  false  "title, artist, release_year, duration, created_at or relevance, optionally suffixed with :asc or :desc"
+// @Success      200    {object}  dto.TrackSearchResponse
+// @Failure      400    {object} map[string]string
 // @Failure      500    {object} map[string]string
 // @Router       /tracks/search [get]
 func (h *TrackHandler) SearchTracks(c *gin.Context) {
 	query := c.Query("q")
 	page, limit := constants.ParsePagination(c.Query("page"), c.Query("limit"))
 
-	uid := ""
-	if c.Query("myTracks") == "true" {
-		userID, exists := c.Get("user_id")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-			return
-		}
-		uid = userID.(string)
+	filter, ok := parseTrackFilter(c)
+	if !ok {
+		return
 	}
 
-	list, err := h.service.SearchTracks(query, page, limit, uid)
+	sort, err := parseTrackSort(c.Query("sort"), query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hits, err := h.service.SearchTracks(query, page, limit, filter, sort)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	totalCount, _ := h.service.CountSearchTracks(query, uid)
+	totalCount, _ := h.service.CountSearchTracks(query, filter)
 
-	resp := make([]dto.TrackResponse, 0)
-	for _, t := range list {
-		resp = append(resp, mappers.ToTrackResponse(t))
+	facets, err := h.service.SearchFacets(query, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, dto.TrackListResponse{
+	terms := utils.SearchTerms(query)
+	resp := make([]dto.TrackSearchResult, 0)
+	for _, hit := range hits {
+		resp = append(resp, mappers.ToTrackSearchResult(hit, terms))
+	}
+
+	c.JSON(http.StatusOK, dto.TrackSearchResponse{
 		Page:       page,
 		Limit:      limit,
 		TotalCount: totalCount,
 		Data:       resp,
+		Facets:     mappers.ToTrackFacetsResponse(facets),
 	})
 }
 
@@ -375,3 +598,126 @@ func (h *TrackHandler) StreamTrack(c *gin.Context) {
 		}
 	}
 }
+
+// parseTrackFilter reads the listing filters shared by GetTracks and SearchTracks.
+// It writes the error response itself and returns false on invalid input.
+func parseTrackFilter(c *gin.Context) (models.TrackFilter, bool) {
+	filter := models.TrackFilter{
+		Composer: c.Query("composer"),
+		Lyricist: c.Query("lyricist"),
+		Producer: c.Query("producer"),
+	}
+
+	if c.Query("myTracks") == "true" {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return filter, false
+		}
+		filter.UserID = userID.(string)
+	}
+
+	if license := c.Query("license"); license != "" {
+		filter.Licenses = utils.UniqueStrings(strings.Split(strings.ToLower(license), ","))
+	}
+	filter.CreativeCommons = c.Query("creative_commons") == "true"
+
+	switch c.Query("explicit") {
+	case "":
+	case "true", "false":
+		explicit := c.Query("explicit") == "true"
+		filter.Explicit = &explicit
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "explicit must be true or false"})
+		return filter, false
+	}
+
+	filter.Genre = c.Query("genre")
+	filter.Artist = c.Query("artist")
+	filter.Tag = c.Query("tag")
+	filter.Uploader = c.Query("uploader")
+
+	ranges := []struct {
+		param string
+		dest  *int
+	}{
+		{"year_from", &filter.YearFrom},
+		{"year_to", &filter.YearTo},
+		{"duration_min", &filter.DurationMin},
+		{"duration_max", &filter.DurationMax},
+	}
+	for _, r := range ranges {
+		value := c.Query(r.param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": r.param + " must be a non-negative number"})
+			return filter, false
+		}
+		*r.dest = n
+	}
+
+	if isrc := c.Query("isrc"); isrc != "" {
+		normalized, err := utils.NormalizeISRC(isrc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return filter, false
+		}
+		filter.ISRC = normalized
+	}
+
+	return filter, true
+}
+
+// cleanCredits trims and de-duplicates credit names; a single comma-separated
+// form value is split into several names.
+func cleanCredits(names []string) []string {
+	if len(names) == 1 && strings.Contains(names[0], ",") {
+		names = strings.Split(names[0], ",")
+	}
+	cleaned := utils.UniqueStrings(names)
+	if cleaned == nil {
+		return []string{}
+	}
+	return cleaned
+}
+
+// parseTrackSort reads "field" or "field:asc|desc". Without a field, results
+// are sorted by relevance when searching and by upload date otherwise.
+func parseTrackSort(raw, query string) (models.TrackSort, error) {
+	field, order, _ := strings.Cut(strings.ToLower(strings.TrimSpace(raw)), ":")
+	if field == "" {
+		if len(utils.SearchTerms(query)) > 0 {
+			return models.TrackSort{Field: models.TrackSortRelevance, Desc: true}, nil
+		}
+		return models.TrackSort{Field: models.TrackSortCreatedAt, Desc: true}, nil
+	}
+
+	switch field {
+	case models.TrackSortRelevance, models.TrackSortTitle, models.TrackSortArtist,
+		models.TrackSortReleaseYear, models.TrackSortDuration, models.TrackSortCreatedAt:
+	default:
+		return models.TrackSort{}, fmt.Errorf("invalid sort field: %s", field)
+	}
+
+	switch order {
+	case "":
+		// Relevance and dates read best newest/highest first
+		return models.TrackSort{Field: field, Desc: field == models.TrackSortRelevance || field == models.TrackSortCreatedAt}, nil
+	case "asc", "desc":
+		return models.TrackSort{Field: field, Desc: order == "desc"}, nil
+	default:
+		return models.TrackSort{}, fmt.Errorf("invalid sort order: %s", order)
+	}
+}
+
+// cleanTags lowercases and de-duplicates tags.
+func cleanTags(tags []string) []string {
+	lowered := make([]string, len(tags))
+	for i, t := range tags {
+		lowered[i] = strings.ToLower(t)
+	}
+	return cleanCredits(lowered)
+}
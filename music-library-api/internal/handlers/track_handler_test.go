@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"music-library-api/internal/models"
+	"music-library-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeTrackService struct {
+	services.ITrackService
+	uploads []primitive.ObjectID
+	deleted []primitive.ObjectID
+	tracks  []string
+}
+
+func (s *fakeTrackService) UploadMP3ToGridFS(filename string, r io.Reader) (primitive.ObjectID, error) {
+	id := primitive.NewObjectID()
+	s.uploads = append(s.uploads, id)
+	return id, nil
+}
+
+func (s *fakeTrackService) DeleteMP3FromGridFS(fileID primitive.ObjectID) error {
+	s.deleted = append(s.deleted, fileID)
+	return nil
+}
+
+func (s *fakeTrackService) DeleteTrack(id string) error {
+	s.tracks = append(s.tracks, id)
+	return nil
+}
+
+type fakeAlbumService struct {
+	services.IAlbumService
+}
+
+func (s *fakeAlbumService) DetachTrack(track *models.Track) error { return nil }
+
+type fakeLyricsService struct {
+	services.ILyricsService
+}
+
+func (s *fakeLyricsService) DeleteLyrics(trackID, language string) error { return nil }
+
+func TestCreateTrackRejectsBeforeUpload(t *testing.T) {
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		// A syncsafe size of 0x7f7f7f7f is 256 MiB, over MaxID3TagSize
+		{"oversized ID3 tag", []byte{'I', 'D', '3', 4, 0, 0, 0x7f, 0x7f, 0x7f, 0x7f}},
+		{"truncated ID3 tag", []byte{'I', 'D', '3', 4, 0, 0, 0, 0, 1, 0, 'T', 'I'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			form.WriteField("artist", "Artist")
+			part, _ := form.CreateFormFile("file", "song.mp3")
+			part.Write(tt.file)
+			form.Close()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/tracks", &body)
+			c.Request.Header.Set("Content-Type", form.FormDataContentType())
+			c.Set("user_id", primitive.NewObjectID().Hex())
+
+			tracks := &fakeTrackService{}
+			NewTrackHandler(tracks, nil, nil, nil, nil).CreateTrack(c)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d (%s)", w.Code, http.StatusBadRequest, w.Body)
+			}
+			if len(tracks.uploads) != 0 {
+				t.Errorf("a rejected file was uploaded to GridFS")
+			}
+		})
+	}
+}
+
+func TestDiscardTrackDeletesUpload(t *testing.T) {
+	tracks := &fakeTrackService{}
+	h := NewTrackHandler(tracks, &fakeAlbumService{}, nil, &fakeLyricsService{}, nil)
+
+	track := &models.Track{FileID: primitive.NewObjectID()}
+	track.ID = primitive.NewObjectID()
+	h.discardTrack(track)
+
+	if len(tracks.tracks) != 1 || tracks.tracks[0] != track.ID.Hex() {
+		t.Errorf("deleted tracks = %v, want %s", tracks.tracks, track.ID.Hex())
+	}
+	if len(tracks.deleted) != 1 || tracks.deleted[0] != track.FileID {
+		t.Errorf("deleted files = %v, want %s", tracks.deleted, track.FileID.Hex())
+	}
+}
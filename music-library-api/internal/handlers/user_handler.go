@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	"music-library-api/internal/dto"
 	"music-library-api/internal/services"
+	"music-library-api/pkg/constants"
+	"music-library-api/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,21 +21,38 @@ func NewUserHandler(service services.IUserService) *UserHandler {
 
 // GET /users — Admin only
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit := constants.ParsePagination(c.Query("page"), c.Query("limit"))
 
-	users, total, err := h.service.GetAllUsers(page, limit)
+	cursor, ok := parseCursor(c)
+	if !ok {
+		return
+	}
+
+	var users []dto.UserResponse
+	var total int64
+	var cursors utils.CursorPage
+	var err error
+	if cursor != nil {
+		users, total, cursors, err = h.service.GetUsersByCursor(cursor, limit)
+	} else {
+		users, total, cursors, err = h.service.GetAllUsers(page, limit)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"page":        page,
+	body := gin.H{
 		"limit":       limit,
 		"total_count": total,
 		"data":        users,
-	})
+		"next_cursor": cursors.NextCursor,
+		"prev_cursor": cursors.PrevCursor,
+	}
+	if cursor == nil {
+		body["page"] = page
+	}
+	c.JSON(http.StatusOK, body)
 }
 
 // GET /users/:id — Admin only
@@ -0,0 +1,61 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ToAlbumResponse builds the ordered tracklist grouped by disc.
+// Entries whose track no longer exists are skipped.
+func ToAlbumResponse(a *models.Album, tracks map[primitive.ObjectID]*models.Track) dto.AlbumResponse {
+	resp := dto.AlbumResponse{
+		ID:          a.ID.Hex(),
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+		UserID:      a.UserID.Hex(),
+		Title:       a.Title,
+		Artist:      a.Artist,
+		ReleaseYear: a.ReleaseYear,
+		Cover:       a.Cover,
+		Discs:       []dto.AlbumDiscResponse{},
+	}
+
+	subtitles := make(map[int]string, len(a.Discs))
+	for _, d := range a.Discs {
+		subtitles[d.Number] = d.Subtitle
+	}
+
+	for _, entry := range a.Tracks {
+		t, ok := tracks[entry.TrackID]
+		if !ok {
+			continue
+		}
+
+		disc := entry.DiscNumber
+		if disc < 1 {
+			disc = 1
+		}
+		if n := len(resp.Discs); n == 0 || resp.Discs[n-1].Number != disc {
+			resp.Discs = append(resp.Discs, dto.AlbumDiscResponse{
+				Number:   disc,
+				Subtitle: subtitles[disc],
+				Tracks:   []dto.AlbumTrackResponse{},
+			})
+		}
+
+		current := &resp.Discs[len(resp.Discs)-1]
+		current.Tracks = append(current.Tracks, dto.AlbumTrackResponse{
+			DiscNumber:  disc,
+			TrackNumber: entry.TrackNumber,
+			Track:       ToTrackResponse(t),
+		})
+		current.Duration += t.Duration
+
+		resp.TrackCount++
+		resp.TotalDuration += t.Duration
+	}
+
+	return resp
+}
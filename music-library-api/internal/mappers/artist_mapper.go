@@ -0,0 +1,71 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+)
+
+const singlesTitle = "Singles"
+
+// ToArtistProfileResponse maps the profile and builds its discography.
+// Tracks must already be ordered by release year, then album order.
+func ToArtistProfileResponse(a *models.ArtistProfile, tracks []*models.Track) dto.ArtistProfileResponse {
+	resp := dto.ArtistProfileResponse{
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+		UserID:      a.UserID.Hex(),
+		Name:        a.Name,
+		Bio:         a.Bio,
+		Avatar:      a.Avatar,
+		Links:       make([]dto.ArtistLinkResponse, len(a.Links)),
+		Discography: []dto.DiscographyYearResponse{},
+	}
+	if !a.ID.IsZero() {
+		resp.ID = a.ID.Hex()
+	}
+	for i, l := range a.Links {
+		resp.Links[i] = dto.ArtistLinkResponse{Label: l.Label, URL: l.URL}
+	}
+
+	albumIndex := map[string]int{} // album key -> index within the current year
+	seenAlbums := map[string]struct{}{}
+	for _, t := range tracks {
+		if n := len(resp.Discography); n == 0 || resp.Discography[n-1].ReleaseYear != t.ReleaseYear {
+			resp.Discography = append(resp.Discography, dto.DiscographyYearResponse{
+				ReleaseYear: t.ReleaseYear,
+				Albums:      []dto.DiscographyAlbumResponse{},
+			})
+			albumIndex = map[string]int{}
+		}
+		year := &resp.Discography[len(resp.Discography)-1]
+
+		key, albumID, title := "", "", singlesTitle
+		if t.AlbumID != nil {
+			key, albumID, title = t.AlbumID.Hex(), t.AlbumID.Hex(), t.Album
+		} else if t.Album != "" {
+			key, title = "title:"+t.Album, t.Album
+		}
+
+		idx, ok := albumIndex[key]
+		if !ok {
+			year.Albums = append(year.Albums, dto.DiscographyAlbumResponse{
+				AlbumID: albumID,
+				Title:   title,
+				Tracks:  []dto.TrackResponse{},
+			})
+			idx = len(year.Albums) - 1
+			albumIndex[key] = idx
+			if _, seen := seenAlbums[albumID]; albumID != "" && !seen {
+				seenAlbums[albumID] = struct{}{}
+				resp.Stats.AlbumCount++
+			}
+		}
+
+		album := &year.Albums[idx]
+		album.Tracks = append(album.Tracks, ToTrackResponse(t))
+		album.TrackCount++
+		album.TotalDuration += t.Duration
+	}
+
+	return resp
+}
@@ -0,0 +1,59 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+)
+
+func ToGenreResponse(g *models.Genre) dto.GenreResponse {
+	resp := dto.GenreResponse{
+		ID:           g.ID.Hex(),
+		CreatedAt:    g.CreatedAt,
+		UpdatedAt:    g.UpdatedAt,
+		Slug:         g.Slug,
+		Name:         g.Name,
+		Aliases:      g.Aliases,
+		DisplayNames: g.DisplayNames,
+	}
+	if g.ParentID != nil {
+		resp.ParentID = g.ParentID.Hex()
+	}
+	if resp.Aliases == nil {
+		resp.Aliases = []string{}
+	}
+	if resp.DisplayNames == nil {
+		resp.DisplayNames = map[string]string{}
+	}
+	return resp
+}
+
+// ToGenreTree nests genres under their parents. Genres whose parent is not in
+// the list become roots, so a subtree can be passed as well as the full set.
+func ToGenreTree(genres []*models.Genre) []dto.GenreResponse {
+	byParent := map[string][]*models.Genre{}
+	present := map[string]bool{}
+	for _, g := range genres {
+		present[g.ID.Hex()] = true
+	}
+
+	var roots []*models.Genre
+	for _, g := range genres {
+		if g.ParentID == nil || !present[g.ParentID.Hex()] {
+			roots = append(roots, g)
+			continue
+		}
+		byParent[g.ParentID.Hex()] = append(byParent[g.ParentID.Hex()], g)
+	}
+
+	var build func(list []*models.Genre) []dto.GenreResponse
+	build = func(list []*models.Genre) []dto.GenreResponse {
+		out := make([]dto.GenreResponse, len(list))
+		for i, g := range list {
+			out[i] = ToGenreResponse(g)
+			out[i].Children = build(byParent[g.ID.Hex()])
+		}
+		return out
+	}
+
+	return build(roots)
+}
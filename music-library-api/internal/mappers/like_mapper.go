@@ -0,0 +1,14 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+)
+
+func ToLikeItemResponse(l models.Like) dto.LikeItemResponse {
+	return dto.LikeItemResponse{
+		TargetType: l.TargetType,
+		TargetID:   l.TargetID.Hex(),
+		LikedAt:    l.CreatedAt,
+	}
+}
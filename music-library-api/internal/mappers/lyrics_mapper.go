@@ -0,0 +1,24 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+)
+
+func ToLyricsResponse(l *models.Lyrics, languages []string) dto.LyricsResponse {
+	lines := make([]dto.LyricLineResponse, len(l.Lines))
+	for i, line := range l.Lines {
+		lines[i] = dto.LyricLineResponse{TimeMs: line.TimeMs, Text: line.Text}
+	}
+
+	return dto.LyricsResponse{
+		TrackID:   l.TrackID.Hex(),
+		Language:  l.Language,
+		Synced:    l.Synced,
+		Source:    l.Source,
+		Text:      l.Text,
+		Lines:     lines,
+		Languages: languages,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+)
+
+func ToPlayHistoryItemResponse(play models.Play, track *models.Track) dto.PlayHistoryItemResponse {
+	resp := dto.PlayHistoryItemResponse{
+		TrackID:  play.Meta.TrackID.Hex(),
+		PlayedAt: play.PlayedAt,
+		PlayedMs: play.PlayedMs,
+	}
+	if track != nil {
+		t := ToTrackResponse(track)
+		resp.Track = &t
+	}
+	return resp
+}
+
+func ToNowPlayingResponse(np *models.NowPlaying, track *models.Track) *dto.NowPlayingResponse {
+	resp := &dto.NowPlayingResponse{TrackID: np.TrackID.Hex(), StartedAt: np.StartedAt}
+	if track != nil {
+		t := ToTrackResponse(track)
+		resp.Track = &t
+	}
+	return resp
+}
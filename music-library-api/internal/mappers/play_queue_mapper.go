@@ -0,0 +1,50 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ToPlayQueueResponse maps a queue with the tracks of its current and
+// upcoming items; items whose track is missing from tracks have a nil track.
+func ToPlayQueueResponse(q *models.PlayQueue, tracks map[primitive.ObjectID]*models.Track) *dto.PlayQueueResponse {
+	toItem := func(item models.PlayQueueItem) dto.PlayQueueItemResponse {
+		resp := dto.PlayQueueItemResponse{ID: item.ID.Hex(), TrackID: item.TrackID.Hex()}
+		if t := tracks[item.TrackID]; t != nil {
+			track := ToTrackResponse(t)
+			resp.Track = &track
+		}
+		return resp
+	}
+
+	order := q.PlayOrder()
+	i := q.CurrentIndex()
+
+	resp := &dto.PlayQueueResponse{
+		PositionMs: q.PositionMs,
+		PositionAt: q.PositionAt,
+		Playing:    q.Playing,
+		Shuffle:    q.Shuffle,
+		Repeat:     q.Repeat,
+		Upcoming:   make([]dto.PlayQueueItemResponse, 0, len(order)),
+		Device:     q.Device,
+		Version:    q.Version,
+		UpdatedAt:  q.UpdatedAt,
+	}
+	if i >= 0 {
+		current := toItem(order[i])
+		resp.Current = &current
+	}
+	for _, item := range order[i+1:] {
+		resp.Upcoming = append(resp.Upcoming, toItem(item))
+	}
+	if q.Source != nil {
+		resp.Source = &dto.PlayQueueSourceResponse{Type: q.Source.Type}
+		if q.Source.ID != nil {
+			resp.Source.ID = q.Source.ID.Hex()
+		}
+	}
+	return resp
+}
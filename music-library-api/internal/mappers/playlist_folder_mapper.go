@@ -0,0 +1,38 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+	"music-library-api/pkg/utils"
+)
+
+func ToPlaylistFolderResponse(f *models.PlaylistFolder) dto.PlaylistFolderResponse {
+	var parentID *string
+	if f.ParentID != nil {
+		hex := f.ParentID.Hex()
+		parentID = &hex
+	}
+
+	return dto.PlaylistFolderResponse{
+		ID:          f.ID.Hex(),
+		Name:        f.Name,
+		ParentID:    parentID,
+		Position:    f.Position,
+		PlaylistIDs: utils.ConvertToHexIDs(f.PlaylistIDs),
+		CreatedAt:   f.CreatedAt,
+		UpdatedAt:   f.UpdatedAt,
+	}
+}
+
+// ToPlaylistTreeItem maps a playlist with its total duration in seconds.
+func ToPlaylistTreeItem(pl *models.Playlist, duration int) dto.PlaylistTreeItem {
+	return dto.PlaylistTreeItem{
+		ID:            pl.ID.Hex(),
+		Title:         pl.Title,
+		AlbumCover:    pl.Cover(),
+		Visibility:    pl.Visibility,
+		Smart:         pl.Smart != nil,
+		TrackCount:    len(pl.Entries),
+		TotalDuration: duration,
+	}
+}
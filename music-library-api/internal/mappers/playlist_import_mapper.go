@@ -0,0 +1,56 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ToPlaylistImportResponse maps an import report; tracks holds the candidates
+// of ambiguous entries.
+func ToPlaylistImportResponse(report *models.PlaylistImport, tracks map[primitive.ObjectID]*models.Track) dto.PlaylistImportResponse {
+	resp := dto.PlaylistImportResponse{
+		ID:         report.ID.Hex(),
+		PlaylistID: report.PlaylistID.Hex(),
+		FileName:   report.FileName,
+		Format:     report.Format,
+		Summary:    dto.PlaylistImportSummary{Total: len(report.Entries)},
+		Entries:    make([]dto.PlaylistImportEntryResponse, len(report.Entries)),
+	}
+
+	for i, e := range report.Entries {
+		entry := dto.PlaylistImportEntryResponse{
+			Index:    e.Index,
+			Location: e.Location,
+			Title:    e.Title,
+			Artist:   e.Artist,
+			Album:    e.Album,
+			Duration: e.Duration,
+			Status:   e.Status,
+			Method:   e.Method,
+		}
+		if e.TrackID != nil {
+			entry.TrackID = e.TrackID.Hex()
+		}
+		if e.EntryID != nil {
+			entry.EntryID = e.EntryID.Hex()
+		}
+		for _, id := range e.Candidates {
+			if t, ok := tracks[id]; ok {
+				entry.Candidates = append(entry.Candidates, ToTrackResponse(t))
+			}
+		}
+		resp.Entries[i] = entry
+
+		switch e.Status {
+		case models.ImportMatched:
+			resp.Summary.Matched++
+		case models.ImportAmbiguous:
+			resp.Summary.Ambiguous++
+		default:
+			resp.Summary.Unmatched++
+		}
+	}
+	return resp
+}
@@ -0,0 +1,36 @@
+package mappers
+
+import (
+	"fmt"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+	"net/url"
+	"time"
+)
+
+func ToPlaylistInvitationResponse(inv *models.PlaylistInvitation, playlistTitle string) dto.PlaylistInvitationResponse {
+	return dto.PlaylistInvitationResponse{
+		ID:            inv.ID.Hex(),
+		PlaylistID:    inv.PlaylistID.Hex(),
+		PlaylistTitle: playlistTitle,
+		InviteeID:     inv.InviteeID.Hex(),
+		InviterID:     inv.InviterID.Hex(),
+		Role:          inv.Role,
+		Status:        inv.Status,
+		CreatedAt:     inv.CreatedAt,
+		RespondedAt:   inv.RespondedAt,
+	}
+}
+
+// ToPlaylistShareLinkResponse builds the link from baseURL, the public root of the API.
+func ToPlaylistShareLinkResponse(pl *models.Playlist, link *models.PlaylistShareLink, baseURL string) dto.PlaylistShareLinkResponse {
+	return dto.PlaylistShareLinkResponse{
+		ID:        link.ID.Hex(),
+		Token:     link.Token,
+		URL:       fmt.Sprintf("%s/api/playlists/%s?share=%s", baseURL, pl.ID.Hex(), url.QueryEscape(link.Token)),
+		CreatedBy: link.CreatedBy.Hex(),
+		CreatedAt: link.CreatedAt,
+		ExpiresAt: link.ExpiresAt,
+		Expired:   link.Expired(time.Now()),
+	}
+}
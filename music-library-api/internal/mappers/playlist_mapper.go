@@ -6,18 +6,144 @@ import (
 )
 
 func ToPlaylistResponse(pl *models.Playlist) dto.PlaylistResponse {
+	pl.SyncTrackIDs()
+
 	ids := make([]string, len(pl.TrackIDs))
 	for i, id := range pl.TrackIDs {
 		ids[i] = id.Hex()
 	}
 
+	entries := make([]dto.PlaylistEntryResponse, len(pl.Entries))
+	for i, e := range pl.Entries {
+		entries[i] = dto.PlaylistEntryResponse{ID: e.ID.Hex(), TrackID: e.TrackID.Hex(), AddedAt: e.AddedAt, AddedBy: entryAddedBy(pl, e)}
+	}
+
+	collaborators := make([]dto.PlaylistCollaboratorResponse, len(pl.Collaborators))
+	for i, c := range pl.Collaborators {
+		collaborators[i] = dto.PlaylistCollaboratorResponse{UserID: c.UserID.Hex(), Role: c.Role, AddedAt: c.AddedAt}
+	}
+
+	var forkedFrom *dto.PlaylistForkSourceResponse
+	if pl.ForkedFrom != nil {
+		forkedFrom = &dto.PlaylistForkSourceResponse{
+			PlaylistID: pl.ForkedFrom.PlaylistID.Hex(),
+			Version:    pl.ForkedFrom.Version,
+			SyncedAt:   pl.ForkedFrom.SyncedAt,
+		}
+	}
+
 	return dto.PlaylistResponse{
-		ID:         pl.ID.Hex(),
-		UserID:     pl.UserID.Hex(),
-		Title:      pl.Title,
-		AlbumCover: pl.AlbumCover,
-		TrackIDs:   ids,
-		CreatedAt:  pl.CreatedAt.String(),
-		UpdatedAt:  pl.UpdatedAt.String(),
+		ID:             pl.ID.Hex(),
+		UserID:         pl.UserID.Hex(),
+		Title:          pl.Title,
+		AlbumCover:     pl.Cover(),
+		CoverGenerated: pl.AlbumCover == "" && pl.GeneratedCover != "",
+		TrackIDs:       ids,
+		Entries:        entries,
+		Version:        pl.Version,
+		Smart:          toSmartPlaylistResponse(pl.Smart),
+		Collaborators:  collaborators,
+		Visibility:     pl.Visibility,
+		ForkedFrom:     forkedFrom,
+		FollowerCount:  pl.FollowerCount,
+		LikeCount:      pl.LikeCount,
+		Kind:           pl.Kind,
+		CreatedAt:      pl.CreatedAt.String(),
+		UpdatedAt:      pl.UpdatedAt.String(),
+	}
+}
+
+func ToExpandedPlaylistResponse(pl *models.ExpandedPlaylist) dto.ExpandedPlaylistResponse {
+	items := make([]dto.PlaylistItemResponse, len(pl.Items))
+	for i, item := range pl.Items {
+		items[i] = dto.PlaylistItemResponse{
+			EntryID:  item.Entry.ID.Hex(),
+			Position: i,
+			AddedAt:  item.Entry.AddedAt,
+			AddedBy:  entryAddedBy(&pl.Playlist, item.Entry),
+			TrackID:  item.Entry.TrackID.Hex(),
+			Deleted:  item.Track == nil,
+		}
+		if item.Track == nil {
+			continue
+		}
+
+		track := &dto.PlaylistTrackResponse{TrackResponse: ToTrackResponse(&item.Track.Track)}
+		if a := item.Track.AlbumInfo; a != nil {
+			track.AlbumInfo = &dto.PlaylistAlbumResponse{
+				ID:          a.ID.Hex(),
+				Title:       a.Title,
+				Artist:      a.Artist,
+				ReleaseYear: a.ReleaseYear,
+				Cover:       a.Cover,
+			}
+		}
+		if a := item.Track.ArtistProfile; a != nil {
+			track.ArtistProfile = &dto.PlaylistArtistResponse{ID: a.ID.Hex(), Name: a.Name, Avatar: a.Avatar}
+		}
+		items[i].Track = track
+	}
+
+	return dto.ExpandedPlaylistResponse{
+		PlaylistResponse: ToPlaylistResponse(&pl.Playlist),
+		TrackCount:       pl.TrackCount,
+		TotalDuration:    pl.TotalDuration,
+		Tracks:           items,
+	}
+}
+
+func ToSmartRuleModel(r dto.SmartRule) models.SmartRule {
+	rule := models.SmartRule{
+		Combinator: r.Combinator,
+		Field:      r.Field,
+		Operator:   r.Operator,
+		Value:      r.Value,
+	}
+	if r.Rules != nil {
+		rule.Rules = make([]models.SmartRule, len(r.Rules))
+		for i, child := range r.Rules {
+			rule.Rules[i] = ToSmartRuleModel(child)
+		}
+	}
+	return rule
+}
+
+func ToSmartRuleDTO(r models.SmartRule) dto.SmartRule {
+	rule := dto.SmartRule{
+		Combinator: r.Combinator,
+		Field:      r.Field,
+		Operator:   r.Operator,
+		Value:      r.Value,
+	}
+	if r.Rules != nil {
+		rule.Rules = make([]dto.SmartRule, len(r.Rules))
+		for i, child := range r.Rules {
+			rule.Rules[i] = ToSmartRuleDTO(child)
+		}
+	}
+	return rule
+}
+
+func toSmartPlaylistResponse(smart *models.SmartPlaylist) *dto.SmartPlaylistResponse {
+	if smart == nil {
+		return nil
+	}
+	order := "asc"
+	if smart.Desc {
+		order = "desc"
+	}
+	return &dto.SmartPlaylistResponse{
+		Rules: ToSmartRuleDTO(smart.Rules),
+		Sort:  smart.Sort,
+		Order: order,
+		Limit: smart.Limit,
+	}
+}
+
+// entryAddedBy attributes entries from before attribution existed to the owner.
+func entryAddedBy(pl *models.Playlist, e models.PlaylistEntry) string {
+	if e.AddedBy != nil {
+		return e.AddedBy.Hex()
 	}
+	return pl.UserID.Hex()
 }
@@ -0,0 +1,42 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+)
+
+func ToPlaylistVersionSummaryResponse(v *models.PlaylistVersion) dto.PlaylistVersionSummaryResponse {
+	var changedBy *string
+	if v.ChangedBy != nil {
+		hex := v.ChangedBy.Hex()
+		changedBy = &hex
+	}
+
+	return dto.PlaylistVersionSummaryResponse{
+		Version:      v.Version,
+		Title:        v.Title,
+		AlbumCover:   v.AlbumCover,
+		TrackCount:   len(v.Entries),
+		Smart:        v.Smart != nil,
+		ChangedBy:    changedBy,
+		RestoredFrom: v.RestoredFrom,
+		CreatedAt:    v.CreatedAt,
+	}
+}
+
+func ToPlaylistVersionResponse(v *models.PlaylistVersion) dto.PlaylistVersionResponse {
+	entries := make([]dto.PlaylistEntryResponse, len(v.Entries))
+	for i, e := range v.Entries {
+		entry := dto.PlaylistEntryResponse{ID: e.ID.Hex(), TrackID: e.TrackID.Hex(), AddedAt: e.AddedAt}
+		if e.AddedBy != nil {
+			entry.AddedBy = e.AddedBy.Hex()
+		}
+		entries[i] = entry
+	}
+
+	return dto.PlaylistVersionResponse{
+		PlaylistVersionSummaryResponse: ToPlaylistVersionSummaryResponse(v),
+		Entries:                        entries,
+		Rules:                          toSmartPlaylistResponse(v.Smart),
+	}
+}
@@ -0,0 +1,18 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+)
+
+func ToRecommendedTrackResponse(item models.RecommendedTrack, track *models.Track) dto.RecommendedTrackResponse {
+	resp := dto.RecommendedTrackResponse{
+		Track:  ToTrackResponse(track),
+		Score:  item.Score,
+		Reason: item.Reason,
+	}
+	if item.BecauseOf != nil {
+		resp.BecauseOf = item.BecauseOf.Hex()
+	}
+	return resp
+}
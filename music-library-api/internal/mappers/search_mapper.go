@@ -0,0 +1,22 @@
+package mappers
+
+import (
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+	"music-library-api/pkg/utils"
+)
+
+func ToSuggestionResponse(e models.SuggestEntry, terms []string) dto.SuggestionResponse {
+	id := ""
+	if !e.ID.IsZero() {
+		id = e.ID.Hex()
+	}
+	highlight, _ := utils.Highlight(e.Text, terms, highlightPre, highlightPost)
+
+	return dto.SuggestionResponse{
+		ID:        id,
+		Text:      e.Text,
+		Subtitle:  e.Subtitle,
+		Highlight: highlight,
+	}
+}
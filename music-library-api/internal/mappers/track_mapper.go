@@ -3,9 +3,25 @@ package mappers
 import (
 	"music-library-api/internal/dto"
 	"music-library-api/internal/models"
+	"music-library-api/pkg/utils"
+)
+
+const (
+	highlightPre  = "<em>"
+	highlightPost = "</em>"
+	snippetWidth  = 80
 )
 
 func ToTrackResponse(m *models.Track) dto.TrackResponse {
+	albumID := ""
+	if m.AlbumID != nil {
+		albumID = m.AlbumID.Hex()
+	}
+	genreID := ""
+	if m.GenreID != nil {
+		genreID = m.GenreID.Hex()
+	}
+
 	return dto.TrackResponse{
 		ID:          m.ID.Hex(),
 		CreatedAt:   m.CreatedAt,
@@ -14,9 +30,79 @@ func ToTrackResponse(m *models.Track) dto.TrackResponse {
 		Artist:      m.Artist,
 		Album:       m.Album,
 		Genre:       m.Genre,
+		GenreID:     genreID,
 		ReleaseYear: m.ReleaseYear,
 		Duration:    m.Duration,
 		FileID:      m.FileID.Hex(),
 		UserID:      m.UserID.Hex(),
+		AlbumID:     albumID,
+		DiscNumber:  m.DiscNumber,
+		TrackNumber: m.TrackNumber,
+		PlayCount:   m.PlayCount,
+		LikeCount:   m.LikeCount,
+		ISRC:        m.ISRC,
+		Composers:   nonNilStrings(m.Composers),
+		Lyricists:   nonNilStrings(m.Lyricists),
+		Producers:   nonNilStrings(m.Producers),
+		Copyright:   m.Copyright,
+		License:     m.License,
+		Explicit:    m.Explicit,
+		Tags:        nonNilStrings(m.Tags),
+	}
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// ToTrackSearchResult maps a search hit, highlighting the query words in each matched field.
+func ToTrackSearchResult(hit *models.TrackSearchHit, terms []string) dto.TrackSearchResult {
+	highlights := map[string]string{}
+	fields := map[string]string{
+		"title":  hit.Title,
+		"artist": hit.Artist,
+		"album":  hit.Album,
+		"genre":  hit.Genre,
+	}
+	for name, value := range fields {
+		if highlighted, ok := utils.Highlight(value, terms, highlightPre, highlightPost); ok {
+			highlights[name] = highlighted
+		}
+	}
+	if snippet := utils.Snippet(hit.LyricsText, terms, snippetWidth, highlightPre, highlightPost); snippet != "" {
+		highlights["lyrics"] = snippet
+	}
+
+	return dto.TrackSearchResult{
+		TrackResponse: ToTrackResponse(&hit.Track),
+		Score:         hit.Score,
+		Highlights:    highlights,
+	}
+}
+
+func ToTrackFacetsResponse(f *models.TrackFacets) dto.TrackFacetsResponse {
+	counts := func(in []models.FacetCount) []dto.FacetCountResponse {
+		out := make([]dto.FacetCountResponse, len(in))
+		for i, c := range in {
+			out[i] = dto.FacetCountResponse{Value: c.Value, Count: c.Count}
+		}
+		return out
+	}
+
+	durations := make([]dto.DurationFacetResponse, len(f.Durations))
+	for i, d := range f.Durations {
+		durations[i] = dto.DurationFacetResponse{Min: d.Min, Max: d.Max, Count: d.Count}
+	}
+
+	return dto.TrackFacetsResponse{
+		Genres:    counts(f.Genres),
+		Artists:   counts(f.Artists),
+		Years:     counts(f.Years),
+		Durations: durations,
+		Tags:      counts(f.Tags),
+		Uploaders: counts(f.Uploaders),
 	}
 }
@@ -47,6 +47,21 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	}
 }
 
+// StreamAuthMiddleware is AuthMiddleware for Server-Sent Events. Browsers
+// cannot set headers on an EventSource, so the token may also be passed as
+// the access_token query parameter.
+func StreamAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	auth := AuthMiddleware(cfg)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		auth(c)
+	}
+}
+
 // OptionalAuthMiddleware tries to parse JWT but does NOT abort if missing/invalid.
 // It sets user_id and role in context only if a valid token is present.
 func OptionalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Album struct {
+	mgm.DefaultModel `bson:",inline"`
+	UserID           primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Title            string             `bson:"title" json:"title"`
+	Artist           string             `bson:"artist" json:"artist"`
+	ReleaseYear      int                `bson:"release_year" json:"release_year"`
+	Cover            string             `bson:"cover" json:"cover"` // image URL
+	Discs            []AlbumDisc        `bson:"discs" json:"discs"`
+	Tracks           []AlbumTrack       `bson:"tracks" json:"tracks"` // ordered by disc, then track number
+}
+
+// AlbumDisc carries per-disc metadata such as "Disc 2: Live at HUB".
+type AlbumDisc struct {
+	Number   int    `bson:"number" json:"number"`
+	Subtitle string `bson:"subtitle" json:"subtitle"`
+}
+
+type AlbumTrack struct {
+	TrackID     primitive.ObjectID `bson:"track_id" json:"track_id"`
+	DiscNumber  int                `bson:"disc_number" json:"disc_number"`
+	TrackNumber int                `bson:"track_number" json:"track_number"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ArtistProfile is the public page of an artist, managed by a User with RoleArtist.
+type ArtistProfile struct {
+	mgm.DefaultModel `bson:",inline"`
+	UserID           primitive.ObjectID `bson:"user_id" json:"user_id"` // managing account
+	Name             string             `bson:"name" json:"name"`
+	Bio              string             `bson:"bio" json:"bio"`
+	Avatar           string             `bson:"avatar" json:"avatar"` // image URL
+	Links            []ArtistLink       `bson:"links" json:"links"`
+}
+
+type ArtistLink struct {
+	Label string `bson:"label" json:"label"`
+	URL   string `bson:"url" json:"url"`
+}
+
+// CollectionName keeps artist profiles in "artists" instead of mgm's default "artist_profiles".
+func (a *ArtistProfile) CollectionName() string {
+	return "artists"
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Genre struct {
+	mgm.DefaultModel `bson:",inline"`
+	Slug             string               `bson:"slug" json:"slug"`
+	Name             string               `bson:"name" json:"name"`
+	ParentID         *primitive.ObjectID  `bson:"parent_id" json:"parent_id"` // nil for top-level genres
+	Ancestors        []primitive.ObjectID `bson:"ancestors" json:"ancestors"` // root first, used for sub-genre lookups
+	Aliases          []string             `bson:"aliases" json:"aliases"`
+	DisplayNames     map[string]string    `bson:"display_names" json:"display_names"` // locale -> name, e.g. "vi": "Nhạc Trẻ"
+	Keys             []string             `bson:"keys" json:"-"`                      // normalized slug, name, aliases and display names
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// What can be liked.
+const (
+	LikeTargetTrack    = "track"
+	LikeTargetAlbum    = "album"
+	LikeTargetArtist   = "artist" // TargetID is the artist's user ID, which exists even without a profile
+	LikeTargetPlaylist = "playlist"
+)
+
+// Like is a user marking a track, album, artist or playlist as a favourite.
+type Like struct {
+	mgm.DefaultModel `bson:",inline"`
+	UserID           primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TargetType       string             `bson:"target_type" json:"target_type"` // LikeTarget*
+	TargetID         primitive.ObjectID `bson:"target_id" json:"target_id"`
+}
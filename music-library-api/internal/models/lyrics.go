@@ -0,0 +1,30 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	LyricsSourceManual = "manual"
+	LyricsSourceLRC    = "lrc"
+	LyricsSourceUSLT   = "id3_uslt"
+	LyricsSourceSYLT   = "id3_sylt"
+)
+
+// Lyrics of a track in one language. Synced lyrics carry timed Lines,
+// plain lyrics only Text.
+type Lyrics struct {
+	mgm.DefaultModel `bson:",inline"`
+	TrackID          primitive.ObjectID `bson:"track_id" json:"track_id"`
+	Language         string             `bson:"language" json:"language"` // ISO 639 code, "und" if unknown
+	Synced           bool               `bson:"synced" json:"synced"`
+	Text             string             `bson:"text" json:"text"`
+	Lines            []LyricLine        `bson:"lines" json:"lines"`
+	Source           string             `bson:"source" json:"source"`
+}
+
+type LyricLine struct {
+	TimeMs int64  `bson:"time_ms" json:"time_ms"` // offset from track start in milliseconds
+	Text   string `bson:"text" json:"text"`
+}
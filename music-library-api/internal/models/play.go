@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	PlayEventNowPlaying = "now_playing"
+	PlayEventPlayed     = "played"
+)
+
+// Play is one counted play of a track. Plays are stored in a time-series
+// collection, which has no updated_at, so Play does not embed mgm.DefaultModel.
+type Play struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Meta     PlayMeta           `bson:"meta" json:"meta"`
+	PlayedAt time.Time          `bson:"played_at" json:"played_at"` // when playback started
+	PlayedMs int64              `bson:"played_ms" json:"played_ms"` // time actually listened
+}
+
+// PlayMeta is the time-series metadata, which groups plays into buckets.
+type PlayMeta struct {
+	UserID  primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TrackID primitive.ObjectID `bson:"track_id" json:"track_id"`
+}
+
+// UserTrackPlays rolls up the plays of one track by one user.
+type UserTrackPlays struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TrackID      primitive.ObjectID `bson:"track_id" json:"track_id"`
+	Count        int64              `bson:"count" json:"count"`
+	LastPlayedAt time.Time          `bson:"last_played_at" json:"last_played_at"`
+}
+
+// NowPlaying is the track a user is listening to, until it would have ended.
+type NowPlaying struct {
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TrackID   primitive.ObjectID `bson:"track_id" json:"track_id"`
+	StartedAt time.Time          `bson:"started_at" json:"started_at"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	RepeatOff = "off"
+	RepeatAll = "all" // wrap around to the first track at the end
+	RepeatOne = "one" // replay the current track when it ends
+)
+
+// Where a queue was built from.
+const (
+	PlayQueueSourcePlaylist = "playlist"
+	PlayQueueSourceAlbum    = "album"
+	PlayQueueSourceTracks   = "tracks"
+)
+
+// PlayQueue is a user's playback state, shared by all their devices. There
+// is one per user.
+type PlayQueue struct {
+	mgm.DefaultModel `bson:",inline"`
+	UserID           primitive.ObjectID   `bson:"user_id" json:"user_id"`
+	Items            []PlayQueueItem      `bson:"items" json:"items"` // in the original, unshuffled order
+	Shuffle          bool                 `bson:"shuffle" json:"shuffle"`
+	ShuffleOrder     []primitive.ObjectID `bson:"shuffle_order" json:"shuffle_order"` // item IDs in play order when shuffling
+	CurrentID        *primitive.ObjectID  `bson:"current_id" json:"current_id"`       // nil when nothing is playing
+	PositionMs       int64                `bson:"position_ms" json:"position_ms"`
+	PositionAt       time.Time            `bson:"position_at" json:"position_at"` // when PositionMs was reported
+	Playing          bool                 `bson:"playing" json:"playing"`
+	Repeat           string               `bson:"repeat" json:"repeat"` // Repeat*
+	Source           *PlayQueueSource     `bson:"source" json:"source"`
+	Device           string               `bson:"device" json:"device"`   // device that made the last change
+	Version          int64                `bson:"version" json:"version"` // bumped on every change
+}
+
+// PlayQueueItem is one track in the queue. Items have their own ID because
+// a track may be queued more than once.
+type PlayQueueItem struct {
+	ID      primitive.ObjectID `bson:"_id" json:"id"`
+	TrackID primitive.ObjectID `bson:"track_id" json:"track_id"`
+}
+
+type PlayQueueSource struct {
+	Type string              `bson:"type" json:"type"` // PlayQueueSource*
+	ID   *primitive.ObjectID `bson:"id" json:"id"`     // nil for a list of tracks
+}
+
+func NewPlayQueueItems(trackIDs []primitive.ObjectID) []PlayQueueItem {
+	items := make([]PlayQueueItem, len(trackIDs))
+	for i, id := range trackIDs {
+		items[i] = PlayQueueItem{ID: primitive.NewObjectID(), TrackID: id}
+	}
+	return items
+}
+
+// PlayOrder returns the items in the order they play, shuffled or not.
+func (q *PlayQueue) PlayOrder() []PlayQueueItem {
+	if !q.Shuffle {
+		return q.Items
+	}
+	byID := make(map[primitive.ObjectID]PlayQueueItem, len(q.Items))
+	for _, item := range q.Items {
+		byID[item.ID] = item
+	}
+	order := make([]PlayQueueItem, 0, len(q.ShuffleOrder))
+	for _, id := range q.ShuffleOrder {
+		if item, ok := byID[id]; ok {
+			order = append(order, item)
+		}
+	}
+	return order
+}
+
+// CurrentIndex is the position of the current item in PlayOrder, or -1.
+func (q *PlayQueue) CurrentIndex() int {
+	if q.CurrentID == nil {
+		return -1
+	}
+	for i, item := range q.PlayOrder() {
+		if item.ID == *q.CurrentID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Saving is called by mgm before every create and update.
+func (q *PlayQueue) Saving() error {
+	if q.Items == nil {
+		q.Items = []PlayQueueItem{}
+	}
+	if q.Repeat == "" {
+		q.Repeat = RepeatOff
+	}
+	return q.DefaultModel.Saving()
+}
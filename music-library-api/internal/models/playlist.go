@@ -1,14 +1,166 @@
 package models
 
 import (
+	"crypto/subtle"
+	"time"
+
 	"github.com/kamva/mgm/v3"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Playlist struct {
 	mgm.DefaultModel `bson:",inline"`
-	UserID           primitive.ObjectID   `bson:"user_id" json:"user_id"`
-	Title            string               `bson:"title" json:"title"`
-	AlbumCover       string               `bson:"album_cover" json:"album_cover"` // image URL
-	TrackIDs         []primitive.ObjectID `bson:"track_ids" json:"track_ids"`
+	UserID           primitive.ObjectID     `bson:"user_id" json:"user_id"`
+	Title            string                 `bson:"title" json:"title"`
+	AlbumCover       string                 `bson:"album_cover" json:"album_cover"` // image URL
+	Entries          []PlaylistEntry        `bson:"entries" json:"entries"`         // ordered, may repeat a track
+	TrackIDs         []primitive.ObjectID   `bson:"track_ids" json:"track_ids"`     // mirrors Entries, kept for existing readers
+	Version          int64                  `bson:"version" json:"version"`         // bumped on every change, for optimistic concurrency
+	Smart            *SmartPlaylist         `bson:"smart" json:"smart"`             // nil for ordinary playlists
+	Collaborators    []PlaylistCollaborator `bson:"collaborators" json:"collaborators"`
+	Visibility       string                 `bson:"visibility" json:"visibility"` // PlaylistVisibility*
+	ShareLinks       []PlaylistShareLink    `bson:"share_links" json:"-"`
+	ForkedFrom       *PlaylistForkSource    `bson:"forked_from" json:"forked_from"` // nil unless the playlist is a fork
+	Kind             string                 `bson:"kind,omitempty" json:"kind"`     // PlaylistKind*; empty for ordinary playlists
+	FollowerCount    int64                  `bson:"-" json:"follower_count"`        // counted on read from playlist_follows
+	LikeCount        int64                  `bson:"-" json:"like_count"`            // counted on read from likes
+	GeneratedCover   string                 `bson:"-" json:"-"`                     // read from playlist_covers
+}
+
+// PlaylistKindLikedSongs is the playlist holding a user's liked tracks. Like
+// a smart playlist, its entries are computed on read, from the likes.
+const PlaylistKindLikedSongs = "liked_songs"
+
+func (p *Playlist) IsLikedSongs() bool {
+	return p.Kind == PlaylistKindLikedSongs
+}
+
+// Cover is the image shown for the playlist: the uploaded cover, otherwise
+// the one generated from its albums.
+func (p *Playlist) Cover() string {
+	if p.AlbumCover != "" {
+		return p.AlbumCover
+	}
+	return p.GeneratedCover
+}
+
+// PlaylistForkSource records the playlist a fork was copied from. TrackIDs are
+// the source's tracks at the last sync, the common base when pulling changes.
+type PlaylistForkSource struct {
+	PlaylistID primitive.ObjectID   `bson:"playlist_id" json:"playlist_id"`
+	Version    int64                `bson:"version" json:"version"` // source version at the last sync
+	TrackIDs   []primitive.ObjectID `bson:"track_ids" json:"track_ids"`
+	SyncedAt   time.Time            `bson:"synced_at" json:"synced_at"`
+}
+
+// Who can read a playlist besides its owner and collaborators. Unlisted
+// playlists are readable by ID but left out of listings.
+const (
+	PlaylistVisibilityPublic   = "public"
+	PlaylistVisibilityUnlisted = "unlisted"
+	PlaylistVisibilityPrivate  = "private"
+)
+
+// PlaylistShareLink grants read-only access to anyone holding its token,
+// until it expires or the owner revokes it.
+type PlaylistShareLink struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Token     string             `bson:"token" json:"-"`
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt *time.Time         `bson:"expires_at" json:"expires_at"` // nil = never
+}
+
+func (l *PlaylistShareLink) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// ShareLink returns the unexpired share link with this token, or nil.
+func (p *Playlist) ShareLink(token string, now time.Time) *PlaylistShareLink {
+	if token == "" {
+		return nil
+	}
+	for i := range p.ShareLinks {
+		l := &p.ShareLinks[i]
+		if subtle.ConstantTimeCompare([]byte(l.Token), []byte(token)) == 1 && !l.Expired(now) {
+			return l
+		}
+	}
+	return nil
+}
+
+// Roles on a playlist, from least to most access. The owner is Playlist.UserID;
+// editors and viewers are collaborators who accepted an invitation.
+const (
+	PlaylistRoleViewer = "viewer"
+	PlaylistRoleEditor = "editor"
+	PlaylistRoleOwner  = "owner"
+)
+
+type PlaylistCollaborator struct {
+	UserID  primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Role    string             `bson:"role" json:"role"` // PlaylistRoleEditor or PlaylistRoleViewer
+	AddedAt time.Time          `bson:"added_at" json:"added_at"`
+}
+
+// Collaborator returns the collaborator entry of a user, or nil.
+func (p *Playlist) Collaborator(userID primitive.ObjectID) *PlaylistCollaborator {
+	for i := range p.Collaborators {
+		if p.Collaborators[i].UserID == userID {
+			return &p.Collaborators[i]
+		}
+	}
+	return nil
+}
+
+// PlaylistEntry is one position in a playlist. Its ID stays the same when the
+// entry is moved, so clients can address a specific copy of a repeated track.
+type PlaylistEntry struct {
+	ID      primitive.ObjectID  `bson:"_id" json:"id"`
+	TrackID primitive.ObjectID  `bson:"track_id" json:"track_id"`
+	AddedAt time.Time           `bson:"added_at" json:"added_at"`
+	AddedBy *primitive.ObjectID `bson:"added_by" json:"added_by"` // nil for entries from before attribution
+}
+
+// NewPlaylistEntries wraps track IDs into new entries added by addedBy at addedAt.
+func NewPlaylistEntries(trackIDs []primitive.ObjectID, addedAt time.Time, addedBy *primitive.ObjectID) []PlaylistEntry {
+	entries := make([]PlaylistEntry, len(trackIDs))
+	for i, id := range trackIDs {
+		entries[i] = PlaylistEntry{ID: primitive.NewObjectID(), TrackID: id, AddedAt: addedAt, AddedBy: addedBy}
+	}
+	return entries
+}
+
+// Saving is called by mgm before every create and update.
+func (p *Playlist) Saving() error {
+	p.SyncTrackIDs()
+	if p.Collaborators == nil {
+		// Stored as arrays so collaborators and share links can be $push-ed
+		p.Collaborators = []PlaylistCollaborator{}
+	}
+	if p.ShareLinks == nil {
+		p.ShareLinks = []PlaylistShareLink{}
+	}
+	if p.Visibility == "" {
+		p.Visibility = PlaylistVisibilityPublic
+	}
+	return p.DefaultModel.Saving()
+}
+
+// SyncTrackIDs rebuilds TrackIDs from Entries. Playlists saved before entries
+// existed get one entry per track first.
+func (p *Playlist) SyncTrackIDs() {
+	if p.Entries == nil {
+		p.Entries = NewPlaylistEntries(p.TrackIDs, p.CreatedAt, nil)
+	}
+	p.TrackIDs = make([]primitive.ObjectID, len(p.Entries))
+	for i, e := range p.Entries {
+		p.TrackIDs[i] = e.TrackID
+	}
+}
+
+// PlaylistFilter narrows playlist listings. Zero values mean "no filter".
+type PlaylistFilter struct {
+	MemberID   string // playlists this user owns or collaborates on
+	PublicOnly bool
 }
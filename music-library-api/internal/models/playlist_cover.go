@@ -0,0 +1,17 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PlaylistCover is the cover generated for a playlist from the artwork of its
+// first albums: a 2x2 mosaic when there are four of them, otherwise the
+// first album's cover. It is only shown when the playlist has no uploaded
+// cover of its own.
+type PlaylistCover struct {
+	mgm.DefaultModel `bson:",inline"`
+	PlaylistID       primitive.ObjectID   `bson:"playlist_id" json:"playlist_id"`
+	URL              string               `bson:"url" json:"url"`             // empty when no album has artwork
+	AlbumIDs         []primitive.ObjectID `bson:"album_ids" json:"album_ids"` // the albums the cover was built from
+}
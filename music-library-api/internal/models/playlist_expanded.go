@@ -0,0 +1,23 @@
+package models
+
+// ExpandedPlaylist is a playlist with its entries joined to their tracks, in
+// playlist order.
+type ExpandedPlaylist struct {
+	Playlist      `bson:",inline"`
+	Items         []PlaylistItem `bson:"items"`
+	TrackCount    int            `bson:"track_count"`
+	TotalDuration int            `bson:"total_duration"` // in seconds, deleted tracks excluded
+}
+
+// PlaylistItem is one entry of an ExpandedPlaylist. Track is nil when the
+// track has been deleted since it was added.
+type PlaylistItem struct {
+	Entry PlaylistEntry      `bson:"entry"`
+	Track *PlaylistItemTrack `bson:"track"`
+}
+
+type PlaylistItemTrack struct {
+	Track         `bson:",inline"`
+	AlbumInfo     *Album         `bson:"album_info"`     // nil for singles
+	ArtistProfile *ArtistProfile `bson:"artist_profile"` // uploader's profile, nil if never saved
+}
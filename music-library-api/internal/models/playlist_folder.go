@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PlaylistFolder groups a user's playlists. Folders nest through ParentID and
+// are ordered by Position among their siblings. A playlist is in at most one
+// folder of each user; the others are at the root of their tree.
+type PlaylistFolder struct {
+	mgm.DefaultModel `bson:",inline"`
+	UserID           primitive.ObjectID   `bson:"user_id" json:"user_id"`
+	Name             string               `bson:"name" json:"name"`
+	ParentID         *primitive.ObjectID  `bson:"parent_id" json:"parent_id"` // nil = top level
+	Position         int                  `bson:"position" json:"position"`
+	PlaylistIDs      []primitive.ObjectID `bson:"playlist_ids" json:"playlist_ids"` // in display order
+}
+
+func (f *PlaylistFolder) Saving() error {
+	if f.PlaylistIDs == nil {
+		f.PlaylistIDs = []primitive.ObjectID{}
+	}
+	return f.DefaultModel.Saving()
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PlaylistFollow is a user following a playlist they do not own.
+type PlaylistFollow struct {
+	mgm.DefaultModel `bson:",inline"`
+	PlaylistID       primitive.ObjectID `bson:"playlist_id" json:"playlist_id"`
+	UserID           primitive.ObjectID `bson:"user_id" json:"user_id"`
+}
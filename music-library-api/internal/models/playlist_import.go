@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ImportMatched   = "matched"
+	ImportAmbiguous = "ambiguous"
+	ImportUnmatched = "unmatched"
+)
+
+// How an import entry was matched, from most to least confident
+const (
+	ImportMatchPath        = "path"         // the location is one of our track URLs
+	ImportMatchBasename    = "basename"     // file name of the uploaded mp3
+	ImportMatchArtistTitle = "artist_title" // same artist and title
+	ImportMatchDuration    = "duration"     // same title, duration within tolerance
+	ImportMatchManual      = "manual"       // picked by the user
+)
+
+// PlaylistImport is the report of a playlist file import. It is kept so the
+// user can resolve ambiguous entries after the playlist has been created.
+type PlaylistImport struct {
+	mgm.DefaultModel `bson:",inline"`
+	UserID           primitive.ObjectID    `bson:"user_id" json:"user_id"`
+	PlaylistID       primitive.ObjectID    `bson:"playlist_id" json:"playlist_id"`
+	FileName         string                `bson:"file_name" json:"file_name"`
+	Format           string                `bson:"format" json:"format"` // m3u, pls, xspf or csv
+	Entries          []PlaylistImportEntry `bson:"entries" json:"entries"`
+}
+
+// PlaylistImportEntry is one line of the imported file and how it was resolved.
+type PlaylistImportEntry struct {
+	Index      int                  `bson:"index" json:"index"` // 0-based line in the file
+	Location   string               `bson:"location" json:"location"`
+	Title      string               `bson:"title" json:"title"`
+	Artist     string               `bson:"artist" json:"artist"`
+	Album      string               `bson:"album" json:"album"`
+	Duration   int                  `bson:"duration" json:"duration"` // in seconds, 0 = unknown
+	Status     string               `bson:"status" json:"status"`     // one of the Import* statuses
+	Method     string               `bson:"method" json:"method"`     // one of the ImportMatch* methods
+	TrackID    *primitive.ObjectID  `bson:"track_id" json:"track_id"`
+	Candidates []primitive.ObjectID `bson:"candidates" json:"candidates"` // for ambiguous entries
+	EntryID    *primitive.ObjectID  `bson:"entry_id" json:"entry_id"`     // playlist entry created for it
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+)
+
+// PlaylistInvitation invites a user to collaborate on a playlist. The user
+// becomes a collaborator only once they accept it.
+type PlaylistInvitation struct {
+	mgm.DefaultModel `bson:",inline"`
+	PlaylistID       primitive.ObjectID `bson:"playlist_id" json:"playlist_id"`
+	InviteeID        primitive.ObjectID `bson:"invitee_id" json:"invitee_id"`
+	InviterID        primitive.ObjectID `bson:"inviter_id" json:"inviter_id"`
+	Role             string             `bson:"role" json:"role"`     // PlaylistRoleEditor or PlaylistRoleViewer
+	Status           string             `bson:"status" json:"status"` // one of the Invitation* statuses
+	RespondedAt      *time.Time         `bson:"responded_at" json:"responded_at"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPlaylistSyncTrackIDs(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		playlist Playlist
+		want     []primitive.ObjectID
+		entries  int
+	}{
+		{
+			name:     "entries drive track IDs",
+			playlist: Playlist{Entries: []PlaylistEntry{{TrackID: b}, {TrackID: a}, {TrackID: b}}, TrackIDs: []primitive.ObjectID{a}},
+			want:     []primitive.ObjectID{b, a, b},
+			entries:  3,
+		},
+		{
+			name:     "legacy playlist gets one entry per track",
+			playlist: Playlist{TrackIDs: []primitive.ObjectID{a, b}},
+			want:     []primitive.ObjectID{a, b},
+			entries:  2,
+		},
+		{
+			name:     "emptied playlist stays empty",
+			playlist: Playlist{Entries: []PlaylistEntry{}, TrackIDs: []primitive.ObjectID{a}},
+			want:     []primitive.ObjectID{},
+			entries:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := tt.playlist
+			pl.CreatedAt = created
+			pl.SyncTrackIDs()
+			if !reflect.DeepEqual(pl.TrackIDs, tt.want) {
+				t.Errorf("TrackIDs = %v, want %v", pl.TrackIDs, tt.want)
+			}
+			if len(pl.Entries) != tt.entries {
+				t.Errorf("len(Entries) = %d, want %d", len(pl.Entries), tt.entries)
+			}
+		})
+	}
+}
+
+func TestNewPlaylistEntries(t *testing.T) {
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	ids[2] = ids[0] // the same track twice still gets two entries
+	user := primitive.NewObjectID()
+	at := time.Now()
+
+	entries := NewPlaylistEntries(ids, at, &user)
+	seen := map[primitive.ObjectID]bool{}
+	for i, e := range entries {
+		if e.TrackID != ids[i] || !e.AddedAt.Equal(at) || e.AddedBy == nil || *e.AddedBy != user {
+			t.Errorf("entry %d = %+v", i, e)
+		}
+		if e.ID.IsZero() || seen[e.ID] {
+			t.Errorf("entry %d has a missing or duplicate ID %s", i, e.ID.Hex())
+		}
+		seen[e.ID] = true
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PlaylistVersion is a snapshot of a playlist's title, cover and entries (or
+// smart rules) as saved at one playlist version. A snapshot is taken on every
+// change to them, so any earlier state can be compared or restored.
+type PlaylistVersion struct {
+	mgm.DefaultModel `bson:",inline"`
+	PlaylistID       primitive.ObjectID  `bson:"playlist_id" json:"playlist_id"`
+	Version          int64               `bson:"version" json:"version"` // Playlist.Version after the change
+	Title            string              `bson:"title" json:"title"`
+	AlbumCover       string              `bson:"album_cover" json:"album_cover"`
+	Entries          []PlaylistEntry     `bson:"entries" json:"entries"`
+	Smart            *SmartPlaylist      `bson:"smart" json:"smart"`
+	ChangedBy        *primitive.ObjectID `bson:"changed_by" json:"changed_by"`
+	RestoredFrom     *int64              `bson:"restored_from" json:"restored_from"` // set when the change was a restore
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// What a Recommendation is for: a user's feed or the tracks similar to a track.
+const (
+	RecommendationKindUser  = "user"
+	RecommendationKindTrack = "track"
+)
+
+// Why a track was recommended.
+const (
+	RecommendationReasonListeners = "listened_together" // played or liked by the same people
+	RecommendationReasonContent   = "similar_style"     // same artist, genre or tags
+	RecommendationReasonPopular   = "popular"           // fallback when there is not enough history
+)
+
+// Recommendation is the output of the recommendation job for one user or
+// track, best first. Each run replaces it.
+type Recommendation struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Kind        string             `bson:"kind" json:"kind"`             // RecommendationKind*
+	SubjectID   primitive.ObjectID `bson:"subject_id" json:"subject_id"` // the user or track
+	Items       []RecommendedTrack `bson:"items" json:"items"`
+	GeneratedAt time.Time          `bson:"generated_at" json:"generated_at"`
+}
+
+type RecommendedTrack struct {
+	TrackID   primitive.ObjectID  `bson:"track_id" json:"track_id"`
+	Score     float64             `bson:"score" json:"score"`
+	Reason    string              `bson:"reason" json:"reason"`         // RecommendationReason*
+	BecauseOf *primitive.ObjectID `bson:"because_of" json:"because_of"` // for users, the track of theirs it is most similar to
+}
@@ -0,0 +1,70 @@
+package models
+
+// SmartPlaylist holds the rules of a playlist whose tracks are computed on
+// every read instead of being stored, e.g. "genre is Ballad AND release_year
+// >= 2015, newest first, 50 tracks".
+type SmartPlaylist struct {
+	Rules SmartRule `bson:"rules" json:"rules"`
+	Sort  string    `bson:"sort" json:"sort"` // one of the SmartSort* fields
+	Desc  bool      `bson:"desc" json:"desc"`
+	Limit int       `bson:"limit" json:"limit"`
+}
+
+// SmartRule is a node of the rule tree: either a group combining child rules
+// with "all" (AND) or "any" (OR), or a condition on one track field.
+type SmartRule struct {
+	// Group
+	Combinator string      `bson:"combinator,omitempty" json:"combinator,omitempty"`
+	Rules      []SmartRule `bson:"rules,omitempty" json:"rules,omitempty"`
+
+	// Condition, e.g. {field: "artist", operator: "contains", value: "Tuấn"}
+	Field    string      `bson:"field,omitempty" json:"field,omitempty"`
+	Operator string      `bson:"operator,omitempty" json:"operator,omitempty"`
+	Value    interface{} `bson:"value,omitempty" json:"value,omitempty"` // string, number, bool or a list of them
+}
+
+// IsGroup reports whether the rule combines other rules.
+func (r SmartRule) IsGroup() bool {
+	return r.Combinator != "" || r.Rules != nil
+}
+
+const (
+	SmartAll = "all"
+	SmartAny = "any"
+)
+
+// Condition operators; which ones apply depends on the field
+const (
+	SmartIs          = "is"
+	SmartIsNot       = "is_not"
+	SmartContains    = "contains"
+	SmartNotContains = "not_contains"
+	SmartStartsWith  = "starts_with"
+	SmartEndsWith    = "ends_with"
+	SmartIn          = "in"
+	SmartNotIn       = "not_in"
+	SmartGreater     = "gt"
+	SmartGreaterEq   = "gte"
+	SmartLess        = "lt"
+	SmartLessEq      = "lte"
+	SmartBetween     = "between"
+	SmartBefore      = "before"
+	SmartAfter       = "after"
+	SmartInLast      = "in_last" // days
+)
+
+const (
+	SmartSortTitle       = "title"
+	SmartSortArtist      = "artist"
+	SmartSortAlbum       = "album"
+	SmartSortReleaseYear = "release_year"
+	SmartSortDuration    = "duration"
+	SmartSortPlayCount   = "play_count"
+	SmartSortLikeCount   = "like_count"
+	SmartSortCreatedAt   = "created_at"
+)
+
+const (
+	SmartDefaultLimit = 100
+	SmartMaxLimit     = 500
+)
@@ -0,0 +1,20 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	SuggestTrack    = "track"
+	SuggestArtist   = "artist"
+	SuggestAlbum    = "album"
+	SuggestPlaylist = "playlist"
+	SuggestTag      = "tag"
+)
+
+// SuggestEntry is one autocomplete candidate. Artists and tags have no ID.
+type SuggestEntry struct {
+	Kind     string
+	ID       primitive.ObjectID
+	Text     string
+	Subtitle string  // e.g. the artist of a track or album
+	Weight   float64 // popularity, used to rank equally good matches
+}
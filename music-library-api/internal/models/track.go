@@ -1,20 +1,79 @@
 package models
 
 import (
+	"music-library-api/pkg/utils"
+
 	"github.com/kamva/mgm/v3"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	LicenseAllRightsReserved = "all-rights-reserved"
+	LicenseCC0               = "cc0"
+	LicenseCCBY              = "cc-by"
+	LicenseCCBYSA            = "cc-by-sa"
+	LicenseCCBYND            = "cc-by-nd"
+	LicenseCCBYNC            = "cc-by-nc"
+	LicenseCCBYNCSA          = "cc-by-nc-sa"
+	LicenseCCBYNCND          = "cc-by-nc-nd"
+	LicensePublicDomain      = "public-domain"
+)
+
+// CreativeCommonsLicenses lists every license that counts as Creative Commons.
+var CreativeCommonsLicenses = []string{
+	LicenseCC0, LicenseCCBY, LicenseCCBYSA, LicenseCCBYND,
+	LicenseCCBYNC, LicenseCCBYNCSA, LicenseCCBYNCND,
+}
+
 type Track struct {
-	mgm.DefaultModel `bson:",inline"`   // ID, CreatedAt, UpdatedAt
-	UserID           primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Title            string             `bson:"title" json:"title"`
-	Artist           string             `bson:"artist" json:"artist"`
-	Album            string             `bson:"album" json:"album"`
-	Genre            string             `bson:"genre" json:"genre"`
-	ReleaseYear      int                `bson:"release_year" json:"release_year"`
-	Duration         int                `bson:"duration" json:"duration"` // in seconds
-	URL              string             `bson:"url" json:"url"`           // mp3 URL
-	FileID           primitive.ObjectID `bson:"file_id" json:"file_id"`
+	mgm.DefaultModel `bson:",inline"`    // ID, CreatedAt, UpdatedAt
+	UserID           primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Title            string              `bson:"title" json:"title"`
+	Artist           string              `bson:"artist" json:"artist"`
+	Album            string              `bson:"album" json:"album"`
+	Genre            string              `bson:"genre" json:"genre"`
+	GenreID          *primitive.ObjectID `bson:"genre_id" json:"genre_id"` // nil for genres outside the taxonomy
+	ReleaseYear      int                 `bson:"release_year" json:"release_year"`
+	Duration         int                 `bson:"duration" json:"duration"` // in seconds
+	URL              string              `bson:"url" json:"url"`           // mp3 URL
+	FileID           primitive.ObjectID  `bson:"file_id" json:"file_id"`
+	AlbumID          *primitive.ObjectID `bson:"album_id" json:"album_id"`
+	DiscNumber       int                 `bson:"disc_number" json:"disc_number"`   // from ID3 TPOS
+	TrackNumber      int                 `bson:"track_number" json:"track_number"` // from ID3 TRCK
+	PlayCount        int64               `bson:"play_count" json:"play_count"`     // counted scrobbles, see PlayService.Scrobble; UpdateTrack never writes it
+	LikeCount        int64               `bson:"like_count" json:"like_count"`     // counted likes, see LikeService; UpdateTrack never writes it
+
+	// Credits and licensing
+	ISRC      string   `bson:"isrc" json:"isrc"` // normalized, e.g. "VNA0S2300001"
+	Composers []string `bson:"composers" json:"composers"`
+	Lyricists []string `bson:"lyricists" json:"lyricists"`
+	Producers []string `bson:"producers" json:"producers"`
+	Copyright string   `bson:"copyright" json:"copyright"` // e.g. "℗ 2023 HUB Records"
+	License   string   `bson:"license" json:"license"`     // one of the License* constants
+	Explicit  bool     `bson:"explicit" json:"explicit"`
+
+	Tags []string `bson:"tags" json:"tags"` // lowercase, e.g. "acoustic", "chill"
+
+	Search TrackSearchFields `bson:"search" json:"-"` // refreshed on every save
 	// PlaylistID       primitive.ObjectID `bson:"playlist_id,omitempty" json:"playlist_id"`
 }
+
+// TrackSearchFields holds accent-folded copies of the searchable fields, so
+// "bui anh tuan" matches "Bùi Anh Tuấn".
+type TrackSearchFields struct {
+	Title  string `bson:"title"`
+	Artist string `bson:"artist"`
+	Album  string `bson:"album"`
+	Genre  string `bson:"genre"`
+}
+
+// Saving is called by mgm before every create and update.
+func (t *Track) Saving() error {
+	t.Search = TrackSearchFields{
+		Title:  utils.FoldDiacritics(t.Title),
+		Artist: utils.FoldDiacritics(t.Artist),
+		Album:  utils.FoldDiacritics(t.Album),
+		Genre:  utils.FoldDiacritics(t.Genre),
+	}
+	return t.DefaultModel.Saving()
+}
@@ -0,0 +1,37 @@
+package models
+
+// TrackFilter narrows track listings and searches. Zero values mean "no filter".
+type TrackFilter struct {
+	UserID          string
+	Licenses        []string
+	CreativeCommons bool
+	Explicit        *bool
+	Composer        string
+	Lyricist        string
+	Producer        string
+	ISRC            string
+
+	Genre       string // genre name, ignoring case and accents
+	Artist      string // artist name, ignoring case and accents
+	YearFrom    int
+	YearTo      int
+	DurationMin int // seconds
+	DurationMax int // seconds
+	Tag         string
+	Uploader    string // user ID
+}
+
+const (
+	TrackSortRelevance   = "relevance"
+	TrackSortTitle       = "title"
+	TrackSortArtist      = "artist"
+	TrackSortReleaseYear = "release_year"
+	TrackSortDuration    = "duration"
+	TrackSortCreatedAt   = "created_at"
+)
+
+// TrackSort orders search results by one of the TrackSort* fields.
+type TrackSort struct {
+	Field string
+	Desc  bool
+}
@@ -0,0 +1,31 @@
+package models
+
+// TrackSearchHit is a track matched by SearchTracks with its relevance score.
+type TrackSearchHit struct {
+	Track      `bson:",inline"`
+	Score      float64 `bson:"score"`
+	LyricsText string  `bson:"lyrics_text"`
+}
+
+// FacetCount is the number of matching tracks for one facet value.
+type FacetCount struct {
+	Value string `bson:"_id"`
+	Count int64  `bson:"count"`
+}
+
+// DurationBucket counts matching tracks with Min <= duration < Max (Max 0 = no upper bound).
+type DurationBucket struct {
+	Min   int   `bson:"-"`
+	Max   int   `bson:"-"`
+	Count int64 `bson:"count"`
+}
+
+// TrackFacets holds the facet counts of a search, most frequent values first.
+type TrackFacets struct {
+	Genres    []FacetCount     `bson:"genres"`
+	Artists   []FacetCount     `bson:"artists"`
+	Years     []FacetCount     `bson:"years"`
+	Tags      []FacetCount     `bson:"tags"`
+	Uploaders []FacetCount     `bson:"uploaders"`
+	Durations []DurationBucket `bson:"-"`
+}
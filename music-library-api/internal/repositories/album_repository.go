@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"music-library-api/internal/models"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IAlbumRepository interface {
+	GetAlbumByID(id string) (*models.Album, error)
+	FindAlbum(userID primitive.ObjectID, title, artist string) (*models.Album, error)
+	CreateAlbum(album *models.Album) error
+	UpdateAlbum(album *models.Album) error
+	RemoveTrack(albumID, trackID primitive.ObjectID) error
+	SetCover(albumID primitive.ObjectID, url string) error
+}
+
+type albumRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewAlbumRepository(db *mongo.Database) IAlbumRepository {
+	return &albumRepository{
+		Collection: db.Collection("albums"),
+	}
+}
+
+func (r *albumRepository) GetAlbumByID(id string) (*models.Album, error) {
+	album := &models.Album{}
+	if err := mgm.Coll(album).FindByID(id, album); err != nil {
+		return nil, err
+	}
+	return album, nil
+}
+
+// FindAlbum looks up an uploader's album by title and artist, ignoring case.
+// Returns nil, nil when no album matches.
+func (r *albumRepository) FindAlbum(userID primitive.ObjectID, title, artist string) (*models.Album, error) {
+	album := &models.Album{}
+	opts := options.FindOne().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+
+	err := mgm.Coll(album).FindOne(context.Background(), bson.M{
+		"user_id": userID,
+		"title":   title,
+		"artist":  artist,
+	}, opts).Decode(album)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return album, nil
+}
+
+func (r *albumRepository) CreateAlbum(album *models.Album) error {
+	return mgm.Coll(album).Create(album)
+}
+
+func (r *albumRepository) UpdateAlbum(album *models.Album) error {
+	return mgm.Coll(album).Update(album)
+}
+
+// RemoveTrack pulls a track out of an album's tracklist.
+func (r *albumRepository) RemoveTrack(albumID, trackID primitive.ObjectID) error {
+	_, err := mgm.Coll(&models.Album{}).UpdateOne(
+		context.Background(),
+		bson.M{"_id": albumID},
+		bson.M{"$pull": bson.M{"tracks": bson.M{"track_id": trackID}}},
+	)
+	return err
+}
+
+// SetCover only touches the cover, so it cannot undo a concurrent tracklist update.
+func (r *albumRepository) SetCover(albumID primitive.ObjectID, url string) error {
+	_, err := mgm.Coll(&models.Album{}).UpdateOne(
+		context.Background(),
+		bson.M{"_id": albumID},
+		bson.M{"$set": bson.M{"cover": url}},
+	)
+	return err
+}
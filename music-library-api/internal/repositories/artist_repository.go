@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"music-library-api/internal/models"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IArtistRepository interface {
+	GetArtistByID(id string) (*models.ArtistProfile, error)
+	GetArtistByUserID(userID primitive.ObjectID) (*models.ArtistProfile, error)
+	CreateArtist(artist *models.ArtistProfile) error
+	UpdateArtist(artist *models.ArtistProfile) error
+	EnsureIndexes() error
+}
+
+type artistRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewArtistRepository(db *mongo.Database) IArtistRepository {
+	return &artistRepository{
+		Collection: db.Collection("artists"),
+	}
+}
+
+// EnsureIndexes makes user_id unique: a user manages at most one profile,
+// even when two requests create it at the same time.
+func (r *artistRepository) EnsureIndexes() error {
+	_, err := r.Collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (r *artistRepository) GetArtistByID(id string) (*models.ArtistProfile, error) {
+	artist := &models.ArtistProfile{}
+	if err := mgm.Coll(artist).FindByID(id, artist); err != nil {
+		return nil, err
+	}
+	return artist, nil
+}
+
+// GetArtistByUserID returns the profile managed by a user, or nil, nil if none exists yet.
+func (r *artistRepository) GetArtistByUserID(userID primitive.ObjectID) (*models.ArtistProfile, error) {
+	artist := &models.ArtistProfile{}
+	err := mgm.Coll(artist).First(bson.M{"user_id": userID}, artist)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return artist, nil
+}
+
+func (r *artistRepository) CreateArtist(artist *models.ArtistProfile) error {
+	return mgm.Coll(artist).Create(artist)
+}
+
+func (r *artistRepository) UpdateArtist(artist *models.ArtistProfile) error {
+	return mgm.Coll(artist).Update(artist)
+}
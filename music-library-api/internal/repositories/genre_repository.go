@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"music-library-api/internal/models"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IGenreRepository interface {
+	GetGenreByID(id primitive.ObjectID) (*models.Genre, error)
+	GetGenreBySlug(slug string) (*models.Genre, error)
+	FindGenreByKey(key string) (*models.Genre, error)
+	GetAllGenres() ([]*models.Genre, error)
+	GetDescendants(id primitive.ObjectID) ([]*models.Genre, error)
+	GetChildren(id primitive.ObjectID) ([]*models.Genre, error)
+	CreateGenre(genre *models.Genre) error
+	UpdateGenre(genre *models.Genre) error
+	DeleteGenre(genre *models.Genre) error
+}
+
+type genreRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewGenreRepository(db *mongo.Database) IGenreRepository {
+	return &genreRepository{
+		Collection: db.Collection("genres"),
+	}
+}
+
+func (r *genreRepository) GetGenreByID(id primitive.ObjectID) (*models.Genre, error) {
+	genre := &models.Genre{}
+	if err := mgm.Coll(genre).FindByID(id, genre); err != nil {
+		return nil, err
+	}
+	return genre, nil
+}
+
+// GetGenreBySlug returns nil, nil when the slug does not exist.
+func (r *genreRepository) GetGenreBySlug(slug string) (*models.Genre, error) {
+	return r.findOne(bson.M{"slug": slug})
+}
+
+// FindGenreByKey matches a normalized key against slug, name, aliases and
+// display names. Returns nil, nil when nothing matches.
+func (r *genreRepository) FindGenreByKey(key string) (*models.Genre, error) {
+	return r.findOne(bson.M{"keys": key})
+}
+
+func (r *genreRepository) GetAllGenres() ([]*models.Genre, error) {
+	return r.find(bson.M{})
+}
+
+// GetDescendants returns every genre below id, at any depth.
+func (r *genreRepository) GetDescendants(id primitive.ObjectID) ([]*models.Genre, error) {
+	return r.find(bson.M{"ancestors": id})
+}
+
+func (r *genreRepository) GetChildren(id primitive.ObjectID) ([]*models.Genre, error) {
+	return r.find(bson.M{"parent_id": id})
+}
+
+func (r *genreRepository) CreateGenre(genre *models.Genre) error {
+	return mgm.Coll(genre).Create(genre)
+}
+
+func (r *genreRepository) UpdateGenre(genre *models.Genre) error {
+	return mgm.Coll(genre).Update(genre)
+}
+
+func (r *genreRepository) DeleteGenre(genre *models.Genre) error {
+	return mgm.Coll(genre).Delete(genre)
+}
+
+func (r *genreRepository) findOne(filter bson.M) (*models.Genre, error) {
+	genre := &models.Genre{}
+	err := mgm.Coll(genre).First(filter, genre)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return genre, nil
+}
+
+func (r *genreRepository) find(filter bson.M) ([]*models.Genre, error) {
+	genres := []*models.Genre{}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := mgm.Coll(&models.Genre{}).Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &genres); err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
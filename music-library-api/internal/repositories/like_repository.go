@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"music-library-api/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ILikeRepository interface {
+	EnsureIndexes() error
+	Like(userID primitive.ObjectID, targetType string, targetID primitive.ObjectID) (bool, error)
+	Unlike(userID primitive.ObjectID, targetType string, targetID primitive.ObjectID) (bool, error)
+	IsLiked(userID primitive.ObjectID, targetType string, targetID primitive.ObjectID) (bool, error)
+	CountLikes(targetType string, targetIDs []primitive.ObjectID) (map[primitive.ObjectID]int64, error)
+	GetLikes(userID primitive.ObjectID, targetType string, page, limit int) ([]models.Like, error)
+	CountUserLikes(userID primitive.ObjectID, targetType string) (int64, error)
+	GetAllLikes(userID primitive.ObjectID, targetType string) ([]models.Like, error)
+	GetLikesByType(targetType string) ([]models.Like, error)
+	DeleteByTarget(targetType string, targetID primitive.ObjectID) error
+}
+
+type likeRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewLikeRepository(db *mongo.Database) ILikeRepository {
+	return &likeRepository{
+		Collection: db.Collection("likes"),
+	}
+}
+
+// EnsureIndexes makes a like unique per user and target, and indexes the
+// lookups of a user's likes and of a target's likes.
+func (r *likeRepository) EnsureIndexes() error {
+	_, err := r.Collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "target_type", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}}},
+	})
+	return err
+}
+
+// Like is idempotent and reports whether a new like was recorded.
+func (r *likeRepository) Like(userID primitive.ObjectID, targetType string, targetID primitive.ObjectID) (bool, error) {
+	now := time.Now().UTC()
+	res, err := r.Collection.UpdateOne(context.Background(),
+		bson.M{"user_id": userID, "target_type": targetType, "target_id": targetID},
+		bson.M{"$setOnInsert": bson.M{
+			"user_id":     userID,
+			"target_type": targetType,
+			"target_id":   targetID,
+			"created_at":  now,
+			"updated_at":  now,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent like of the same target won the upsert
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return res.UpsertedCount > 0, nil
+}
+
+// Unlike reports whether there was a like to remove.
+func (r *likeRepository) Unlike(userID primitive.ObjectID, targetType string, targetID primitive.ObjectID) (bool, error) {
+	res, err := r.Collection.DeleteOne(context.Background(),
+		bson.M{"user_id": userID, "target_type": targetType, "target_id": targetID})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func (r *likeRepository) IsLiked(userID primitive.ObjectID, targetType string, targetID primitive.ObjectID) (bool, error) {
+	n, err := r.Collection.CountDocuments(context.Background(),
+		bson.M{"user_id": userID, "target_type": targetType, "target_id": targetID},
+		options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+// CountLikes counts the likes of several targets in one query. Targets
+// without likes are left out of the map.
+func (r *likeRepository) CountLikes(targetType string, targetIDs []primitive.ObjectID) (map[primitive.ObjectID]int64, error) {
+	counts := make(map[primitive.ObjectID]int64, len(targetIDs))
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"target_type": targetType, "target_id": bson.M{"$in": targetIDs}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$target_id", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.Collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Count int64              `bson:"count"`
+	}
+	if err := cursor.All(context.Background(), &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetLikes returns a page of a user's likes of one target type, most recent first.
+func (r *likeRepository) GetLikes(userID primitive.ObjectID, targetType string, page, limit int) ([]models.Like, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	return r.find(bson.M{"user_id": userID, "target_type": targetType}, opts)
+}
+
+func (r *likeRepository) CountUserLikes(userID primitive.ObjectID, targetType string) (int64, error) {
+	return r.Collection.CountDocuments(context.Background(), bson.M{"user_id": userID, "target_type": targetType})
+}
+
+// GetAllLikes returns every like of a user of one target type, most recent first.
+func (r *likeRepository) GetAllLikes(userID primitive.ObjectID, targetType string) ([]models.Like, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	return r.find(bson.M{"user_id": userID, "target_type": targetType}, opts)
+}
+
+// GetLikesByType returns the likes of every user of one target type, for offline jobs.
+func (r *likeRepository) GetLikesByType(targetType string) ([]models.Like, error) {
+	opts := options.Find().SetProjection(bson.M{"user_id": 1, "target_type": 1, "target_id": 1})
+	return r.find(bson.M{"target_type": targetType}, opts)
+}
+
+func (r *likeRepository) DeleteByTarget(targetType string, targetID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteMany(context.Background(), bson.M{"target_type": targetType, "target_id": targetID})
+	return err
+}
+
+func (r *likeRepository) find(filter bson.M, opts *options.FindOptions) ([]models.Like, error) {
+	cursor, err := r.Collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	likes := []models.Like{}
+	if err := cursor.All(context.Background(), &likes); err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"music-library-api/internal/models"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ILyricsRepository interface {
+	GetLyricsByTrack(trackID primitive.ObjectID) ([]*models.Lyrics, error)
+	GetLyrics(trackID primitive.ObjectID, language string) (*models.Lyrics, error)
+	CreateLyrics(lyrics *models.Lyrics) error
+	UpdateLyrics(lyrics *models.Lyrics) error
+	DeleteLyrics(trackID primitive.ObjectID, language string) (int64, error)
+}
+
+type lyricsRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewLyricsRepository(db *mongo.Database) ILyricsRepository {
+	return &lyricsRepository{
+		Collection: db.Collection("lyrics"),
+	}
+}
+
+// GetLyricsByTrack returns every language of a track, synced lyrics first.
+func (r *lyricsRepository) GetLyricsByTrack(trackID primitive.ObjectID) ([]*models.Lyrics, error) {
+	lyrics := []*models.Lyrics{}
+	opts := options.Find().SetSort(bson.D{{Key: "synced", Value: -1}, {Key: "language", Value: 1}})
+
+	cursor, err := mgm.Coll(&models.Lyrics{}).Find(context.Background(), bson.M{"track_id": trackID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &lyrics); err != nil {
+		return nil, err
+	}
+	return lyrics, nil
+}
+
+// GetLyrics returns nil, nil when the track has no lyrics in that language.
+func (r *lyricsRepository) GetLyrics(trackID primitive.ObjectID, language string) (*models.Lyrics, error) {
+	lyrics := &models.Lyrics{}
+	err := mgm.Coll(lyrics).First(bson.M{"track_id": trackID, "language": language}, lyrics)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return lyrics, nil
+}
+
+func (r *lyricsRepository) CreateLyrics(lyrics *models.Lyrics) error {
+	return mgm.Coll(lyrics).Create(lyrics)
+}
+
+func (r *lyricsRepository) UpdateLyrics(lyrics *models.Lyrics) error {
+	return mgm.Coll(lyrics).Update(lyrics)
+}
+
+// DeleteLyrics removes one language, or every language when language is empty.
+func (r *lyricsRepository) DeleteLyrics(trackID primitive.ObjectID, language string) (int64, error) {
+	filter := bson.M{"track_id": trackID}
+	if language != "" {
+		filter["language"] = language
+	}
+	res, err := mgm.Coll(&models.Lyrics{}).DeleteMany(context.Background(), filter)
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
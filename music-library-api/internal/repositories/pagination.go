@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"music-library-api/pkg/utils"
+	"slices"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// stableSort orders list endpoints by _id, which is unique and follows insertion
+// order, so pages don't shift between requests.
+var stableSort = bson.D{{Key: "_id", Value: 1}}
+
+// ErrInvalidLimit is returned for a page size below one, which Mongo would
+// read as no limit at all.
+var ErrInvalidLimit = errors.New("limit must be at least 1")
+
+// findByCursor returns up to limit documents after (or, with Before, before)
+// the cursor position in _id order. A nil cursor starts from the beginning.
+func findByCursor[T mgm.Model](coll *mongo.Collection, filter bson.M, cursor *utils.Cursor, limit int) ([]T, utils.CursorPage, error) {
+	if limit < 1 {
+		return nil, utils.CursorPage{}, ErrInvalidLimit
+	}
+	items := []T{}
+
+	query := filter
+	order := 1
+	if cursor != nil {
+		op := "$gt"
+		if cursor.Before {
+			op, order = "$lt", -1
+		}
+		query = bson.M{"$and": []bson.M{filter, {"_id": bson.M{op: cursor.ID}}}}
+	}
+
+	// One extra document tells whether there is another page in that direction
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: order}}).SetLimit(int64(limit + 1))
+	cur, err := coll.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, utils.CursorPage{}, err
+	}
+	if err := cur.All(context.Background(), &items); err != nil {
+		return nil, utils.CursorPage{}, err
+	}
+
+	more := len(items) > limit
+	if more {
+		items = items[:limit]
+	}
+	if order == -1 {
+		slices.Reverse(items)
+	}
+
+	hasPrev, hasNext := cursor != nil, more
+	if cursor != nil && cursor.Before {
+		hasPrev, hasNext = more, true
+	}
+	return items, utils.NewCursorPage(items, hasPrev, hasNext), nil
+}
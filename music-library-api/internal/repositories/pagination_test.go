@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"errors"
+	"music-library-api/internal/models"
+	"music-library-api/pkg/utils"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindByCursorInvalidLimit(t *testing.T) {
+	cursor := &utils.Cursor{ID: primitive.NewObjectID()}
+
+	// Rejected before the collection is used, so no database is needed
+	for _, limit := range []int{0, -1, -100} {
+		if _, _, err := findByCursor[*models.User](nil, bson.M{}, cursor, limit); !errors.Is(err, ErrInvalidLimit) {
+			t.Errorf("findByCursor(limit %d) error = %v, want ErrInvalidLimit", limit, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"music-library-api/internal/models"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IPlayQueueRepository interface {
+	GetQueue(userID primitive.ObjectID) (*models.PlayQueue, error)
+	SaveQueue(queue *models.PlayQueue, expected int64) (bool, error)
+	EnsureIndexes() error
+}
+
+type playQueueRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPlayQueueRepository(db *mongo.Database) IPlayQueueRepository {
+	return &playQueueRepository{
+		Collection: db.Collection("play_queues"),
+	}
+}
+
+func (r *playQueueRepository) GetQueue(userID primitive.ObjectID) (*models.PlayQueue, error) {
+	var queue models.PlayQueue
+	err := r.Collection.FindOne(context.Background(), bson.M{"user_id": userID}).Decode(&queue)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &queue, nil
+}
+
+// SaveQueue writes the queue if it is still at version expected, and reports
+// whether it did. A queue at version 0 is inserted; the unique index on
+// user_id makes a concurrent first save fail instead of creating a second
+// queue.
+func (r *playQueueRepository) SaveQueue(queue *models.PlayQueue, expected int64) (bool, error) {
+	queue.Version = expected + 1
+	if expected == 0 {
+		err := mgm.Coll(queue).Create(queue)
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	if err := queue.Saving(); err != nil {
+		return false, err
+	}
+	res, err := r.Collection.UpdateOne(context.Background(),
+		bson.M{"_id": queue.ID, "version": expected},
+		bson.M{"$set": queue},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount == 1, nil
+}
+
+// EnsureIndexes creates the unique index that keeps one queue per user.
+func (r *playQueueRepository) EnsureIndexes() error {
+	_, err := r.Collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"music-library-api/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IPlayRepository interface {
+	EnsureCollections() error
+	RecordPlay(play *models.Play) (bool, error)
+	GetHistory(userID primitive.ObjectID, page, limit int) ([]models.Play, error)
+	CountHistory(userID primitive.ObjectID) (int64, error)
+	IncrementUserTrackPlays(userID, trackID primitive.ObjectID, playedAt time.Time) error
+	GetAllUserTrackPlays() ([]models.UserTrackPlays, error)
+	SetNowPlaying(nowPlaying *models.NowPlaying) error
+	GetNowPlaying(userID primitive.ObjectID) (*models.NowPlaying, error)
+}
+
+// playRepository stores plays in a time-series collection, their per-user
+// rollup in user_track_plays and the track each user is playing in now_playing.
+// Time-series collections cannot have unique indexes, so each play also has a
+// marker in play_keys, which is what makes recording a play idempotent.
+type playRepository struct {
+	db         *mongo.Database
+	Collection *mongo.Collection
+	keys       *mongo.Collection
+	rollups    *mongo.Collection
+	nowPlaying *mongo.Collection
+}
+
+func NewPlayRepository(db *mongo.Database) IPlayRepository {
+	return &playRepository{
+		db:         db,
+		Collection: db.Collection("plays"),
+		keys:       db.Collection("play_keys"),
+		rollups:    db.Collection("user_track_plays"),
+		nowPlaying: db.Collection("now_playing"),
+	}
+}
+
+// EnsureCollections creates the plays time-series collection and the indexes
+// of the other collections. Existing ones are left as they are.
+func (r *playRepository) EnsureCollections() error {
+	ctx := context.Background()
+
+	names, err := r.db.ListCollectionNames(ctx, bson.M{"name": "plays"})
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		opts := options.CreateCollection().SetTimeSeriesOptions(
+			options.TimeSeries().SetTimeField("played_at").SetMetaField("meta").SetGranularity("minutes"),
+		)
+		if err := r.db.CreateCollection(ctx, "plays", opts); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.Collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "meta.user_id", Value: 1}, {Key: "played_at", Value: -1}},
+	}); err != nil {
+		return err
+	}
+	if _, err := r.keys.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "track_id", Value: 1}, {Key: "played_at", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+	if _, err := r.rollups.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "track_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+	_, err = r.nowPlaying.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// RecordPlay inserts a play unless the same play (user, track and start
+// time) was already recorded, so that clients can safely retry. The play's
+// marker is upserted first: only the request that creates it inserts the
+// play, even when retries arrive at the same time. It reports whether the
+// play was inserted.
+func (r *playRepository) RecordPlay(play *models.Play) (bool, error) {
+	ctx := context.Background()
+	key := bson.M{
+		"user_id":   play.Meta.UserID,
+		"track_id":  play.Meta.TrackID,
+		"played_at": play.PlayedAt,
+	}
+	res, err := r.keys.UpdateOne(ctx, key,
+		bson.M{"$setOnInsert": bson.M{"created_at": time.Now().UTC()}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent upsert of the same key won the race
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if res.UpsertedCount == 0 {
+		return false, nil
+	}
+
+	play.ID = primitive.NewObjectID()
+	if _, err := r.Collection.InsertOne(ctx, play); err != nil {
+		// Release the key so that a retry records the play
+		_, delErr := r.keys.DeleteOne(ctx, key)
+		return false, errors.Join(err, delErr)
+	}
+	return true, nil
+}
+
+// GetHistory returns the plays of a user, most recent first.
+func (r *playRepository) GetHistory(userID primitive.ObjectID, page, limit int) ([]models.Play, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "played_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.Collection.Find(context.Background(), bson.M{"meta.user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	plays := []models.Play{}
+	if err := cursor.All(context.Background(), &plays); err != nil {
+		return nil, err
+	}
+	return plays, nil
+}
+
+func (r *playRepository) CountHistory(userID primitive.ObjectID) (int64, error) {
+	return r.Collection.CountDocuments(context.Background(), bson.M{"meta.user_id": userID})
+}
+
+func (r *playRepository) IncrementUserTrackPlays(userID, trackID primitive.ObjectID, playedAt time.Time) error {
+	_, err := r.rollups.UpdateOne(context.Background(),
+		bson.M{"user_id": userID, "track_id": trackID},
+		bson.M{
+			"$inc": bson.M{"count": 1},
+			"$max": bson.M{"last_played_at": playedAt},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// GetAllUserTrackPlays returns the whole play count rollup, for offline jobs.
+func (r *playRepository) GetAllUserTrackPlays() ([]models.UserTrackPlays, error) {
+	opts := options.Find().SetProjection(bson.M{"user_id": 1, "track_id": 1, "count": 1})
+	cursor, err := r.rollups.Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	rollups := []models.UserTrackPlays{}
+	if err := cursor.All(context.Background(), &rollups); err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+func (r *playRepository) SetNowPlaying(nowPlaying *models.NowPlaying) error {
+	_, err := r.nowPlaying.ReplaceOne(context.Background(),
+		bson.M{"user_id": nowPlaying.UserID},
+		nowPlaying,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// GetNowPlaying returns the track the user is playing, or nil. The TTL index
+// removes entries lazily, so expired ones are filtered out here.
+func (r *playRepository) GetNowPlaying(userID primitive.ObjectID) (*models.NowPlaying, error) {
+	var nowPlaying models.NowPlaying
+	err := r.nowPlaying.FindOne(context.Background(),
+		bson.M{"user_id": userID, "expires_at": bson.M{"$gt": time.Now().UTC()}},
+	).Decode(&nowPlaying)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &nowPlaying, nil
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"music-library-api/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IPlaylistCoverRepository interface {
+	GetCover(playlistID primitive.ObjectID) (*models.PlaylistCover, error)
+	GetCoverURLs(playlistIDs []primitive.ObjectID) (map[primitive.ObjectID]string, error)
+	SaveCover(cover *models.PlaylistCover) error
+	DeleteByPlaylist(playlistID primitive.ObjectID) error
+}
+
+type playlistCoverRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPlaylistCoverRepository(db *mongo.Database) IPlaylistCoverRepository {
+	return &playlistCoverRepository{
+		Collection: db.Collection("playlist_covers"),
+	}
+}
+
+func (r *playlistCoverRepository) GetCover(playlistID primitive.ObjectID) (*models.PlaylistCover, error) {
+	var cover models.PlaylistCover
+	err := r.Collection.FindOne(context.Background(), bson.M{"playlist_id": playlistID}).Decode(&cover)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cover, nil
+}
+
+// GetCoverURLs returns the generated covers of several playlists in one
+// query. Playlists without a cover are left out of the map.
+func (r *playlistCoverRepository) GetCoverURLs(playlistIDs []primitive.ObjectID) (map[primitive.ObjectID]string, error) {
+	urls := make(map[primitive.ObjectID]string, len(playlistIDs))
+	if len(playlistIDs) == 0 {
+		return urls, nil
+	}
+
+	cursor, err := r.Collection.Find(context.Background(),
+		bson.M{"playlist_id": bson.M{"$in": playlistIDs}, "url": bson.M{"$ne": ""}},
+		options.Find().SetProjection(bson.M{"playlist_id": 1, "url": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var covers []models.PlaylistCover
+	if err := cursor.All(context.Background(), &covers); err != nil {
+		return nil, err
+	}
+	for _, c := range covers {
+		urls[c.PlaylistID] = c.URL
+	}
+	return urls, nil
+}
+
+// SaveCover replaces the cover of cover.PlaylistID, creating it if needed.
+func (r *playlistCoverRepository) SaveCover(cover *models.PlaylistCover) error {
+	now := time.Now().UTC()
+	_, err := r.Collection.UpdateOne(context.Background(),
+		bson.M{"playlist_id": cover.PlaylistID},
+		bson.M{
+			"$set": bson.M{
+				"url":        cover.URL,
+				"album_ids":  cover.AlbumIDs,
+				"updated_at": now,
+			},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *playlistCoverRepository) DeleteByPlaylist(playlistID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteOne(context.Background(), bson.M{"playlist_id": playlistID})
+	return err
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"music-library-api/internal/models"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IPlaylistFolderRepository interface {
+	GetFoldersByUser(userID primitive.ObjectID) ([]*models.PlaylistFolder, error)
+	CreateFolder(folder *models.PlaylistFolder) error
+	UpdateFolder(folder *models.PlaylistFolder) error
+	DeleteFolder(folder *models.PlaylistFolder) error
+	SetPositions(folderIDs []primitive.ObjectID) error
+	RemovePlaylist(userID, playlistID primitive.ObjectID) error
+}
+
+type playlistFolderRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPlaylistFolderRepository(db *mongo.Database) IPlaylistFolderRepository {
+	return &playlistFolderRepository{
+		Collection: db.Collection("playlist_folders"),
+	}
+}
+
+// GetFoldersByUser returns all folders of a user, ordered by position.
+func (r *playlistFolderRepository) GetFoldersByUser(userID primitive.ObjectID) ([]*models.PlaylistFolder, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.Collection.Find(context.Background(), bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	folders := []*models.PlaylistFolder{}
+	if err := cursor.All(context.Background(), &folders); err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+func (r *playlistFolderRepository) CreateFolder(folder *models.PlaylistFolder) error {
+	return mgm.Coll(folder).Create(folder)
+}
+
+func (r *playlistFolderRepository) UpdateFolder(folder *models.PlaylistFolder) error {
+	return mgm.Coll(folder).Update(folder)
+}
+
+func (r *playlistFolderRepository) DeleteFolder(folder *models.PlaylistFolder) error {
+	return mgm.Coll(folder).Delete(folder)
+}
+
+// SetPositions numbers sibling folders in the given order.
+func (r *playlistFolderRepository) SetPositions(folderIDs []primitive.ObjectID) error {
+	if len(folderIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	writes := make([]mongo.WriteModel, len(folderIDs))
+	for i, id := range folderIDs {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{"position": i, "updated_at": now}})
+	}
+	_, err := r.Collection.BulkWrite(context.Background(), writes)
+	return err
+}
+
+// RemovePlaylist takes a playlist out of the folders of one user.
+func (r *playlistFolderRepository) RemovePlaylist(userID, playlistID primitive.ObjectID) error {
+	_, err := r.Collection.UpdateMany(context.Background(),
+		bson.M{"user_id": userID, "playlist_ids": playlistID},
+		bson.M{"$pull": bson.M{"playlist_ids": playlistID}, "$set": bson.M{"updated_at": time.Now().UTC()}},
+	)
+	return err
+}
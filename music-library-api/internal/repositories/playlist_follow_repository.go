@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"music-library-api/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IPlaylistFollowRepository interface {
+	Follow(playlistID, userID primitive.ObjectID) error
+	Unfollow(playlistID, userID primitive.ObjectID) error
+	CountFollowers(playlistIDs []primitive.ObjectID) (map[primitive.ObjectID]int64, error)
+	GetFollowedPlaylistIDs(userID primitive.ObjectID, page, limit int) ([]primitive.ObjectID, error)
+	CountFollowed(userID primitive.ObjectID) (int64, error)
+	DeleteByPlaylist(playlistID primitive.ObjectID) error
+}
+
+type playlistFollowRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPlaylistFollowRepository(db *mongo.Database) IPlaylistFollowRepository {
+	return &playlistFollowRepository{
+		Collection: db.Collection("playlist_follows"),
+	}
+}
+
+// Follow is idempotent: following a playlist twice keeps the first follow.
+func (r *playlistFollowRepository) Follow(playlistID, userID primitive.ObjectID) error {
+	now := time.Now().UTC()
+	_, err := r.Collection.UpdateOne(context.Background(),
+		bson.M{"playlist_id": playlistID, "user_id": userID},
+		bson.M{"$setOnInsert": bson.M{
+			"playlist_id": playlistID,
+			"user_id":     userID,
+			"created_at":  now,
+			"updated_at":  now,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *playlistFollowRepository) Unfollow(playlistID, userID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteOne(context.Background(), bson.M{"playlist_id": playlistID, "user_id": userID})
+	return err
+}
+
+// CountFollowers counts the followers of several playlists in one query.
+// Playlists without followers are left out of the map.
+func (r *playlistFollowRepository) CountFollowers(playlistIDs []primitive.ObjectID) (map[primitive.ObjectID]int64, error) {
+	counts := make(map[primitive.ObjectID]int64, len(playlistIDs))
+	if len(playlistIDs) == 0 {
+		return counts, nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"playlist_id": bson.M{"$in": playlistIDs}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$playlist_id", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.Collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Count int64              `bson:"count"`
+	}
+	if err := cursor.All(context.Background(), &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetFollowedPlaylistIDs returns the playlists a user follows, most recently followed first.
+func (r *playlistFollowRepository) GetFollowedPlaylistIDs(userID primitive.ObjectID, page, limit int) ([]primitive.ObjectID, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.Collection.Find(context.Background(), bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var follows []models.PlaylistFollow
+	if err := cursor.All(context.Background(), &follows); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(follows))
+	for i, f := range follows {
+		ids[i] = f.PlaylistID
+	}
+	return ids, nil
+}
+
+func (r *playlistFollowRepository) CountFollowed(userID primitive.ObjectID) (int64, error) {
+	return r.Collection.CountDocuments(context.Background(), bson.M{"user_id": userID})
+}
+
+func (r *playlistFollowRepository) DeleteByPlaylist(playlistID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteMany(context.Background(), bson.M{"playlist_id": playlistID})
+	return err
+}
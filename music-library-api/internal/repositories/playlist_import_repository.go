@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"music-library-api/internal/models"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IPlaylistImportRepository interface {
+	GetImportByID(id string) (*models.PlaylistImport, error)
+	CreateImport(report *models.PlaylistImport) error
+	UpdateImport(report *models.PlaylistImport) error
+}
+
+type playlistImportRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPlaylistImportRepository(db *mongo.Database) IPlaylistImportRepository {
+	return &playlistImportRepository{
+		Collection: db.Collection("playlist_imports"),
+	}
+}
+
+func (r *playlistImportRepository) GetImportByID(id string) (*models.PlaylistImport, error) {
+	report := &models.PlaylistImport{}
+	if err := mgm.Coll(report).FindByID(id, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *playlistImportRepository) CreateImport(report *models.PlaylistImport) error {
+	return mgm.Coll(report).Create(report)
+}
+
+func (r *playlistImportRepository) UpdateImport(report *models.PlaylistImport) error {
+	return mgm.Coll(report).Update(report)
+}
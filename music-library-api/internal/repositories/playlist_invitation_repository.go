@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"music-library-api/internal/models"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IPlaylistInvitationRepository interface {
+	GetInvitationByID(id string) (*models.PlaylistInvitation, error)
+	GetPendingInvitation(playlistID, inviteeID primitive.ObjectID) (*models.PlaylistInvitation, error)
+	GetPendingByPlaylist(playlistID primitive.ObjectID) ([]*models.PlaylistInvitation, error)
+	GetPendingByInvitee(inviteeID primitive.ObjectID) ([]*models.PlaylistInvitation, error)
+	CreateInvitation(invitation *models.PlaylistInvitation) error
+	UpdateInvitation(invitation *models.PlaylistInvitation) error
+}
+
+type playlistInvitationRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPlaylistInvitationRepository(db *mongo.Database) IPlaylistInvitationRepository {
+	return &playlistInvitationRepository{
+		Collection: db.Collection("playlist_invitations"),
+	}
+}
+
+func (r *playlistInvitationRepository) GetInvitationByID(id string) (*models.PlaylistInvitation, error) {
+	invitation := &models.PlaylistInvitation{}
+	if err := mgm.Coll(invitation).FindByID(id, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// GetPendingInvitation returns the open invitation of a user to a playlist, or nil, nil.
+func (r *playlistInvitationRepository) GetPendingInvitation(playlistID, inviteeID primitive.ObjectID) (*models.PlaylistInvitation, error) {
+	invitation := &models.PlaylistInvitation{}
+	err := mgm.Coll(invitation).First(bson.M{
+		"playlist_id": playlistID,
+		"invitee_id":  inviteeID,
+		"status":      models.InvitationPending,
+	}, invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (r *playlistInvitationRepository) GetPendingByPlaylist(playlistID primitive.ObjectID) ([]*models.PlaylistInvitation, error) {
+	return r.findPending(bson.M{"playlist_id": playlistID})
+}
+
+func (r *playlistInvitationRepository) GetPendingByInvitee(inviteeID primitive.ObjectID) ([]*models.PlaylistInvitation, error) {
+	return r.findPending(bson.M{"invitee_id": inviteeID})
+}
+
+func (r *playlistInvitationRepository) findPending(filter bson.M) ([]*models.PlaylistInvitation, error) {
+	filter["status"] = models.InvitationPending
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.Collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	invitations := []*models.PlaylistInvitation{}
+	if err := cursor.All(context.Background(), &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *playlistInvitationRepository) CreateInvitation(invitation *models.PlaylistInvitation) error {
+	return mgm.Coll(invitation).Create(invitation)
+}
+
+func (r *playlistInvitationRepository) UpdateInvitation(invitation *models.PlaylistInvitation) error {
+	return mgm.Coll(invitation).Update(invitation)
+}
@@ -3,20 +3,35 @@ package repositories
 import (
 	"context"
 	"music-library-api/internal/models"
+	"music-library-api/pkg/utils"
+	"time"
 
 	"github.com/kamva/mgm/v3"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type IPlaylistRepository interface {
 	GetPlaylistByID(id string) (*models.Playlist, error)
-	GetPlaylists(page, limit int, userID string) ([]*models.Playlist, error)
-	CountPlaylists(userID string) (int64, error)
+	GetPlaylistsByIDs(ids []primitive.ObjectID) ([]*models.Playlist, error)
+	GetExpandedPlaylist(id string, entries []models.PlaylistEntry) (*models.ExpandedPlaylist, error)
+	GetPlaylists(page, limit int, filter models.PlaylistFilter) ([]*models.Playlist, error)
+	GetPlaylistsByCursor(cursor *utils.Cursor, limit int, filter models.PlaylistFilter) ([]*models.Playlist, utils.CursorPage, error)
+	CountPlaylists(filter models.PlaylistFilter) (int64, error)
 	CreatePlaylist(playlist *models.Playlist) (*models.Playlist, error)
-	UpdatePlaylist(playlist *models.Playlist) (*models.Playlist, error)
+	UpdatePlaylistVersion(playlist *models.Playlist, expected int64) (bool, error)
+	BackfillPlaylistEntries() (int64, error)
+	BackfillPlaylistVisibility() (int64, error)
+	GetPlaylistIDsWithoutCover() ([]primitive.ObjectID, error)
+	GetPlaylistByKind(userID primitive.ObjectID, kind string) (*models.Playlist, error)
+	EnsureIndexes() error
 	DeletePlaylist(id string) error
+	UpsertCollaborator(playlistID primitive.ObjectID, collaborator models.PlaylistCollaborator) error
+	RemoveCollaborator(playlistID, userID primitive.ObjectID) (bool, error)
+	AddShareLink(playlistID primitive.ObjectID, link models.PlaylistShareLink) error
+	RemoveShareLink(playlistID, linkID primitive.ObjectID) (bool, error)
 }
 
 type playlistRepository struct{}
@@ -33,20 +48,125 @@ func (r *playlistRepository) GetPlaylistByID(id string) (*models.Playlist, error
 	return playlist, nil
 }
 
-func (r *playlistRepository) GetPlaylists(page, limit int, userID string) ([]*models.Playlist, error) {
-	playlists := []*models.Playlist{}
-	skip := int64((page - 1) * limit)
-	opts := options.Find().SetSkip(skip).SetLimit(int64(limit))
+// GetPlaylistsByIDs returns playlists in the order of ids, skipping missing ones.
+func (r *playlistRepository) GetPlaylistsByIDs(ids []primitive.ObjectID) ([]*models.Playlist, error) {
+	if len(ids) == 0 {
+		return []*models.Playlist{}, nil
+	}
 
-	filter := bson.M{}
-	if userID != "" {
-		objID, err := primitive.ObjectIDFromHex(userID)
-		if err == nil {
-			filter["user_id"] = objID
+	var found []*models.Playlist
+	if err := mgm.Coll(&models.Playlist{}).SimpleFind(&found, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*models.Playlist, len(found))
+	for _, pl := range found {
+		byID[pl.ID] = pl
+	}
+	playlists := make([]*models.Playlist, 0, len(ids))
+	for _, id := range ids {
+		if pl, ok := byID[id]; ok {
+			playlists = append(playlists, pl)
+		}
+	}
+	return playlists, nil
+}
+
+// GetExpandedPlaylist loads a playlist with its tracks, albums and artist
+// profiles in a single aggregation. Non-nil entries replace the stored ones,
+// which is how smart playlists are expanded. It returns mongo.ErrNoDocuments
+// like GetPlaylistByID when the playlist does not exist.
+func (r *playlistRepository) GetExpandedPlaylist(id string, entries []models.PlaylistEntry) (*models.ExpandedPlaylist, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": objID}}},
+	}
+	if entries != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"entries": bson.M{"$literal": entries}}}})
+	}
+	// localField/foreignField lets the join use the _id index instead of
+	// scanning tracks with $expr
+	pipeline = append(pipeline, bson.D{{Key: "$lookup", Value: bson.M{
+		"from":         mgm.CollName(&models.Track{}),
+		"localField":   "entries.track_id",
+		"foreignField": "_id",
+		"pipeline": bson.A{
+			bson.M{"$project": bson.M{"lyrics_text": 0, "lyrics_folded": 0}},
+			bson.M{"$lookup": bson.M{
+				"from":         mgm.CollName(&models.Album{}),
+				"localField":   "album_id",
+				"foreignField": "_id",
+				"pipeline":     bson.A{bson.M{"$project": bson.M{"tracks": 0, "discs": 0}}},
+				"as":           "album_info",
+			}},
+			bson.M{"$lookup": bson.M{
+				"from":         mgm.CollName(&models.ArtistProfile{}),
+				"localField":   "user_id",
+				"foreignField": "user_id",
+				"pipeline":     bson.A{bson.M{"$project": bson.M{"bio": 0, "links": 0}}},
+				"as":           "artist_profile",
+			}},
+			bson.M{"$addFields": bson.M{
+				"album_info":     bson.M{"$arrayElemAt": bson.A{"$album_info", 0}},
+				"artist_profile": bson.M{"$arrayElemAt": bson.A{"$artist_profile", 0}},
+			}},
+		},
+		"as": "tracks",
+	}}})
+
+	cursor, err := mgm.Coll(&models.Playlist{}).Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	if !cursor.Next(context.Background()) {
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+		return nil, mongo.ErrNoDocuments
+	}
+	var doc struct {
+		models.Playlist `bson:",inline"`
+		Tracks          []*models.PlaylistItemTrack `bson:"tracks"`
+	}
+	if err := cursor.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return expandPlaylist(doc.Playlist, doc.Tracks), nil
+}
+
+// expandPlaylist puts the looked-up tracks back in entry order. $lookup
+// returns each track once and in no particular order; a deleted track leaves
+// an item without a track.
+func expandPlaylist(playlist models.Playlist, tracks []*models.PlaylistItemTrack) *models.ExpandedPlaylist {
+	byID := make(map[primitive.ObjectID]*models.PlaylistItemTrack, len(tracks))
+	for _, t := range tracks {
+		byID[t.ID] = t
+	}
+
+	expanded := &models.ExpandedPlaylist{Playlist: playlist, Items: make([]models.PlaylistItem, len(playlist.Entries))}
+	for i, e := range playlist.Entries {
+		track := byID[e.TrackID]
+		expanded.Items[i] = models.PlaylistItem{Entry: e, Track: track}
+		if track != nil {
+			expanded.TotalDuration += track.Duration
 		}
 	}
+	expanded.TrackCount = len(expanded.Items)
+	return expanded
+}
 
-	cursor, err := mgm.Coll(&models.Playlist{}).Find(context.Background(), filter, opts)
+func (r *playlistRepository) GetPlaylists(page, limit int, filter models.PlaylistFilter) ([]*models.Playlist, error) {
+	playlists := []*models.Playlist{}
+	skip := int64((page - 1) * limit)
+	opts := options.Find().SetSort(stableSort).SetSkip(skip).SetLimit(int64(limit))
+
+	cursor, err := mgm.Coll(&models.Playlist{}).Find(context.Background(), playlistFilter(filter), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -56,25 +176,37 @@ func (r *playlistRepository) GetPlaylists(page, limit int, userID string) ([]*mo
 	return playlists, nil
 }
 
-func (r *playlistRepository) CountPlaylists(userID string) (int64, error) {
+// GetPlaylistsByCursor is the keyset-paginated variant of GetPlaylists.
+func (r *playlistRepository) GetPlaylistsByCursor(cursor *utils.Cursor, limit int, filter models.PlaylistFilter) ([]*models.Playlist, utils.CursorPage, error) {
+	return findByCursor[*models.Playlist](mgm.Coll(&models.Playlist{}).Collection, playlistFilter(filter), cursor, limit)
+}
+
+func (r *playlistRepository) CountPlaylists(filter models.PlaylistFilter) (int64, error) {
+	return mgm.Coll(&models.Playlist{}).CountDocuments(context.Background(), playlistFilter(filter))
+}
+
+// playlistFilter builds the query of a playlist listing.
+func playlistFilter(f models.PlaylistFilter) bson.M {
 	filter := bson.M{}
-	if userID != "" {
-		objID, err := primitive.ObjectIDFromHex(userID)
+	if f.MemberID != "" {
+		objID, err := primitive.ObjectIDFromHex(f.MemberID)
 		if err == nil {
-			filter["user_id"] = objID
+			filter["$or"] = bson.A{
+				bson.M{"user_id": objID},
+				bson.M{"collaborators.user_id": objID},
+			}
 		}
 	}
-	return mgm.Coll(&models.Playlist{}).CountDocuments(context.Background(), filter)
+	if f.PublicOnly {
+		filter["visibility"] = models.PlaylistVisibilityPublic
+	}
+	return filter
 }
 
 func (r *playlistRepository) CreatePlaylist(playlist *models.Playlist) (*models.Playlist, error) {
 	return playlist, mgm.Coll(playlist).Create(playlist)
 }
 
-func (r *playlistRepository) UpdatePlaylist(playlist *models.Playlist) (*models.Playlist, error) {
-	return playlist, mgm.Coll(playlist).Update(playlist)
-}
-
 func (r *playlistRepository) DeletePlaylist(id string) error {
 	playlist := &models.Playlist{}
 	if err := mgm.Coll(playlist).FindByID(id, playlist); err != nil {
@@ -82,3 +214,210 @@ func (r *playlistRepository) DeletePlaylist(id string) error {
 	}
 	return mgm.Coll(playlist).Delete(playlist)
 }
+
+// UpdatePlaylistVersion saves the playlist only if it is still at the expected
+// version, bumping the version. It returns false when someone else saved first.
+func (r *playlistRepository) UpdatePlaylistVersion(playlist *models.Playlist, expected int64) (bool, error) {
+	filter := bson.M{"_id": playlist.ID, "version": expected}
+	if expected == 0 {
+		// Playlists created before versioning have no version field
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	playlist.Version = expected + 1
+	if err := playlist.Saving(); err != nil {
+		return false, err
+	}
+
+	res, err := mgm.Coll(playlist).UpdateOne(context.Background(), filter, bson.M{"$set": playlist})
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount == 1, nil
+}
+
+// BackfillPlaylistEntries converts playlists saved before entries existed.
+func (r *playlistRepository) BackfillPlaylistEntries() (int64, error) {
+	ctx := context.Background()
+	coll := mgm.Coll(&models.Playlist{})
+
+	cursor, err := coll.Find(ctx, bson.M{"entries": bson.M{"$exists": false}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var updated int64
+	for cursor.Next(ctx) {
+		playlist := &models.Playlist{}
+		if err := cursor.Decode(playlist); err != nil {
+			return updated, err
+		}
+
+		playlist.SyncTrackIDs()
+		_, err := coll.UpdateOne(ctx, bson.M{"_id": playlist.ID}, bson.M{"$set": bson.M{
+			"entries": playlist.Entries,
+			"version": playlist.Version,
+		}})
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, cursor.Err()
+}
+
+// BackfillPlaylistVisibility makes playlists saved before visibility existed public,
+// which is how they were listed until then.
+func (r *playlistRepository) BackfillPlaylistVisibility() (int64, error) {
+	res, err := mgm.Coll(&models.Playlist{}).UpdateMany(context.Background(),
+		bson.M{"visibility": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"visibility": models.PlaylistVisibilityPublic}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// GetPlaylistIDsWithoutCover returns the playlists that have neither an
+// uploaded cover nor a generated one in playlist_covers.
+func (r *playlistRepository) GetPlaylistIDsWithoutCover() ([]primitive.ObjectID, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"album_cover": bson.M{"$in": bson.A{"", nil}}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "playlist_covers",
+			"localField":   "_id",
+			"foreignField": "playlist_id",
+			"as":           "covers",
+		}}},
+		{{Key: "$match", Value: bson.M{"covers": bson.M{"$size": 0}}}},
+		{{Key: "$project", Value: bson.M{"_id": 1}}},
+	}
+	cursor, err := mgm.Coll(&models.Playlist{}).Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(context.Background(), &rows); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids, nil
+}
+
+// GetPlaylistByKind returns the playlist of a kind a user owns, or nil.
+func (r *playlistRepository) GetPlaylistByKind(userID primitive.ObjectID, kind string) (*models.Playlist, error) {
+	playlist := &models.Playlist{}
+	err := mgm.Coll(playlist).First(bson.M{"user_id": userID, "kind": kind}, playlist)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// EnsureIndexes allows a user at most one playlist of each kind, so two
+// concurrent requests cannot both create a user's Liked Songs.
+func (r *playlistRepository) EnsureIndexes() error {
+	_, err := mgm.Coll(&models.Playlist{}).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "kind", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"kind": bson.M{"$exists": true}}),
+	})
+	return err
+}
+
+// UpsertCollaborator adds a collaborator, or changes their role if they
+// already are one. Like every change it bumps the version, so a concurrent
+// edit based on the old document cannot overwrite the collaborator list.
+func (r *playlistRepository) UpsertCollaborator(playlistID primitive.ObjectID, collaborator models.PlaylistCollaborator) error {
+	coll := mgm.Coll(&models.Playlist{})
+	ctx := context.Background()
+
+	res, err := coll.UpdateOne(ctx,
+		bson.M{"_id": playlistID, "collaborators.user_id": collaborator.UserID},
+		bson.M{
+			"$set": bson.M{"collaborators.$.role": collaborator.Role, "updated_at": time.Now().UTC()},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	if err != nil || res.MatchedCount == 1 {
+		return err
+	}
+
+	res, err = coll.UpdateOne(ctx,
+		bson.M{"_id": playlistID},
+		bson.M{
+			"$push": bson.M{"collaborators": collaborator},
+			"$set":  bson.M{"updated_at": time.Now().UTC()},
+			"$inc":  bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RemoveCollaborator removes a collaborator; it returns false if the user was not one.
+func (r *playlistRepository) RemoveCollaborator(playlistID, userID primitive.ObjectID) (bool, error) {
+	res, err := mgm.Coll(&models.Playlist{}).UpdateOne(context.Background(),
+		bson.M{"_id": playlistID, "collaborators.user_id": userID},
+		bson.M{
+			"$pull": bson.M{"collaborators": bson.M{"user_id": userID}},
+			"$set":  bson.M{"updated_at": time.Now().UTC()},
+			"$inc":  bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// AddShareLink stores a new share link, bumping the version like UpsertCollaborator.
+func (r *playlistRepository) AddShareLink(playlistID primitive.ObjectID, link models.PlaylistShareLink) error {
+	res, err := mgm.Coll(&models.Playlist{}).UpdateOne(context.Background(),
+		bson.M{"_id": playlistID},
+		bson.M{
+			"$push": bson.M{"share_links": link},
+			"$set":  bson.M{"updated_at": time.Now().UTC()},
+			"$inc":  bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RemoveShareLink revokes a share link; it returns false if there was no such link.
+func (r *playlistRepository) RemoveShareLink(playlistID, linkID primitive.ObjectID) (bool, error) {
+	res, err := mgm.Coll(&models.Playlist{}).UpdateOne(context.Background(),
+		bson.M{"_id": playlistID, "share_links._id": linkID},
+		bson.M{
+			"$pull": bson.M{"share_links": bson.M{"_id": linkID}},
+			"$set":  bson.M{"updated_at": time.Now().UTC()},
+			"$inc":  bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"music-library-api/internal/models"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExpandPlaylist(t *testing.T) {
+	a, b, deleted := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	track := func(id primitive.ObjectID, duration int) *models.PlaylistItemTrack {
+		tr := &models.PlaylistItemTrack{}
+		tr.ID = id
+		tr.Duration = duration
+		return tr
+	}
+
+	tests := []struct {
+		name     string
+		entries  []models.PlaylistEntry
+		tracks   []*models.PlaylistItemTrack
+		want     []primitive.ObjectID // track of each item, zero for a deleted track
+		duration int
+	}{
+		{
+			name:    "no entries",
+			entries: nil,
+			want:    []primitive.ObjectID{},
+		},
+		{
+			name:     "lookup order is replaced by entry order",
+			entries:  []models.PlaylistEntry{{TrackID: b}, {TrackID: a}},
+			tracks:   []*models.PlaylistItemTrack{track(a, 100), track(b, 200)},
+			want:     []primitive.ObjectID{b, a},
+			duration: 300,
+		},
+		{
+			name:     "duplicate entries share one looked-up track",
+			entries:  []models.PlaylistEntry{{TrackID: a}, {TrackID: b}, {TrackID: a}},
+			tracks:   []*models.PlaylistItemTrack{track(b, 200), track(a, 100)},
+			want:     []primitive.ObjectID{a, b, a},
+			duration: 400,
+		},
+		{
+			name:     "deleted track keeps its entry",
+			entries:  []models.PlaylistEntry{{TrackID: deleted}, {TrackID: a}},
+			tracks:   []*models.PlaylistItemTrack{track(a, 100)},
+			want:     []primitive.ObjectID{primitive.NilObjectID, a},
+			duration: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := expandPlaylist(models.Playlist{Entries: tt.entries}, tt.tracks)
+			if got.TrackCount != len(tt.want) || len(got.Items) != len(tt.want) {
+				t.Fatalf("TrackCount = %d, len(Items) = %d, want %d", got.TrackCount, len(got.Items), len(tt.want))
+			}
+			for i, item := range got.Items {
+				if item.Entry.TrackID != tt.entries[i].TrackID {
+					t.Errorf("item %d entry = %s, want %s", i, item.Entry.TrackID.Hex(), tt.entries[i].TrackID.Hex())
+				}
+				switch {
+				case tt.want[i].IsZero() && item.Track != nil:
+					t.Errorf("item %d track = %s, want none", i, item.Track.ID.Hex())
+				case !tt.want[i].IsZero() && (item.Track == nil || item.Track.ID != tt.want[i]):
+					t.Errorf("item %d track = %v, want %s", i, item.Track, tt.want[i].Hex())
+				}
+			}
+			if got.TotalDuration != tt.duration {
+				t.Errorf("TotalDuration = %d, want %d", got.TotalDuration, tt.duration)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"music-library-api/internal/models"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IPlaylistVersionRepository interface {
+	GetVersion(playlistID primitive.ObjectID, version int64) (*models.PlaylistVersion, error)
+	GetLatestVersion(playlistID primitive.ObjectID) (*models.PlaylistVersion, error)
+	GetVersions(playlistID primitive.ObjectID, page, limit int) ([]*models.PlaylistVersion, error)
+	CountVersions(playlistID primitive.ObjectID) (int64, error)
+	CreateVersion(version *models.PlaylistVersion) error
+	DeleteByPlaylist(playlistID primitive.ObjectID) error
+}
+
+type playlistVersionRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewPlaylistVersionRepository(db *mongo.Database) IPlaylistVersionRepository {
+	return &playlistVersionRepository{
+		Collection: db.Collection("playlist_versions"),
+	}
+}
+
+// GetVersion returns the snapshot of a playlist version, or nil, nil.
+func (r *playlistVersionRepository) GetVersion(playlistID primitive.ObjectID, version int64) (*models.PlaylistVersion, error) {
+	return r.findOne(bson.M{"playlist_id": playlistID, "version": version}, nil)
+}
+
+// GetLatestVersion returns the newest snapshot of a playlist, or nil, nil.
+func (r *playlistVersionRepository) GetLatestVersion(playlistID primitive.ObjectID) (*models.PlaylistVersion, error) {
+	return r.findOne(bson.M{"playlist_id": playlistID}, bson.D{{Key: "version", Value: -1}})
+}
+
+func (r *playlistVersionRepository) findOne(filter bson.M, sort bson.D) (*models.PlaylistVersion, error) {
+	opts := options.FindOne()
+	if sort != nil {
+		opts.SetSort(sort)
+	}
+
+	version := &models.PlaylistVersion{}
+	if err := r.Collection.FindOne(context.Background(), filter, opts).Decode(version); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return version, nil
+}
+
+// GetVersions lists snapshots newest first.
+func (r *playlistVersionRepository) GetVersions(playlistID primitive.ObjectID, page, limit int) ([]*models.PlaylistVersion, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.Collection.Find(context.Background(), bson.M{"playlist_id": playlistID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	versions := []*models.PlaylistVersion{}
+	if err := cursor.All(context.Background(), &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *playlistVersionRepository) CountVersions(playlistID primitive.ObjectID) (int64, error) {
+	return r.Collection.CountDocuments(context.Background(), bson.M{"playlist_id": playlistID})
+}
+
+func (r *playlistVersionRepository) CreateVersion(version *models.PlaylistVersion) error {
+	return mgm.Coll(version).Create(version)
+}
+
+func (r *playlistVersionRepository) DeleteByPlaylist(playlistID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteMany(context.Background(), bson.M{"playlist_id": playlistID})
+	return err
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"music-library-api/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IRecommendationRepository interface {
+	EnsureIndexes() error
+	GetRecommendation(kind string, subjectID primitive.ObjectID) (*models.Recommendation, error)
+	SaveRecommendations(recs []models.Recommendation) error
+	DeleteGeneratedBefore(t time.Time) (int64, error)
+}
+
+const recommendationBatchSize = 500
+
+type recommendationRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewRecommendationRepository(db *mongo.Database) IRecommendationRepository {
+	return &recommendationRepository{
+		Collection: db.Collection("recommendations"),
+	}
+}
+
+func (r *recommendationRepository) EnsureIndexes() error {
+	_, err := r.Collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "kind", Value: 1}, {Key: "subject_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "generated_at", Value: 1}}},
+	})
+	return err
+}
+
+// GetRecommendation returns the recommendations of a user or track, or nil.
+func (r *recommendationRepository) GetRecommendation(kind string, subjectID primitive.ObjectID) (*models.Recommendation, error) {
+	var rec models.Recommendation
+	err := r.Collection.FindOne(context.Background(), bson.M{"kind": kind, "subject_id": subjectID}).Decode(&rec)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// SaveRecommendations replaces the recommendations of each subject, in
+// unordered bulk writes of recommendationBatchSize.
+func (r *recommendationRepository) SaveRecommendations(recs []models.Recommendation) error {
+	ctx := context.Background()
+	for start := 0; start < len(recs); start += recommendationBatchSize {
+		batch := recs[start:min(start+recommendationBatchSize, len(recs))]
+		writes := make([]mongo.WriteModel, len(batch))
+		for i, rec := range batch {
+			rec.ID = primitive.NilObjectID // keep the stored _id
+			writes[i] = mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"kind": rec.Kind, "subject_id": rec.SubjectID}).
+				SetReplacement(rec).
+				SetUpsert(true)
+		}
+		if _, err := r.Collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteGeneratedBefore removes recommendations a run did not refresh, such
+// as those of deleted tracks.
+func (r *recommendationRepository) DeleteGeneratedBefore(t time.Time) (int64, error) {
+	res, err := r.Collection.DeleteMany(context.Background(), bson.M{"generated_at": bson.M{"$lt": t}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
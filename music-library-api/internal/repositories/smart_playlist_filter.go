@@ -0,0 +1,398 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"music-library-api/internal/models"
+	"music-library-api/pkg/utils"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type smartFieldKind int
+
+const (
+	smartText    smartFieldKind = iota // folded copy in "search", accent- and case-insensitive
+	smartKeyword                       // exact value, e.g. a license
+	smartTags                          // lowercase string array
+	smartNumber
+	smartDate
+	smartBool
+	smartObjectID
+	smartLiked // whether the owner of the playlist likes the track
+)
+
+type smartField struct {
+	path string
+	kind smartFieldKind
+}
+
+// smartFields are the track fields rules can use.
+var smartFields = map[string]smartField{
+	"title":        {"search.title", smartText},
+	"artist":       {"search.artist", smartText},
+	"album":        {"search.album", smartText},
+	"genre":        {"search.genre", smartText},
+	"tag":          {"tags", smartTags},
+	"license":      {"license", smartKeyword},
+	"isrc":         {"isrc", smartKeyword},
+	"release_year": {"release_year", smartNumber},
+	"duration":     {"duration", smartNumber},
+	"play_count":   {"play_count", smartNumber},
+	"like_count":   {"like_count", smartNumber},
+	"created_at":   {"created_at", smartDate},
+	"explicit":     {"explicit", smartBool},
+	"uploader":     {"user_id", smartObjectID},
+	"liked":        {"_id", smartLiked},
+}
+
+var smartOperators = map[smartFieldKind][]string{
+	smartText: {models.SmartIs, models.SmartIsNot, models.SmartContains, models.SmartNotContains,
+		models.SmartStartsWith, models.SmartEndsWith, models.SmartIn, models.SmartNotIn},
+	smartKeyword:  {models.SmartIs, models.SmartIsNot, models.SmartIn, models.SmartNotIn},
+	smartTags:     {models.SmartIs, models.SmartIsNot, models.SmartContains, models.SmartIn, models.SmartNotIn},
+	smartNumber:   {models.SmartIs, models.SmartIsNot, models.SmartGreater, models.SmartGreaterEq, models.SmartLess, models.SmartLessEq, models.SmartBetween},
+	smartDate:     {models.SmartBefore, models.SmartAfter, models.SmartInLast},
+	smartBool:     {models.SmartIs},
+	smartObjectID: {models.SmartIs, models.SmartIsNot, models.SmartIn, models.SmartNotIn},
+	smartLiked:    {models.SmartIs},
+}
+
+// smartSortPaths maps SmartSort* fields to document paths.
+var smartSortPaths = map[string]string{
+	models.SmartSortTitle:       "search.title",
+	models.SmartSortArtist:      "search.artist",
+	models.SmartSortAlbum:       "search.album",
+	models.SmartSortReleaseYear: "release_year",
+	models.SmartSortDuration:    "duration",
+	models.SmartSortPlayCount:   "play_count",
+	models.SmartSortLikeCount:   "like_count",
+	models.SmartSortCreatedAt:   "created_at",
+}
+
+const (
+	smartMaxDepth      = 5
+	smartMaxConditions = 50
+)
+
+// SmartContext is what rules are evaluated against besides the tracks
+// themselves. The zero value is enough to validate rules.
+type SmartContext struct {
+	LikedTrackIDs []primitive.ObjectID // liked by the owner of the playlist
+}
+
+// CompileSmartRules turns a rule tree into a track filter. It is also the
+// validator: any rule it cannot compile is reported as an error.
+func CompileSmartRules(rule models.SmartRule, ctx SmartContext) (bson.M, error) {
+	if !rule.IsGroup() && rule.Field == "" {
+		// No rules at all: every track
+		rule.Combinator = models.SmartAll
+	}
+	conditions := 0
+	return compileSmartRule(rule, ctx, 1, &conditions)
+}
+
+// SmartRulesUseField reports whether any condition of a rule tree is on field,
+// so that context only some rules need is loaded only for them.
+func SmartRulesUseField(rule models.SmartRule, field string) bool {
+	if rule.Field == field {
+		return true
+	}
+	for _, child := range rule.Rules {
+		if SmartRulesUseField(child, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// SmartSortBSON returns the sort of a smart playlist, with _id as tie-breaker.
+func SmartSortBSON(smart *models.SmartPlaylist) (bson.D, error) {
+	if smart.Sort == "" {
+		return stableSort, nil
+	}
+	path, ok := smartSortPaths[smart.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort field %q", smart.Sort)
+	}
+	dir := 1
+	if smart.Desc {
+		dir = -1
+	}
+	return bson.D{{Key: path, Value: dir}, {Key: "_id", Value: dir}}, nil
+}
+
+func compileSmartRule(rule models.SmartRule, ctx SmartContext, depth int, conditions *int) (bson.M, error) {
+	if depth > smartMaxDepth {
+		return nil, fmt.Errorf("rules may be nested at most %d levels deep", smartMaxDepth)
+	}
+
+	if rule.IsGroup() {
+		if rule.Field != "" || rule.Operator != "" || rule.Value != nil {
+			return nil, errors.New("a rule is either a group or a condition, not both")
+		}
+		op := "$and"
+		switch rule.Combinator {
+		case models.SmartAll, "":
+		case models.SmartAny:
+			op = "$or"
+		default:
+			return nil, fmt.Errorf("combinator must be %q or %q", models.SmartAll, models.SmartAny)
+		}
+		if len(rule.Rules) == 0 {
+			if depth > 1 {
+				return nil, errors.New("a nested group needs at least one rule")
+			}
+			return bson.M{}, nil // an empty root matches every track
+		}
+
+		parts := make(bson.A, len(rule.Rules))
+		for i, child := range rule.Rules {
+			f, err := compileSmartRule(child, ctx, depth+1, conditions)
+			if err != nil {
+				return nil, err
+			}
+			parts[i] = f
+		}
+		return bson.M{op: parts}, nil
+	}
+
+	if *conditions++; *conditions > smartMaxConditions {
+		return nil, fmt.Errorf("a smart playlist may have at most %d conditions", smartMaxConditions)
+	}
+	return compileSmartCondition(rule, ctx)
+}
+
+func compileSmartCondition(rule models.SmartRule, ctx SmartContext) (bson.M, error) {
+	field, ok := smartFields[rule.Field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", rule.Field)
+	}
+	allowed := false
+	for _, op := range smartOperators[field.kind] {
+		allowed = allowed || op == rule.Operator
+	}
+	if !allowed {
+		return nil, fmt.Errorf("field %q does not support operator %q (use one of %s)",
+			rule.Field, rule.Operator, strings.Join(smartOperators[field.kind], ", "))
+	}
+
+	invalid := func(expected string) error {
+		return fmt.Errorf("field %q with %q needs %s", rule.Field, rule.Operator, expected)
+	}
+
+	switch field.kind {
+	case smartText, smartKeyword, smartTags:
+		normalize := func(s string) string { return strings.TrimSpace(s) }
+		switch field.kind {
+		case smartText:
+			normalize = func(s string) string { return utils.FoldDiacritics(strings.TrimSpace(s)) }
+		case smartTags:
+			normalize = func(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+		}
+
+		if rule.Operator == models.SmartIn || rule.Operator == models.SmartNotIn {
+			values, ok := smartStrings(rule.Value)
+			if !ok || len(values) == 0 {
+				return nil, invalid("a non-empty list of strings")
+			}
+			for i := range values {
+				values[i] = normalize(values[i])
+			}
+			if rule.Operator == models.SmartIn {
+				return bson.M{field.path: bson.M{"$in": values}}, nil
+			}
+			return bson.M{field.path: bson.M{"$nin": values}}, nil
+		}
+
+		s, ok := rule.Value.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return nil, invalid("a non-empty string")
+		}
+		value := normalize(s)
+		quoted := regexp.QuoteMeta(value)
+		switch rule.Operator {
+		case models.SmartIs:
+			return bson.M{field.path: value}, nil
+		case models.SmartIsNot:
+			return bson.M{field.path: bson.M{"$ne": value}}, nil
+		case models.SmartContains:
+			return bson.M{field.path: primitive.Regex{Pattern: quoted}}, nil
+		case models.SmartNotContains:
+			return bson.M{field.path: bson.M{"$not": primitive.Regex{Pattern: quoted}}}, nil
+		case models.SmartStartsWith:
+			return bson.M{field.path: primitive.Regex{Pattern: "^" + quoted}}, nil
+		case models.SmartEndsWith:
+			return bson.M{field.path: primitive.Regex{Pattern: quoted + "$"}}, nil
+		}
+
+	case smartNumber:
+		if rule.Operator == models.SmartBetween {
+			values, ok := smartNumbers(rule.Value)
+			if !ok || len(values) != 2 || values[0] > values[1] {
+				return nil, invalid("a list [min, max]")
+			}
+			return bson.M{field.path: bson.M{"$gte": values[0], "$lte": values[1]}}, nil
+		}
+		n, ok := smartNumberValue(rule.Value)
+		if !ok {
+			return nil, invalid("a number")
+		}
+		op := map[string]string{
+			models.SmartIs:        "$eq",
+			models.SmartIsNot:     "$ne",
+			models.SmartGreater:   "$gt",
+			models.SmartGreaterEq: "$gte",
+			models.SmartLess:      "$lt",
+			models.SmartLessEq:    "$lte",
+		}[rule.Operator]
+		return bson.M{field.path: bson.M{op: n}}, nil
+
+	case smartDate:
+		if rule.Operator == models.SmartInLast {
+			days, ok := smartNumberValue(rule.Value)
+			if !ok || days <= 0 {
+				return nil, invalid("a positive number of days")
+			}
+			since := time.Now().Add(-time.Duration(days * float64(24*time.Hour)))
+			return bson.M{field.path: bson.M{"$gte": since}}, nil
+		}
+		s, _ := rule.Value.(string)
+		t, err := smartDateValue(s)
+		if err != nil {
+			return nil, invalid("a date like 2024-01-31 or an RFC 3339 time")
+		}
+		if rule.Operator == models.SmartBefore {
+			return bson.M{field.path: bson.M{"$lt": t}}, nil
+		}
+		return bson.M{field.path: bson.M{"$gte": t}}, nil
+
+	case smartBool:
+		b, ok := rule.Value.(bool)
+		if !ok {
+			return nil, invalid("true or false")
+		}
+		return bson.M{field.path: b}, nil
+
+	case smartLiked:
+		liked, ok := rule.Value.(bool)
+		if !ok {
+			return nil, invalid("true or false")
+		}
+		ids := ctx.LikedTrackIDs
+		if ids == nil {
+			ids = []primitive.ObjectID{}
+		}
+		if liked {
+			return bson.M{field.path: bson.M{"$in": ids}}, nil
+		}
+		return bson.M{field.path: bson.M{"$nin": ids}}, nil
+
+	case smartObjectID:
+		var hexes []string
+		if rule.Operator == models.SmartIn || rule.Operator == models.SmartNotIn {
+			hexes, ok = smartStrings(rule.Value)
+		} else {
+			var s string
+			s, ok = rule.Value.(string)
+			hexes = []string{s}
+		}
+		if !ok || len(hexes) == 0 {
+			return nil, invalid("user IDs")
+		}
+		ids, err := utils.ConvertToObjectIDs(hexes)
+		if err != nil {
+			return nil, invalid("valid user IDs")
+		}
+		switch rule.Operator {
+		case models.SmartIs:
+			return bson.M{field.path: ids[0]}, nil
+		case models.SmartIsNot:
+			return bson.M{field.path: bson.M{"$ne": ids[0]}}, nil
+		case models.SmartIn:
+			return bson.M{field.path: bson.M{"$in": ids}}, nil
+		default:
+			return bson.M{field.path: bson.M{"$nin": ids}}, nil
+		}
+	}
+	return nil, fmt.Errorf("field %q does not support operator %q", rule.Field, rule.Operator)
+}
+
+// smartList accepts a list from JSON ([]interface{}) or from BSON (primitive.A).
+func smartList(v interface{}) ([]interface{}, bool) {
+	switch list := v.(type) {
+	case []interface{}:
+		return list, true
+	case primitive.A:
+		return list, true
+	}
+	return nil, false
+}
+
+func smartStrings(v interface{}) ([]string, bool) {
+	list, ok := smartList(v)
+	if !ok {
+		return nil, false
+	}
+	out := make([]string, len(list))
+	for i, item := range list {
+		s, ok := item.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return nil, false
+		}
+		out[i] = s
+	}
+	return out, true
+}
+
+func smartNumbers(v interface{}) ([]float64, bool) {
+	list, ok := smartList(v)
+	if !ok {
+		return nil, false
+	}
+	out := make([]float64, len(list))
+	for i, item := range list {
+		if out[i], ok = smartNumberValue(item); !ok {
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+// smartNumberValue reads numbers decoded from JSON or BSON, or numeric strings.
+func smartNumberValue(v interface{}) (float64, bool) {
+	var n float64
+	switch x := v.(type) {
+	case float64:
+		n = x
+	case int:
+		n = float64(x)
+	case int32:
+		n = float64(x)
+	case int64:
+		n = float64(x)
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		if err != nil {
+			return 0, false
+		}
+		n = f
+	default:
+		return 0, false
+	}
+	return n, !math.IsNaN(n) && !math.IsInf(n, 0)
+}
+
+func smartDateValue(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
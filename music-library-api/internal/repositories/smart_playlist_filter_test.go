@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"music-library-api/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func cond(field, op string, value interface{}) models.SmartRule {
+	return models.SmartRule{Field: field, Operator: op, Value: value}
+}
+
+func TestCompileSmartRules(t *testing.T) {
+	uploader := primitive.NewObjectID()
+	liked := []primitive.ObjectID{primitive.NewObjectID()}
+
+	tests := []struct {
+		name string
+		rule models.SmartRule
+		ctx  SmartContext
+		want bson.M
+	}{
+		{
+			name: "no rules matches everything",
+			rule: models.SmartRule{},
+			want: bson.M{},
+		},
+		{
+			name: "text is folded",
+			rule: cond("artist", models.SmartIs, "  Bùi Anh Tuấn "),
+			want: bson.M{"search.artist": "bui anh tuan"},
+		},
+		{
+			name: "contains escapes regex metacharacters",
+			rule: cond("title", models.SmartContains, "a.b(c)*+?[x]"),
+			want: bson.M{"search.title": primitive.Regex{Pattern: `a\.b\(c\)\*\+\?\[x\]`}},
+		},
+		{
+			name: "starts_with anchors the escaped value",
+			rule: cond("album", models.SmartStartsWith, "^Hits$"),
+			want: bson.M{"search.album": primitive.Regex{Pattern: `^\^hits\$`}},
+		},
+		{
+			name: "ends_with anchors the escaped value",
+			rule: cond("genre", models.SmartEndsWith, "R|B"),
+			want: bson.M{"search.genre": primitive.Regex{Pattern: `r\|b$`}},
+		},
+		{
+			name: "not_contains",
+			rule: cond("title", models.SmartNotContains, "live"),
+			want: bson.M{"search.title": bson.M{"$not": primitive.Regex{Pattern: "live"}}},
+		},
+		{
+			name: "tags are lowercased",
+			rule: cond("tag", models.SmartIn, []interface{}{"Chill", " LoFi "}),
+			want: bson.M{"tags": bson.M{"$in": []string{"chill", "lofi"}}},
+		},
+		{
+			name: "keywords are kept as typed",
+			rule: cond("license", models.SmartNotIn, primitive.A{"cc-by", "cc0"}),
+			want: bson.M{"license": bson.M{"$nin": []string{"cc-by", "cc0"}}},
+		},
+		{
+			name: "number from a numeric string",
+			rule: cond("release_year", models.SmartGreaterEq, "2015"),
+			want: bson.M{"release_year": bson.M{"$gte": 2015.0}},
+		},
+		{
+			name: "between",
+			rule: cond("duration", models.SmartBetween, []interface{}{120.0, int32(300)}),
+			want: bson.M{"duration": bson.M{"$gte": 120.0, "$lte": 300.0}},
+		},
+		{
+			name: "date before",
+			rule: cond("created_at", models.SmartBefore, "2024-01-31"),
+			want: bson.M{"created_at": bson.M{"$lt": time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name: "bool",
+			rule: cond("explicit", models.SmartIs, false),
+			want: bson.M{"explicit": false},
+		},
+		{
+			name: "uploader",
+			rule: cond("uploader", models.SmartIs, uploader.Hex()),
+			want: bson.M{"user_id": uploader},
+		},
+		{
+			name: "liked uses the context",
+			rule: cond("liked", models.SmartIs, true),
+			ctx:  SmartContext{LikedTrackIDs: liked},
+			want: bson.M{"_id": bson.M{"$in": liked}},
+		},
+		{
+			name: "not liked without likes matches everything",
+			rule: cond("liked", models.SmartIs, false),
+			want: bson.M{"_id": bson.M{"$nin": []primitive.ObjectID{}}},
+		},
+		{
+			name: "nested groups",
+			rule: models.SmartRule{Combinator: models.SmartAll, Rules: []models.SmartRule{
+				cond("genre", models.SmartIs, "Ballad"),
+				{Combinator: models.SmartAny, Rules: []models.SmartRule{
+					cond("play_count", models.SmartGreater, 10.0),
+					cond("like_count", models.SmartGreater, 3.0),
+				}},
+			}},
+			want: bson.M{"$and": bson.A{
+				bson.M{"search.genre": "ballad"},
+				bson.M{"$or": bson.A{
+					bson.M{"play_count": bson.M{"$gt": 10.0}},
+					bson.M{"like_count": bson.M{"$gt": 3.0}},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompileSmartRules(tt.rule, tt.ctx)
+			if err != nil {
+				t.Fatalf("CompileSmartRules() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompileSmartRules() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileSmartRulesInLast(t *testing.T) {
+	got, err := CompileSmartRules(cond("created_at", models.SmartInLast, 7.0), SmartContext{})
+	if err != nil {
+		t.Fatalf("CompileSmartRules() error = %v", err)
+	}
+	since, ok := got["created_at"].(bson.M)["$gte"].(time.Time)
+	if !ok || time.Since(since) < 7*24*time.Hour || time.Since(since) > 7*24*time.Hour+time.Minute {
+		t.Errorf("CompileSmartRules() = %v, want created_at >= 7 days ago", got)
+	}
+}
+
+func TestCompileSmartRulesErrors(t *testing.T) {
+	deep := cond("title", models.SmartIs, "x")
+	for i := 0; i < smartMaxDepth; i++ {
+		deep = models.SmartRule{Combinator: models.SmartAll, Rules: []models.SmartRule{deep}}
+	}
+	many := models.SmartRule{Combinator: models.SmartAny}
+	for i := 0; i <= smartMaxConditions; i++ {
+		many.Rules = append(many.Rules, cond("title", models.SmartIs, "x"))
+	}
+
+	tests := []struct {
+		name string
+		rule models.SmartRule
+		want string
+	}{
+		{"unknown field", cond("mood", models.SmartIs, "sad"), "unknown field"},
+		{"unsupported operator", cond("duration", models.SmartContains, "3"), "does not support operator"},
+		{"empty string", cond("title", models.SmartIs, "  "), "non-empty string"},
+		{"empty list", cond("title", models.SmartIn, []interface{}{}), "non-empty list"},
+		{"not a number", cond("duration", models.SmartLess, "long"), "a number"},
+		{"reversed between", cond("duration", models.SmartBetween, []interface{}{300.0, 100.0}), "[min, max]"},
+		{"bad date", cond("created_at", models.SmartAfter, "yesterday"), "a date"},
+		{"non-positive in_last", cond("created_at", models.SmartInLast, 0.0), "positive number"},
+		{"bad uploader", cond("uploader", models.SmartIs, "nope"), "valid user IDs"},
+		{"bad combinator", models.SmartRule{Combinator: "xor", Rules: []models.SmartRule{cond("title", models.SmartIs, "x")}}, "combinator"},
+		{"group and condition", models.SmartRule{Combinator: models.SmartAll, Field: "title"}, "not both"},
+		{"empty nested group", models.SmartRule{Combinator: models.SmartAll, Rules: []models.SmartRule{{Combinator: models.SmartAny}}}, "at least one rule"},
+		{"too deep", deep, "nested at most"},
+		{"too many conditions", many, "at most"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileSmartRules(tt.rule, SmartContext{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CompileSmartRules() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmartSortBSON(t *testing.T) {
+	tests := []struct {
+		smart   models.SmartPlaylist
+		want    bson.D
+		wantErr bool
+	}{
+		{models.SmartPlaylist{}, stableSort, false},
+		{models.SmartPlaylist{Sort: models.SmartSortPlayCount, Desc: true}, bson.D{{Key: "play_count", Value: -1}, {Key: "_id", Value: -1}}, false},
+		{models.SmartPlaylist{Sort: models.SmartSortTitle}, bson.D{{Key: "search.title", Value: 1}, {Key: "_id", Value: 1}}, false},
+		{models.SmartPlaylist{Sort: "mood"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := SmartSortBSON(&tt.smart)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SmartSortBSON(%+v) = %v, %v, want %v", tt.smart, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"music-library-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ISuggestRepository interface {
+	LoadSuggestEntries() ([]models.SuggestEntry, error)
+}
+
+type suggestRepository struct {
+	db *mongo.Database
+}
+
+func NewSuggestRepository(db *mongo.Database) ISuggestRepository {
+	return &suggestRepository{db: db}
+}
+
+// LoadSuggestEntries reads the small projection of tracks, artists, albums,
+// playlists and tags needed to build the autocomplete index.
+func (r *suggestRepository) LoadSuggestEntries() ([]models.SuggestEntry, error) {
+	entries := []models.SuggestEntry{}
+	loaders := []func() ([]models.SuggestEntry, error){
+		r.loadTracks,
+		r.loadArtists,
+		r.loadAlbums,
+		r.loadPlaylists,
+		r.loadTags,
+	}
+	for _, load := range loaders {
+		loaded, err := load()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, loaded...)
+	}
+	return entries, nil
+}
+
+func (r *suggestRepository) loadTracks() ([]models.SuggestEntry, error) {
+	opts := options.Find().SetProjection(bson.M{"title": 1, "artist": 1, "play_count": 1})
+	cursor, err := r.db.Collection("tracks").Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		ID        primitive.ObjectID `bson:"_id"`
+		Title     string             `bson:"title"`
+		Artist    string             `bson:"artist"`
+		PlayCount int64              `bson:"play_count"`
+	}
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.SuggestEntry, len(docs))
+	for i, d := range docs {
+		entries[i] = models.SuggestEntry{Kind: models.SuggestTrack, ID: d.ID, Text: d.Title, Subtitle: d.Artist, Weight: float64(d.PlayCount)}
+	}
+	return entries, nil
+}
+
+// loadArtists lists the distinct artist names of tracks, weighted by total plays.
+func (r *suggestRepository) loadArtists() ([]models.SuggestEntry, error) {
+	return r.groupNames("tracks", mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"artist": bson.M{"$nin": bson.A{nil, ""}}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$artist", "weight": bson.M{"$sum": "$play_count"}}}},
+	}, models.SuggestArtist)
+}
+
+func (r *suggestRepository) loadAlbums() ([]models.SuggestEntry, error) {
+	opts := options.Find().SetProjection(bson.M{"title": 1, "artist": 1, "tracks": 1})
+	cursor, err := r.db.Collection("albums").Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Title  string             `bson:"title"`
+		Artist string             `bson:"artist"`
+		Tracks []bson.Raw         `bson:"tracks"`
+	}
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.SuggestEntry, len(docs))
+	for i, d := range docs {
+		entries[i] = models.SuggestEntry{Kind: models.SuggestAlbum, ID: d.ID, Text: d.Title, Subtitle: d.Artist, Weight: float64(len(d.Tracks))}
+	}
+	return entries, nil
+}
+
+// suggestPlaylistFilter keeps suggestions to the playlists anyone may list:
+// the suggest endpoint is public, so private and unlisted playlists and the
+// per-user Liked Songs playlists must stay out of the index.
+var suggestPlaylistFilter = bson.M{
+	"visibility": models.PlaylistVisibilityPublic,
+	"kind":       bson.M{"$exists": false},
+}
+
+type suggestPlaylistDoc struct {
+	ID         primitive.ObjectID   `bson:"_id"`
+	Title      string               `bson:"title"`
+	TrackIDs   []primitive.ObjectID `bson:"track_ids"`
+	Visibility string               `bson:"visibility"`
+	Kind       string               `bson:"kind"`
+}
+
+func (r *suggestRepository) loadPlaylists() ([]models.SuggestEntry, error) {
+	opts := options.Find().SetProjection(bson.M{"title": 1, "track_ids": 1, "visibility": 1, "kind": 1})
+	cursor, err := r.db.Collection("playlists").Find(context.Background(), suggestPlaylistFilter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []suggestPlaylistDoc
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		return nil, err
+	}
+	return playlistSuggestions(docs), nil
+}
+
+// playlistSuggestions repeats the check of suggestPlaylistFilter, so that a
+// change to the query cannot publish a private playlist.
+func playlistSuggestions(docs []suggestPlaylistDoc) []models.SuggestEntry {
+	entries := make([]models.SuggestEntry, 0, len(docs))
+	for _, d := range docs {
+		if d.Visibility != models.PlaylistVisibilityPublic || d.Kind != "" {
+			continue
+		}
+		entries = append(entries, models.SuggestEntry{Kind: models.SuggestPlaylist, ID: d.ID, Text: d.Title, Weight: float64(len(d.TrackIDs))})
+	}
+	return entries
+}
+
+// loadTags lists the distinct track tags, weighted by usage.
+func (r *suggestRepository) loadTags() ([]models.SuggestEntry, error) {
+	return r.groupNames("tracks", mongo.Pipeline{
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "weight": bson.M{"$sum": 1}}}},
+	}, models.SuggestTag)
+}
+
+func (r *suggestRepository) groupNames(collection string, pipeline mongo.Pipeline, kind string) ([]models.SuggestEntry, error) {
+	cursor, err := r.db.Collection(collection).Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		Name   string  `bson:"_id"`
+		Weight float64 `bson:"weight"`
+	}
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.SuggestEntry, len(docs))
+	for i, d := range docs {
+		entries[i] = models.SuggestEntry{Kind: kind, Text: d.Name, Weight: d.Weight}
+	}
+	return entries, nil
+}
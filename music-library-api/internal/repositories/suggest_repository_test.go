@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"music-library-api/internal/models"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPlaylistSuggestions(t *testing.T) {
+	public := suggestPlaylistDoc{ID: primitive.NewObjectID(), Title: "Road Trip", TrackIDs: make([]primitive.ObjectID, 3), Visibility: models.PlaylistVisibilityPublic}
+
+	tests := []struct {
+		name string
+		doc  suggestPlaylistDoc
+		want []models.SuggestEntry
+	}{
+		{
+			name: "public",
+			doc:  public,
+			want: []models.SuggestEntry{{Kind: models.SuggestPlaylist, ID: public.ID, Text: "Road Trip", Weight: 3}},
+		},
+		{
+			name: "private",
+			doc:  suggestPlaylistDoc{ID: primitive.NewObjectID(), Title: "Diary", Visibility: models.PlaylistVisibilityPrivate},
+			want: []models.SuggestEntry{},
+		},
+		{
+			name: "unlisted",
+			doc:  suggestPlaylistDoc{ID: primitive.NewObjectID(), Title: "For Friends", Visibility: models.PlaylistVisibilityUnlisted},
+			want: []models.SuggestEntry{},
+		},
+		{
+			name: "liked songs",
+			doc:  suggestPlaylistDoc{ID: primitive.NewObjectID(), Title: "Liked Songs", Visibility: models.PlaylistVisibilityPublic, Kind: models.PlaylistKindLikedSongs},
+			want: []models.SuggestEntry{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := playlistSuggestions([]suggestPlaylistDoc{tt.doc}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("playlistSuggestions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestPlaylistFilter(t *testing.T) {
+	if suggestPlaylistFilter["visibility"] != models.PlaylistVisibilityPublic {
+		t.Errorf("suggest query does not restrict visibility: %v", suggestPlaylistFilter)
+	}
+	if !reflect.DeepEqual(suggestPlaylistFilter["kind"], bson.M{"$exists": false}) {
+		t.Errorf("suggest query does not leave out Liked Songs playlists: %v", suggestPlaylistFilter)
+	}
+}
@@ -2,7 +2,11 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"music-library-api/internal/models"
+	"music-library-api/pkg/utils"
+	"regexp"
+	"strings"
 
 	"github.com/kamva/mgm/v3"
 	"go.mongodb.org/mongo-driver/bson"
@@ -13,16 +17,73 @@ import (
 
 type ITrackRepository interface {
 	GetTrackByID(id string) (*models.Track, error)
-	GetTracks(page, limit int, userID string) ([]*models.Track, error)
-	CountTracks(userID string) (int64, error)
+	GetTracks(page, limit int, filter models.TrackFilter) ([]*models.Track, error)
+	GetTracksByCursor(cursor *utils.Cursor, limit int, filter models.TrackFilter) ([]*models.Track, utils.CursorPage, error)
+	CountTracks(filter models.TrackFilter) (int64, error)
 	CreateTrack(track *models.Track) error
 	UpdateTrack(track *models.Track) error
 	DeleteTrack(id string) error
-	SearchTracks(query string, page, limit int, userID string) ([]*models.Track, error)
-	CountSearchTracks(query string, userID string) (int64, error)
+	SearchTracks(query string, page, limit int, filter models.TrackFilter, sort models.TrackSort) ([]*models.TrackSearchHit, error)
+	SearchFacets(query string, filter models.TrackFilter) (*models.TrackFacets, error)
+	CountSearchTracks(query string, filter models.TrackFilter) (int64, error)
 	GetTracksByIDs(ids []primitive.ObjectID) ([]*models.Track, error)
+	FindTracksByFileNames(names []string) (map[string][]*models.Track, error)
+	FindTracksByTitles(titles []string) ([]*models.Track, error)
+	FindSmartTracks(smart *models.SmartPlaylist, ctx SmartContext) ([]*models.Track, error)
 	FindMissingIDs(ids []primitive.ObjectID) ([]primitive.ObjectID, error)
 	ExistAllByIDs(ids []primitive.ObjectID) (bool, error)
+	GetTracksByUser(userID primitive.ObjectID) ([]*models.Track, error)
+	GetUserTrackStats(userID primitive.ObjectID) (*TrackStats, error)
+	GetTracksByGenreIDs(genreIDs []primitive.ObjectID, page, limit int) ([]*models.Track, error)
+	CountTracksByGenreIDs(genreIDs []primitive.ObjectID) (int64, error)
+	ReassignGenre(fromIDs []primitive.ObjectID, toID primitive.ObjectID, name string) (int64, error)
+	LinkUnassignedGenre(names []string, genreID primitive.ObjectID, name string) (int64, error)
+	SetLyricsText(id primitive.ObjectID, text string) error
+	IncrementPlayCount(id primitive.ObjectID) (int64, error)
+	IncrementLikeCount(id primitive.ObjectID, delta int64) (int64, error)
+	GetPopularTracks(filter models.TrackFilter, limit int) ([]*models.Track, error)
+	GetTrackFeatures() ([]*models.Track, error)
+	BackfillSearchFields() (int64, error)
+	BackfillPlayCounts() (int64, error)
+	EnsureSearchIndex() error
+}
+
+// TrackStats aggregates the tracks owned by one uploader.
+type TrackStats struct {
+	TrackCount    int64 `bson:"track_count"`
+	TotalDuration int64 `bson:"total_duration"`
+	PlayCount     int64 `bson:"play_count"`
+}
+
+// Text index weights: title > artist > album > genre > lyrics.
+// The accent-folded copies carry the same weight as the originals.
+var trackSearchWeights = bson.D{
+	{Key: "title", Value: 10},
+	{Key: "search.title", Value: 10},
+	{Key: "artist", Value: 6},
+	{Key: "search.artist", Value: 6},
+	{Key: "album", Value: 4},
+	{Key: "search.album", Value: 4},
+	{Key: "genre", Value: 2},
+	{Key: "search.genre", Value: 2},
+	{Key: "lyrics_folded", Value: 1},
+}
+
+const trackSearchIndex = "track_search"
+
+// Facet limits and duration bucket boundaries, in seconds
+const facetLimit = 20
+
+var durationBoundaries = []int{0, 120, 180, 240, 300, 420, 600}
+
+// Sortable fields; text fields sort on their accent-folded copies
+var trackSortKeys = map[string]string{
+	models.TrackSortRelevance:   "score",
+	models.TrackSortTitle:       "search.title",
+	models.TrackSortArtist:      "search.artist",
+	models.TrackSortReleaseYear: "release_year",
+	models.TrackSortDuration:    "duration",
+	models.TrackSortCreatedAt:   "created_at",
 }
 
 type trackRepository struct {
@@ -46,20 +107,12 @@ func (r *trackRepository) GetTrackByID(id string) (*models.Track, error) {
 }
 
 // Get paginated tracks
-func (r *trackRepository) GetTracks(page, limit int, userID string) ([]*models.Track, error) {
+func (r *trackRepository) GetTracks(page, limit int, filter models.TrackFilter) ([]*models.Track, error) {
 	tracks := []*models.Track{}
 	skip := int64((page - 1) * limit)
-	opts := options.Find().SetSkip(skip).SetLimit(int64(limit))
-
-	filter := bson.M{}
-	if userID != "" {
-		objID, err := primitive.ObjectIDFromHex(userID)
-		if err == nil {
-			filter["user_id"] = objID
-		}
-	}
+	opts := options.Find().SetSort(stableSort).SetSkip(skip).SetLimit(int64(limit))
 
-	cursor, err := mgm.Coll(&models.Track{}).Find(context.Background(), filter, opts)
+	cursor, err := mgm.Coll(&models.Track{}).Find(context.Background(), trackFilterBSON(filter), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -69,15 +122,13 @@ func (r *trackRepository) GetTracks(page, limit int, userID string) ([]*models.T
 	return tracks, nil
 }
 
-func (r *trackRepository) CountTracks(userID string) (int64, error) {
-	filter := bson.M{}
-	if userID != "" {
-		objID, err := primitive.ObjectIDFromHex(userID)
-		if err == nil {
-			filter["user_id"] = objID
-		}
-	}
-	return mgm.Coll(&models.Track{}).CountDocuments(context.Background(), filter)
+// GetTracksByCursor is the keyset-paginated variant of GetTracks.
+func (r *trackRepository) GetTracksByCursor(cursor *utils.Cursor, limit int, filter models.TrackFilter) ([]*models.Track, utils.CursorPage, error) {
+	return findByCursor[*models.Track](r.Collection, trackFilterBSON(filter), cursor, limit)
+}
+
+func (r *trackRepository) CountTracks(filter models.TrackFilter) (int64, error) {
+	return mgm.Coll(&models.Track{}).CountDocuments(context.Background(), trackFilterBSON(filter))
 }
 
 // Create a new track
@@ -85,9 +136,40 @@ func (r *trackRepository) CreateTrack(track *models.Track) error {
 	return mgm.Coll(track).Create(track)
 }
 
-// Update track
+// trackCounters are only ever changed with $inc, by IncrementPlayCount and
+// IncrementLikeCount. UpdateTrack leaves them out, otherwise saving a track
+// read a moment ago would write back its stale counts and lose the plays and
+// likes counted since.
+var trackCounters = []string{"play_count", "like_count"}
+
+// Update track. Like mgm's Update it runs the Saving hook and sets the whole
+// document, except for the counters.
 func (r *trackRepository) UpdateTrack(track *models.Track) error {
-	return mgm.Coll(track).Update(track)
+	if err := track.Saving(); err != nil {
+		return err
+	}
+	set, err := trackUpdateDoc(track)
+	if err != nil {
+		return err
+	}
+	_, err = r.Collection.UpdateOne(context.Background(), bson.M{"_id": track.ID}, bson.M{"$set": set})
+	return err
+}
+
+func trackUpdateDoc(track *models.Track) (bson.M, error) {
+	raw, err := bson.Marshal(track)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	delete(doc, "_id")
+	for _, field := range trackCounters {
+		delete(doc, field)
+	}
+	return doc, nil
 }
 
 // Delete track
@@ -99,70 +181,267 @@ func (r *trackRepository) DeleteTrack(id string) error {
 	return mgm.Coll(track).Delete(track)
 }
 
-// Search tracks by title, artist, album, genre (basic)
-func (r *trackRepository) SearchTracks(query string, page, limit int, userID string) ([]*models.Track, error) {
-	tracks := []*models.Track{}
+// SearchTracks runs a full-text search over title, artist, album, genre and
+// lyrics. Matches with the query's exact diacritics get a relevance boost.
+func (r *trackRepository) SearchTracks(query string, page, limit int, filter models.TrackFilter, sort models.TrackSort) ([]*models.TrackSearchHit, error) {
+	hits := []*models.TrackSearchHit{}
 	skip := int64((page - 1) * limit)
 
-	searchFilter := bson.M{
-		"$or": []bson.M{
-			{"title": bson.M{"$regex": query, "$options": "i"}},
-			{"artist": bson.M{"$regex": query, "$options": "i"}},
-			{"album": bson.M{"$regex": query, "$options": "i"}},
-			{"genre": bson.M{"$regex": query, "$options": "i"}},
-		},
+	score := bson.M{"$literal": 0}
+	if len(utils.SearchTerms(query)) > 0 {
+		score = bson.M{"$meta": "textScore"}
 	}
 
-	filter := searchFilter
-	if userID != "" {
-		objID, err := primitive.ObjectIDFromHex(userID)
-		if err == nil {
-			filter = bson.M{
-				"$and": []bson.M{
-					{"user_id": objID},
-					searchFilter,
-				},
-			}
+	// The raw query is only ever matched literally
+	exactRegex := regexp.QuoteMeta(strings.TrimSpace(query))
+	exact := bson.A{}
+	for _, field := range []string{"$title", "$artist", "$album", "$genre"} {
+		exact = append(exact, bson.M{"$regexMatch": bson.M{"input": field, "regex": exactRegex, "options": "i"}})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: searchFilterBSON(query, filter)}},
+		{{Key: "$addFields", Value: bson.M{"score": score}}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{
+			"$cond": bson.A{bson.M{"$or": exact}, bson.M{"$multiply": bson.A{"$score", 1.5}}, "$score"},
+		}}}},
+		{{Key: "$sort", Value: trackSortBSON(sort)}},
+		{{Key: "$skip", Value: skip}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+
+	cursor, err := r.Collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &hits); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// SearchFacets counts the tracks matching a search per genre, artist, year,
+// tag, uploader and duration range.
+func (r *trackRepository) SearchFacets(query string, filter models.TrackFilter) (*models.TrackFacets, error) {
+	countBy := func(field interface{}, unwind bool) bson.A {
+		stages := bson.A{}
+		if unwind {
+			stages = append(stages, bson.M{"$unwind": field})
 		}
+		return append(stages,
+			bson.M{"$match": bson.M{strings.TrimPrefix(field.(string), "$"): bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$group": bson.M{"_id": field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			bson.M{"$limit": facetLimit},
+		)
 	}
 
-	opts := options.Find().SetSkip(skip).SetLimit(int64(limit))
-	cursor, err := mgm.Coll(&models.Track{}).Find(context.Background(), filter, opts)
+	boundaries := bson.A{}
+	for _, b := range durationBoundaries {
+		boundaries = append(boundaries, b)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: searchFilterBSON(query, filter)}},
+		{{Key: "$project", Value: bson.M{
+			"genre":        1,
+			"artist":       1,
+			"tags":         1,
+			"duration":     1,
+			"release_year": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$release_year", 0}}, bson.M{"$toString": "$release_year"}, nil}},
+			"user_id":      bson.M{"$toString": "$user_id"},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"genres":    countBy("$genre", false),
+			"artists":   countBy("$artist", false),
+			"years":     countBy("$release_year", false),
+			"tags":      countBy("$tags", true),
+			"uploaders": countBy("$user_id", false),
+			"durations": bson.A{
+				bson.M{"$bucket": bson.M{
+					"groupBy":    "$duration",
+					"boundaries": boundaries,
+					"default":    "longer",
+					"output":     bson.M{"count": bson.M{"$sum": 1}},
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := r.Collection.Aggregate(context.Background(), pipeline)
 	if err != nil {
 		return nil, err
 	}
-	if err := cursor.All(context.Background(), &tracks); err != nil {
+	defer cursor.Close(context.Background())
+
+	var result struct {
+		models.TrackFacets `bson:",inline"`
+		Durations          []struct {
+			Lower interface{} `bson:"_id"`
+			Count int64       `bson:"count"`
+		} `bson:"durations"`
+	}
+	if cursor.Next(context.Background()) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
 		return nil, err
 	}
-	return tracks, nil
+
+	facets := result.TrackFacets
+	counts := map[int]int64{}
+	for _, d := range result.Durations {
+		switch lower := d.Lower.(type) {
+		case int32:
+			counts[int(lower)] += d.Count
+		case int64:
+			counts[int(lower)] += d.Count
+		default:
+			// "longer": at or above the last boundary
+			counts[durationBoundaries[len(durationBoundaries)-1]] += d.Count
+		}
+	}
+	for i, lower := range durationBoundaries {
+		upper := 0
+		if i+1 < len(durationBoundaries) {
+			upper = durationBoundaries[i+1]
+		}
+		facets.Durations = append(facets.Durations, models.DurationBucket{Min: lower, Max: upper, Count: counts[lower]})
+	}
+	return &facets, nil
 }
 
-func (r *trackRepository) CountSearchTracks(query string, userID string) (int64, error) {
-	searchFilter := bson.M{
-		"$or": []bson.M{
-			{"title": bson.M{"$regex": query, "$options": "i"}},
-			{"artist": bson.M{"$regex": query, "$options": "i"}},
-			{"album": bson.M{"$regex": query, "$options": "i"}},
-			{"genre": bson.M{"$regex": query, "$options": "i"}},
-		},
+func (r *trackRepository) CountSearchTracks(query string, filter models.TrackFilter) (int64, error) {
+	return mgm.Coll(&models.Track{}).CountDocuments(context.Background(), searchFilterBSON(query, filter))
+}
+
+// searchFilterBSON builds the $text filter from the folded query words. A query
+// without any word matches every track, like an empty regex used to.
+func searchFilterBSON(query string, filter models.TrackFilter) bson.M {
+	f := trackFilterBSON(filter)
+	if terms := utils.SearchTerms(query); len(terms) > 0 {
+		// Plain words only: quotes and "-" would be read as phrase/negation operators
+		f["$text"] = bson.M{"$search": strings.Join(terms, " ")}
+	}
+	return f
+}
+
+// trackSortBSON defaults to relevance, best first. _id keeps pages stable.
+func trackSortBSON(sort models.TrackSort) bson.D {
+	key, ok := trackSortKeys[sort.Field]
+	if !ok {
+		return bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}
+	}
+	order := 1
+	if sort.Desc {
+		order = -1
+	}
+	return bson.D{{Key: key, Value: order}, {Key: "_id", Value: 1}}
+}
+
+// trackFilterBSON translates a TrackFilter into a Mongo filter.
+// Credit names match whole entries, ignoring case.
+func trackFilterBSON(filter models.TrackFilter) bson.M {
+	f := bson.M{}
+	if filter.UserID != "" {
+		if objID, err := primitive.ObjectIDFromHex(filter.UserID); err == nil {
+			f["user_id"] = objID
+		}
+	}
+
+	licenses := filter.Licenses
+	if filter.CreativeCommons {
+		if len(licenses) == 0 {
+			licenses = models.CreativeCommonsLicenses
+		} else {
+			// Only the requested licenses that are Creative Commons
+			licenses = intersectStrings(licenses, models.CreativeCommonsLicenses)
+		}
+	}
+	if filter.CreativeCommons || len(licenses) > 0 {
+		f["license"] = bson.M{"$in": licenses}
+	}
+
+	if filter.Explicit != nil {
+		f["explicit"] = *filter.Explicit
+	}
+	if filter.ISRC != "" {
+		f["isrc"] = filter.ISRC
+	}
+	if filter.Composer != "" {
+		f["composers"] = exactMatchRegex(filter.Composer)
+	}
+	if filter.Lyricist != "" {
+		f["lyricists"] = exactMatchRegex(filter.Lyricist)
+	}
+	if filter.Producer != "" {
+		f["producers"] = exactMatchRegex(filter.Producer)
 	}
 
-	filter := searchFilter
-	if userID != "" {
-		objID, err := primitive.ObjectIDFromHex(userID)
-		if err == nil {
-			filter = bson.M{
-				"$and": []bson.M{
-					{"user_id": objID},
-					searchFilter,
-				},
+	if filter.Genre != "" {
+		f["search.genre"] = utils.FoldDiacritics(strings.TrimSpace(filter.Genre))
+	}
+	if filter.Artist != "" {
+		f["search.artist"] = utils.FoldDiacritics(strings.TrimSpace(filter.Artist))
+	}
+	if filter.Tag != "" {
+		f["tags"] = strings.ToLower(strings.TrimSpace(filter.Tag))
+	}
+	if filter.Uploader != "" {
+		if objID, err := primitive.ObjectIDFromHex(filter.Uploader); err == nil {
+			if _, taken := f["user_id"]; taken {
+				// Combined with myTracks
+				f["$and"] = []bson.M{{"user_id": objID}}
+			} else {
+				f["user_id"] = objID
 			}
 		}
 	}
+	if year := intRange(filter.YearFrom, filter.YearTo); year != nil {
+		f["release_year"] = year
+	}
+	if duration := intRange(filter.DurationMin, filter.DurationMax); duration != nil {
+		f["duration"] = duration
+	}
+	return f
+}
 
-	return mgm.Coll(&models.Track{}).CountDocuments(context.Background(), filter)
+// intRange builds an inclusive range condition; 0 means unbounded.
+func intRange(from, to int) bson.M {
+	if from <= 0 && to <= 0 {
+		return nil
+	}
+	cond := bson.M{}
+	if from > 0 {
+		cond["$gte"] = from
+	}
+	if to > 0 {
+		cond["$lte"] = to
+	}
+	return cond
+}
+
+func exactMatchRegex(s string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(strings.TrimSpace(s)) + "$", Options: "i"}
 }
 
+func intersectStrings(a, b []string) []string {
+	out := []string{}
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				out = append(out, x)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// GetTracksByIDs returns the tracks in the order of ids, repeating a track
+// each time its ID repeats. IDs of deleted tracks are skipped.
 func (r *trackRepository) GetTracksByIDs(ids []primitive.ObjectID) ([]*models.Track, error) {
 	if len(ids) == 0 {
 		return []*models.Track{}, nil
@@ -176,19 +455,150 @@ func (r *trackRepository) GetTracksByIDs(ids []primitive.ObjectID) ([]*models.Tr
 	}
 	defer cursor.Close(context.Background())
 
-	var tracks []*models.Track
+	byID := make(map[primitive.ObjectID]*models.Track, len(ids))
 	for cursor.Next(context.Background()) {
 		var t models.Track
 		if err := cursor.Decode(&t); err != nil {
 			return nil, err
 		}
-		tracks = append(tracks, &t)
+		byID[t.ID] = &t
 	}
 
 	if err := cursor.Err(); err != nil {
 		return nil, err
 	}
 
+	// $in returns documents in storage order, not in the order asked for
+	tracks := make([]*models.Track, 0, len(byID))
+	for _, id := range ids {
+		if t, ok := byID[id]; ok {
+			tracks = append(tracks, t)
+		}
+	}
+	return tracks, nil
+}
+
+// FindTracksByFileNames finds tracks by the name of their uploaded mp3, or by
+// a title equal to it (titles default to the file name). The result is keyed
+// by the accent-folded name.
+func (r *trackRepository) FindTracksByFileNames(names []string) (map[string][]*models.Track, error) {
+	result := map[string][]*models.Track{}
+	if len(names) == 0 {
+		return result, nil
+	}
+	ctx := context.Background()
+
+	folded := make([]string, len(names))
+	for i, name := range names {
+		folded[i] = utils.FoldDiacritics(name)
+	}
+
+	// GridFS keeps the original file name; strength 2 compares case-insensitively
+	files, err := r.Collection.Database().Collection("fs.files").Find(ctx,
+		bson.M{"filename": bson.M{"$in": names}},
+		options.Find().
+			SetProjection(bson.M{"filename": 1}).
+			SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var fileDocs []struct {
+		ID       primitive.ObjectID `bson:"_id"`
+		Filename string             `bson:"filename"`
+	}
+	if err := files.All(ctx, &fileDocs); err != nil {
+		return nil, err
+	}
+	fileNames := make(map[primitive.ObjectID]string, len(fileDocs))
+	fileIDs := make([]primitive.ObjectID, len(fileDocs))
+	for i, f := range fileDocs {
+		fileNames[f.ID] = utils.FoldDiacritics(f.Filename)
+		fileIDs[i] = f.ID
+	}
+
+	cursor, err := r.Collection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"file_id": bson.M{"$in": fileIDs}},
+		bson.M{"search.title": bson.M{"$in": folded}},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	var tracks []*models.Track
+	if err := cursor.All(ctx, &tracks); err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(folded))
+	for _, name := range folded {
+		wanted[name] = true
+	}
+	for _, t := range tracks {
+		keys := []string{}
+		if name, ok := fileNames[t.FileID]; ok {
+			keys = append(keys, name)
+		}
+		if wanted[t.Search.Title] && (len(keys) == 0 || keys[0] != t.Search.Title) {
+			keys = append(keys, t.Search.Title)
+		}
+		for _, key := range keys {
+			result[key] = append(result[key], t)
+		}
+	}
+	return result, nil
+}
+
+// FindTracksByTitles finds tracks whose accent-folded title is one of titles,
+// with or without a ".mp3" extension.
+func (r *trackRepository) FindTracksByTitles(titles []string) ([]*models.Track, error) {
+	tracks := []*models.Track{}
+	if len(titles) == 0 {
+		return tracks, nil
+	}
+
+	folded := make([]string, 0, len(titles)*2)
+	for _, title := range titles {
+		title = utils.FoldDiacritics(title)
+		folded = append(folded, title, title+".mp3")
+	}
+
+	cursor, err := r.Collection.Find(context.Background(), bson.M{"search.title": bson.M{"$in": folded}})
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// FindSmartTracks evaluates the rules of a smart playlist.
+func (r *trackRepository) FindSmartTracks(smart *models.SmartPlaylist, ctx SmartContext) ([]*models.Track, error) {
+	filter, err := CompileSmartRules(smart.Rules, ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := SmartSortBSON(smart)
+	if err != nil {
+		return nil, err
+	}
+	limit := smart.Limit
+	if limit <= 0 || limit > models.SmartMaxLimit {
+		limit = models.SmartMaxLimit
+	}
+
+	opts := options.Find().
+		SetSort(sort).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"lyrics_text": 0, "lyrics_folded": 0})
+	cursor, err := r.Collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	tracks := []*models.Track{}
+	if err := cursor.All(context.Background(), &tracks); err != nil {
+		return nil, err
+	}
 	return tracks, nil
 }
 
@@ -223,3 +633,279 @@ func (r *trackRepository) ExistAllByIDs(ids []primitive.ObjectID) (bool, error)
 	}
 	return count == int64(len(ids)), nil
 }
+
+// GetTracksByUser returns every track of an uploader, newest release first,
+// then in album order.
+func (r *trackRepository) GetTracksByUser(userID primitive.ObjectID) ([]*models.Track, error) {
+	tracks := []*models.Track{}
+	opts := options.Find().SetSort(bson.D{
+		{Key: "release_year", Value: -1},
+		{Key: "album", Value: 1},
+		{Key: "disc_number", Value: 1},
+		{Key: "track_number", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+
+	cursor, err := mgm.Coll(&models.Track{}).Find(context.Background(), bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (r *trackRepository) GetUserTrackStats(userID primitive.ObjectID) (*TrackStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            nil,
+			"track_count":    bson.M{"$sum": 1},
+			"total_duration": bson.M{"$sum": "$duration"},
+			"play_count":     bson.M{"$sum": "$play_count"},
+		}}},
+	}
+
+	cursor, err := r.Collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	stats := &TrackStats{}
+	if cursor.Next(context.Background()) {
+		if err := cursor.Decode(stats); err != nil {
+			return nil, err
+		}
+	}
+	return stats, cursor.Err()
+}
+
+func (r *trackRepository) GetTracksByGenreIDs(genreIDs []primitive.ObjectID, page, limit int) ([]*models.Track, error) {
+	tracks := []*models.Track{}
+	skip := int64((page - 1) * limit)
+	opts := options.Find().SetSkip(skip).SetLimit(int64(limit))
+
+	filter := bson.M{"genre_id": bson.M{"$in": genreIDs}}
+	cursor, err := mgm.Coll(&models.Track{}).Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(context.Background(), &tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (r *trackRepository) CountTracksByGenreIDs(genreIDs []primitive.ObjectID) (int64, error) {
+	return mgm.Coll(&models.Track{}).CountDocuments(context.Background(), bson.M{"genre_id": bson.M{"$in": genreIDs}})
+}
+
+// ReassignGenre re-points every track of the given genres to toID, also
+// rewriting the free-text genre name. Used for merges and renames.
+func (r *trackRepository) ReassignGenre(fromIDs []primitive.ObjectID, toID primitive.ObjectID, name string) (int64, error) {
+	res, err := r.Collection.UpdateMany(
+		context.Background(),
+		bson.M{"genre_id": bson.M{"$in": fromIDs}},
+		bson.M{"$set": bson.M{"genre_id": toID, "genre": name, "search.genre": utils.FoldDiacritics(name)}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// LinkUnassignedGenre links tracks that only have a free-text genre matching
+// one of names (ignoring case and accents) to the given genre.
+func (r *trackRepository) LinkUnassignedGenre(names []string, genreID primitive.ObjectID, name string) (int64, error) {
+	if len(names) == 0 {
+		return 0, nil
+	}
+
+	opts := options.Update().SetCollation(&options.Collation{Locale: "vi", Strength: 1})
+	res, err := r.Collection.UpdateMany(
+		context.Background(),
+		bson.M{
+			"genre_id": nil,
+			"genre":    bson.M{"$in": names},
+		},
+		bson.M{"$set": bson.M{"genre_id": genreID, "genre": name, "search.genre": utils.FoldDiacritics(name)}},
+		opts,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// SetLyricsText refreshes the denormalized lyrics used by search.
+func (r *trackRepository) SetLyricsText(id primitive.ObjectID, text string) error {
+	_, err := r.Collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"lyrics_text": text, "lyrics_folded": utils.FoldDiacritics(text)}},
+	)
+	return err
+}
+
+// IncrementPlayCount counts one more play of a track and returns the new
+// count. It is a single atomic update, so concurrent plays are all counted.
+func (r *trackRepository) IncrementPlayCount(id primitive.ObjectID) (int64, error) {
+	var track struct {
+		PlayCount int64 `bson:"play_count"`
+	}
+	err := r.Collection.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"play_count": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"play_count": 1}),
+	).Decode(&track)
+	return track.PlayCount, err
+}
+
+// IncrementLikeCount adds delta to the like count of a track and returns the
+// new count.
+func (r *trackRepository) IncrementLikeCount(id primitive.ObjectID, delta int64) (int64, error) {
+	var track struct {
+		LikeCount int64 `bson:"like_count"`
+	}
+	err := r.Collection.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"like_count": delta}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"like_count": 1}),
+	).Decode(&track)
+	return track.LikeCount, err
+}
+
+// GetPopularTracks returns the most played tracks matching filter, then the
+// most liked among equally played ones.
+func (r *trackRepository) GetPopularTracks(filter models.TrackFilter, limit int) ([]*models.Track, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "play_count", Value: -1}, {Key: "like_count", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"lyrics_text": 0, "lyrics_folded": 0})
+	cursor, err := r.Collection.Find(context.Background(), trackFilterBSON(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	tracks := []*models.Track{}
+	if err := cursor.All(context.Background(), &tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// GetTrackFeatures returns every track with only the fields recommendations
+// compare tracks on: artist, genre, tags and popularity.
+func (r *trackRepository) GetTrackFeatures() ([]*models.Track, error) {
+	opts := options.Find().SetProjection(bson.M{
+		"user_id":       1,
+		"search.artist": 1,
+		"search.genre":  1,
+		"genre_id":      1,
+		"tags":          1,
+		"play_count":    1,
+		"like_count":    1,
+	})
+	cursor, err := r.Collection.Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	tracks := []*models.Track{}
+	if err := cursor.All(context.Background(), &tracks); err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// BackfillSearchFields fills the accent-folded fields of tracks saved before
+// they existed. Returns the number of tracks updated.
+func (r *trackRepository) BackfillSearchFields() (int64, error) {
+	ctx := context.Background()
+	filter := bson.M{"$or": []bson.M{
+		{"search": bson.M{"$exists": false}},
+		{"lyrics_text": bson.M{"$exists": true}, "lyrics_folded": bson.M{"$exists": false}},
+	}}
+
+	cursor, err := r.Collection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var updated int64
+	for cursor.Next(ctx) {
+		var doc struct {
+			models.Track `bson:",inline"`
+			LyricsText   string `bson:"lyrics_text"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return updated, err
+		}
+
+		doc.Track.Saving()
+		_, err := r.Collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{
+			"search":        doc.Search,
+			"lyrics_folded": utils.FoldDiacritics(doc.LyricsText),
+		}})
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, cursor.Err()
+}
+
+// BackfillPlayCounts sets play_count to 0 on tracks saved before the counter
+// existed. Range filters and sorts on play_count skip or misplace documents
+// missing the field. Returns the number of tracks updated.
+func (r *trackRepository) BackfillPlayCounts() (int64, error) {
+	res, err := r.Collection.UpdateMany(context.Background(),
+		bson.M{"play_count": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"play_count": 0}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// EnsureSearchIndex creates the weighted text index used by SearchTracks,
+// replacing an older definition if the weights changed.
+func (r *trackRepository) EnsureSearchIndex() error {
+	keys := bson.D{}
+	weights := bson.D{}
+	for _, w := range trackSearchWeights {
+		keys = append(keys, bson.E{Key: w.Key, Value: "text"})
+		weights = append(weights, w)
+	}
+
+	model := mongo.IndexModel{
+		Keys: keys,
+		Options: options.Index().
+			SetName(trackSearchIndex).
+			SetWeights(weights).
+			// No stemming: most titles are Vietnamese, which MongoDB has no stemmer for
+			SetDefaultLanguage("none").
+			SetLanguageOverride("text_language"),
+	}
+
+	ctx := context.Background()
+	_, err := r.Collection.Indexes().CreateOne(ctx, model)
+	if err == nil {
+		return nil
+	}
+
+	// A collection has at most one text index; drop it and retry once
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) || (cmdErr.Name != "IndexOptionsConflict" && cmdErr.Name != "IndexKeySpecsConflict") {
+		return err
+	}
+	if _, err := r.Collection.Indexes().DropOne(ctx, trackSearchIndex); err != nil {
+		return err
+	}
+	_, err = r.Collection.Indexes().CreateOne(ctx, model)
+	return err
+}
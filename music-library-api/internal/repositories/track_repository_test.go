@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"music-library-api/internal/models"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTrackUpdateDoc(t *testing.T) {
+	track := &models.Track{Title: "Nơi Này Có Anh", PlayCount: 41, LikeCount: 7}
+	track.ID = primitive.NewObjectID()
+	if err := track.Saving(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := trackUpdateDoc(track)
+	if err != nil {
+		t.Fatalf("trackUpdateDoc() error = %v", err)
+	}
+
+	for _, field := range trackCounters {
+		if _, ok := doc[field]; ok {
+			t.Errorf("%s is set, a stale value would overwrite counted increments", field)
+		}
+	}
+	if _, ok := doc["_id"]; ok {
+		t.Errorf("_id is set")
+	}
+	for _, field := range []string{"title", "search", "updated_at", "album_id"} {
+		if _, ok := doc[field]; !ok {
+			t.Errorf("%s is missing from the update", field)
+		}
+	}
+	if doc["title"] != track.Title {
+		t.Errorf("title = %v, want %q", doc["title"], track.Title)
+	}
+}
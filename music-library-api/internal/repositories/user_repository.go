@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"music-library-api/internal/models"
+	"music-library-api/pkg/utils"
 
 	"github.com/kamva/mgm/v3"
 	"go.mongodb.org/mongo-driver/bson"
@@ -17,6 +18,7 @@ type IUserRepository interface {
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByID(id string) (*models.User, error)
 	GetAllUsers(page, limit int) ([]*models.User, error)
+	GetUsersByCursor(cursor *utils.Cursor, limit int) ([]*models.User, utils.CursorPage, error)
 	CountUsers() (int64, error)
 	UpdateUser(user *models.User) error
 	SaveRefreshToken(userID, token string) error
@@ -62,7 +64,7 @@ func (r *userRepository) GetUserByID(id string) (*models.User, error) {
 func (r *userRepository) GetAllUsers(page, limit int) ([]*models.User, error) {
 	users := []*models.User{}
 	skip := int64((page - 1) * limit)
-	opts := options.Find().SetSkip(skip).SetLimit(int64(limit))
+	opts := options.Find().SetSort(stableSort).SetSkip(skip).SetLimit(int64(limit))
 
 	cursor, err := mgm.Coll(&models.User{}).Find(context.Background(), bson.M{}, opts)
 	if err != nil {
@@ -74,6 +76,11 @@ func (r *userRepository) GetAllUsers(page, limit int) ([]*models.User, error) {
 	return users, nil
 }
 
+// GetUsersByCursor is the keyset-paginated variant of GetAllUsers.
+func (r *userRepository) GetUsersByCursor(cursor *utils.Cursor, limit int) ([]*models.User, utils.CursorPage, error) {
+	return findByCursor[*models.User](r.Collection, bson.M{}, cursor, limit)
+}
+
 func (r *userRepository) CountUsers() (int64, error) {
 	return mgm.Coll(&models.User{}).CountDocuments(context.Background(), bson.M{})
 }
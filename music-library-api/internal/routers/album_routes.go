@@ -0,0 +1,24 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+	"music-library-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAlbumRoutes(rg *gin.RouterGroup, handler *handlers.AlbumHandler, cfg *configs.Config) {
+	albums := rg.Group("/albums")
+	{
+		albums.GET("/:id", handler.GetAlbumByID)
+
+		protected := albums.Group("")
+		protected.Use(middlewares.AuthMiddleware(cfg))
+		protected.Use(middlewares.RequireRoles(models.RoleAdmin, models.RoleArtist))
+		{
+			protected.PATCH("/:id", handler.UpdateAlbum)
+		}
+	}
+}
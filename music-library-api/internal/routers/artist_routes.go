@@ -0,0 +1,25 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+	"music-library-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterArtistRoutes(rg *gin.RouterGroup, handler *handlers.ArtistHandler, cfg *configs.Config) {
+	artists := rg.Group("/artists")
+	{
+		artists.GET("/:id", handler.GetArtist)
+
+		protected := artists.Group("")
+		protected.Use(middlewares.AuthMiddleware(cfg))
+		protected.Use(middlewares.RequireRoles(models.RoleAdmin, models.RoleArtist))
+		{
+			protected.PATCH("/me", handler.UpdateMyArtistProfile)
+			protected.PATCH("/:id", handler.UpdateArtistProfile)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+	"music-library-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterGenreRoutes(rg *gin.RouterGroup, handler *handlers.GenreHandler, cfg *configs.Config) {
+	genres := rg.Group("/genres")
+	{
+		genres.GET("", handler.GetGenres)
+		genres.GET("/:slug", handler.GetGenre)
+		genres.GET("/:slug/tracks", handler.GetGenreTracks)
+
+		// Admin only
+		adminOnly := genres.Group("")
+		adminOnly.Use(middlewares.AuthMiddleware(cfg))
+		adminOnly.Use(middlewares.RequireRoles(models.RoleAdmin))
+		{
+			adminOnly.POST("", handler.CreateGenre)
+			adminOnly.PATCH("/:slug", handler.UpdateGenre)
+			adminOnly.POST("/:slug/merge", handler.MergeGenre)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterLikeRoutes(rg *gin.RouterGroup, handler *handlers.LikeHandler, cfg *configs.Config) {
+	auth := middlewares.AuthMiddleware(cfg)
+
+	rg.POST("/tracks/:id/like", auth, handler.LikeTrack)
+	rg.DELETE("/tracks/:id/like", auth, handler.UnlikeTrack)
+	rg.POST("/albums/:id/like", auth, handler.LikeAlbum)
+	rg.DELETE("/albums/:id/like", auth, handler.UnlikeAlbum)
+	rg.POST("/artists/:id/like", auth, handler.LikeArtist)
+	rg.DELETE("/artists/:id/like", auth, handler.UnlikeArtist)
+	rg.POST("/playlists/:id/like", auth, handler.LikePlaylist)
+	rg.DELETE("/playlists/:id/like", auth, handler.UnlikePlaylist)
+
+	me := rg.Group("/users/me")
+	me.Use(auth)
+	{
+		me.GET("/likes", handler.GetMyLikes)
+	}
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+	"music-library-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterLyricsRoutes(rg *gin.RouterGroup, handler *handlers.LyricsHandler, cfg *configs.Config) {
+	lyrics := rg.Group("/tracks/:id/lyrics")
+	{
+		lyrics.GET("", handler.GetLyrics)
+
+		protected := lyrics.Group("")
+		protected.Use(middlewares.AuthMiddleware(cfg))
+		protected.Use(middlewares.RequireRoles(models.RoleAdmin, models.RoleArtist))
+		{
+			protected.PUT("", handler.UpsertLyrics)
+			protected.DELETE("", handler.DeleteLyrics)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPlayQueueRoutes(rg *gin.RouterGroup, handler *handlers.PlayQueueHandler, cfg *configs.Config) {
+	queue := rg.Group("/users/me/queue")
+
+	// EventSource cannot send headers, so this one also takes ?access_token=
+	queue.GET("/events", middlewares.StreamAuthMiddleware(cfg), handler.QueueEvents)
+
+	queue.Use(middlewares.AuthMiddleware(cfg))
+	{
+		queue.GET("", handler.GetQueue)
+		queue.PUT("", handler.BuildQueue)
+		queue.DELETE("", handler.ClearQueue)
+		queue.PATCH("/playback", handler.UpdatePlayback)
+		queue.POST("/items", handler.AddToQueue)
+		queue.DELETE("/items/:itemId", handler.RemoveFromQueue)
+		queue.POST("/next", handler.Next)
+		queue.POST("/previous", handler.Previous)
+	}
+}
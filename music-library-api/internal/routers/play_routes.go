@@ -0,0 +1,23 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPlayRoutes(rg *gin.RouterGroup, handler *handlers.PlayHandler, cfg *configs.Config) {
+	plays := rg.Group("/plays")
+	plays.Use(middlewares.AuthMiddleware(cfg))
+	{
+		plays.POST("", handler.Scrobble)
+	}
+
+	me := rg.Group("/users/me")
+	me.Use(middlewares.AuthMiddleware(cfg))
+	{
+		me.GET("/history", handler.GetHistory)
+	}
+}
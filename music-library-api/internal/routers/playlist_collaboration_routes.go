@@ -0,0 +1,25 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPlaylistCollaborationRoutes(rg *gin.RouterGroup, handler *handlers.PlaylistCollaborationHandler, cfg *configs.Config) {
+	playlists := rg.Group("/playlists")
+	playlists.Use(middlewares.AuthMiddleware(cfg))
+	{
+		playlists.GET("/invitations", handler.GetMyInvitations)
+		playlists.POST("/invitations/:invitationId/accept", handler.AcceptInvitation)
+		playlists.POST("/invitations/:invitationId/decline", handler.DeclineInvitation)
+
+		playlists.POST("/:id/invitations", handler.InviteCollaborator)
+		playlists.GET("/:id/invitations", handler.GetPlaylistInvitations)
+		playlists.DELETE("/:id/invitations/:invitationId", handler.RevokeInvitation)
+		playlists.PATCH("/:id/collaborators/:userId", handler.UpdateCollaborator)
+		playlists.DELETE("/:id/collaborators/:userId", handler.RemoveCollaborator)
+	}
+}
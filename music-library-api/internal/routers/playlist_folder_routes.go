@@ -0,0 +1,22 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPlaylistFolderRoutes(rg *gin.RouterGroup, handler *handlers.PlaylistFolderHandler, cfg *configs.Config) {
+	playlists := rg.Group("/playlists")
+	playlists.Use(middlewares.AuthMiddleware(cfg))
+	{
+		playlists.POST("/folders", handler.CreateFolder)
+		playlists.GET("/folders/tree", handler.GetTree)
+		playlists.PATCH("/folders/:folderId", handler.RenameFolder)
+		playlists.POST("/folders/:folderId/move", handler.MoveFolder)
+		playlists.DELETE("/folders/:folderId", handler.DeleteFolder)
+		playlists.PUT("/:id/folder", handler.MovePlaylist)
+	}
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPlaylistFollowRoutes(rg *gin.RouterGroup, handler *handlers.PlaylistFollowHandler, cfg *configs.Config) {
+	playlists := rg.Group("/playlists")
+	playlists.Use(middlewares.AuthMiddleware(cfg))
+	{
+		playlists.GET("/followed", handler.GetFollowedPlaylists)
+		playlists.POST("/:id/follow", handler.FollowPlaylist)
+		playlists.DELETE("/:id/follow", handler.UnfollowPlaylist)
+	}
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPlaylistImportRoutes(rg *gin.RouterGroup, handler *handlers.PlaylistImportHandler, cfg *configs.Config) {
+	imports := rg.Group("/playlists")
+	imports.Use(middlewares.AuthMiddleware(cfg))
+	{
+		imports.POST("/import", handler.ImportPlaylist)
+		imports.GET("/imports/:importId", handler.GetImport)
+		imports.POST("/imports/:importId/resolve", handler.ResolveImport)
+	}
+}
@@ -11,18 +11,35 @@ import (
 func RegisterPlaylistRoutes(rg *gin.RouterGroup, handler *handlers.PlaylistHandler, cfg *configs.Config) {
 	playlists := rg.Group("/playlists")
 	{
-		// Public routes (with optional auth for user-scoped filtering)
+		// Public routes (with optional auth for user-scoped filtering and private playlists)
 		playlists.GET("", middlewares.OptionalAuthMiddleware(cfg), handler.GetPlaylists)
-		playlists.GET("/:id", handler.GetPlaylistByID)
-		playlists.GET("/:id/stream", handler.StreamPlaylistM3U)
+		playlists.GET("/:id", middlewares.OptionalAuthMiddleware(cfg), handler.GetPlaylistByID)
+		playlists.GET("/:id/stream", middlewares.OptionalAuthMiddleware(cfg), handler.StreamPlaylistM3U)
+		playlists.GET("/:id/export/:filename", middlewares.OptionalAuthMiddleware(cfg), handler.ExportPlaylist)
 
 		// Protected routes (require auth)
 		protected := playlists.Group("")
 		protected.Use(middlewares.AuthMiddleware(cfg))
 		{
 			protected.POST("", handler.CreatePlaylist)
+			protected.POST("/smart", handler.CreateSmartPlaylist)
+			protected.GET("/liked", handler.GetLikedSongs)
+			protected.PUT("/:id/smart", handler.UpdateSmartPlaylist)
 			protected.PATCH("/:id", handler.UpdatePlaylist)
 			protected.DELETE("/:id", handler.DeletePlaylist)
+
+			protected.POST("/:id/entries", handler.InsertEntries)
+			protected.POST("/:id/entries/reverse", handler.ReverseEntries)
+			protected.POST("/:id/entries/sort", handler.SortEntries)
+			protected.PATCH("/:id/entries/:entryId", handler.MoveEntry)
+			protected.DELETE("/:id/entries/:entryId", handler.RemoveEntry)
+
+			protected.POST("/:id/fork", handler.ForkPlaylist)
+			protected.POST("/:id/pull", handler.PullFork)
+
+			protected.POST("/:id/shares", handler.CreateShareLink)
+			protected.GET("/:id/shares", handler.GetShareLinks)
+			protected.DELETE("/:id/shares/:shareId", handler.RevokeShareLink)
 		}
 	}
 }
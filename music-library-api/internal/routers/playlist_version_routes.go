@@ -0,0 +1,21 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPlaylistVersionRoutes(rg *gin.RouterGroup, handler *handlers.PlaylistVersionHandler, cfg *configs.Config) {
+	playlists := rg.Group("/playlists")
+	{
+		// Readable by anyone who can see the playlist
+		playlists.GET("/:id/versions", middlewares.OptionalAuthMiddleware(cfg), handler.GetVersions)
+		playlists.GET("/:id/versions/diff", middlewares.OptionalAuthMiddleware(cfg), handler.DiffVersions)
+		playlists.GET("/:id/versions/:version", middlewares.OptionalAuthMiddleware(cfg), handler.GetVersion)
+
+		playlists.POST("/:id/versions/:version/restore", middlewares.AuthMiddleware(cfg), handler.RestoreVersion)
+	}
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+	"music-library-api/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRecommendationRoutes(rg *gin.RouterGroup, handler *handlers.RecommendationHandler, cfg *configs.Config) {
+	rg.GET("/tracks/:id/similar", handler.GetSimilarTracks)
+
+	me := rg.Group("/users/me")
+	me.Use(middlewares.AuthMiddleware(cfg))
+	{
+		me.GET("/recommendations", handler.GetMyRecommendations)
+	}
+}
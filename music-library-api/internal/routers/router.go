@@ -1,21 +1,42 @@
 package router
 
 import (
+	"log"
 	configs "music-library-api/configs"
 	"music-library-api/internal/handlers"
 	"music-library-api/internal/middlewares"
+	"music-library-api/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 func NewRouter(
 	cfg *configs.Config,
+	trustedProxies utils.TrustedProxies,
 	authHandler *handlers.AuthHandler,
 	userHandler *handlers.UserHandler,
 	trackHandler *handlers.TrackHandler,
 	playlistHandler *handlers.PlaylistHandler,
+	albumHandler *handlers.AlbumHandler,
+	artistHandler *handlers.ArtistHandler,
+	genreHandler *handlers.GenreHandler,
+	lyricsHandler *handlers.LyricsHandler,
+	searchHandler *handlers.SearchHandler,
+	playlistImportHandler *handlers.PlaylistImportHandler,
+	playlistCollaborationHandler *handlers.PlaylistCollaborationHandler,
+	playlistFollowHandler *handlers.PlaylistFollowHandler,
+	playlistVersionHandler *handlers.PlaylistVersionHandler,
+	playlistFolderHandler *handlers.PlaylistFolderHandler,
+	playQueueHandler *handlers.PlayQueueHandler,
+	playHandler *handlers.PlayHandler,
+	likeHandler *handlers.LikeHandler,
+	recommendationHandler *handlers.RecommendationHandler,
 ) *gin.Engine {
 	r := gin.Default()
+	// gin trusts every proxy by default, which lets any client spoof ClientIP
+	if err := r.SetTrustedProxies(trustedProxies.Strings()); err != nil {
+		log.Printf("Failed to set trusted proxies: %v", err)
+	}
 	r.Use(middlewares.CORSMiddleware())
 
 	api := r.Group("/api")
@@ -24,6 +45,20 @@ func NewRouter(
 	RegisterUserRoutes(api, userHandler, cfg)
 	RegisterTrackRoutes(api, trackHandler, cfg)
 	RegisterPlaylistRoutes(api, playlistHandler, cfg)
+	RegisterPlaylistImportRoutes(api, playlistImportHandler, cfg)
+	RegisterPlaylistCollaborationRoutes(api, playlistCollaborationHandler, cfg)
+	RegisterPlaylistFollowRoutes(api, playlistFollowHandler, cfg)
+	RegisterPlaylistVersionRoutes(api, playlistVersionHandler, cfg)
+	RegisterPlaylistFolderRoutes(api, playlistFolderHandler, cfg)
+	RegisterPlayQueueRoutes(api, playQueueHandler, cfg)
+	RegisterPlayRoutes(api, playHandler, cfg)
+	RegisterLikeRoutes(api, likeHandler, cfg)
+	RegisterRecommendationRoutes(api, recommendationHandler, cfg)
+	RegisterAlbumRoutes(api, albumHandler, cfg)
+	RegisterArtistRoutes(api, artistHandler, cfg)
+	RegisterGenreRoutes(api, genreHandler, cfg)
+	RegisterLyricsRoutes(api, lyricsHandler, cfg)
+	RegisterSearchRoutes(api, searchHandler, cfg)
 
 	return r
 }
@@ -0,0 +1,15 @@
+package router
+
+import (
+	configs "music-library-api/configs"
+	"music-library-api/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSearchRoutes(rg *gin.RouterGroup, handler *handlers.SearchHandler, cfg *configs.Config) {
+	search := rg.Group("/search")
+	{
+		search.GET("/suggest", handler.Suggest)
+	}
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IAlbumService interface {
+	GetAlbumByID(id string) (*models.Album, error)
+	GetAlbumResponse(id string) (*dto.AlbumResponse, error)
+	UpdateAlbum(id string, req *dto.UpdateAlbumRequest) (*dto.AlbumResponse, error)
+	AttachTrack(track *models.Track) error
+	DetachTrack(track *models.Track) error
+	SetArtwork(track *models.Track, data []byte) error
+}
+
+type AlbumService struct {
+	repo         repositories.IAlbumRepository
+	likeRepo     repositories.ILikeRepository
+	trackService ITrackService
+	uploader     utils.ImageUploader
+}
+
+func NewAlbumService(repo repositories.IAlbumRepository, likeRepo repositories.ILikeRepository, trackService ITrackService, uploader utils.ImageUploader) IAlbumService {
+	return &AlbumService{
+		repo:         repo,
+		likeRepo:     likeRepo,
+		trackService: trackService,
+		uploader:     uploader,
+	}
+}
+
+func (s *AlbumService) GetAlbumByID(id string) (*models.Album, error) {
+	return s.repo.GetAlbumByID(id)
+}
+
+// GetAlbumResponse returns the album with its full ordered tracklist and total duration.
+func (s *AlbumService) GetAlbumResponse(id string) (*dto.AlbumResponse, error) {
+	album, err := s.repo.GetAlbumByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildResponse(album)
+}
+
+func (s *AlbumService) UpdateAlbum(id string, req *dto.UpdateAlbumRequest) (*dto.AlbumResponse, error) {
+	album, err := s.repo.GetAlbumByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := req.Title != "" && strings.TrimSpace(req.Title) != album.Title
+	if req.Title != "" {
+		album.Title = strings.TrimSpace(req.Title)
+	}
+	if req.Artist != "" {
+		album.Artist = strings.TrimSpace(req.Artist)
+	}
+	if req.ReleaseYear != 0 {
+		album.ReleaseYear = req.ReleaseYear
+	}
+	for _, d := range req.Discs {
+		setDiscSubtitle(album, d.Number, strings.TrimSpace(d.Subtitle))
+	}
+
+	if err := s.repo.UpdateAlbum(album); err != nil {
+		return nil, err
+	}
+
+	// Keep the free-text album name on tracks in sync with the album document
+	if renamed {
+		if err := s.renameTracks(album); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.buildResponse(album)
+}
+
+// AttachTrack files a track into the album named by track.Album, creating the
+// album on first use. The tracklist is kept sorted by disc then track number.
+func (s *AlbumService) AttachTrack(track *models.Track) error {
+	title := strings.TrimSpace(track.Album)
+
+	var album *models.Album
+
+	// Track moved to another album (or left it): detach it from the old one first
+	if track.AlbumID != nil {
+		current, err := s.repo.GetAlbumByID(track.AlbumID.Hex())
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("failed to load current album: %w", err)
+		}
+		if current != nil && title != "" &&
+			strings.EqualFold(current.Title, title) && strings.EqualFold(current.Artist, track.Artist) {
+			album = current
+		} else {
+			if err := s.DetachTrack(track); err != nil {
+				return fmt.Errorf("failed to detach track from album: %w", err)
+			}
+			track.AlbumID = nil
+		}
+	}
+
+	if title == "" {
+		return s.trackService.UpdateTrack(track)
+	}
+
+	if album == nil {
+		found, err := s.repo.FindAlbum(track.UserID, title, track.Artist)
+		if err != nil {
+			return err
+		}
+		album = found
+	}
+	if album == nil {
+		album = &models.Album{
+			UserID:      track.UserID,
+			Title:       title,
+			Artist:      track.Artist,
+			ReleaseYear: track.ReleaseYear,
+		}
+		if err := s.repo.CreateAlbum(album); err != nil {
+			return fmt.Errorf("failed to create album: %w", err)
+		}
+	}
+
+	upsertAlbumTrack(album, track)
+	if err := s.repo.UpdateAlbum(album); err != nil {
+		return fmt.Errorf("failed to update album tracklist: %w", err)
+	}
+
+	track.AlbumID = &album.ID
+	return s.trackService.UpdateTrack(track)
+}
+
+// DetachTrack removes a track from its album's tracklist, e.g. when it is
+// deleted or moved to another album.
+func (s *AlbumService) DetachTrack(track *models.Track) error {
+	if track.AlbumID == nil {
+		return nil
+	}
+	return s.repo.RemoveTrack(*track.AlbumID, track.ID)
+}
+
+// SetArtwork makes a picture embedded in an uploaded file (ID3 APIC) the cover
+// of the track's album, unless the album already has one.
+func (s *AlbumService) SetArtwork(track *models.Track, data []byte) error {
+	if track.AlbumID == nil || len(data) == 0 {
+		return nil
+	}
+	album, err := s.repo.GetAlbumByID(track.AlbumID.Hex())
+	if err != nil {
+		return err
+	}
+	if album.Cover != "" {
+		return nil
+	}
+
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("unsupported artwork: %w", err)
+	}
+	url, err := s.uploader.UploadImageData(data, album.ID.Hex(), "album_covers")
+	if err != nil {
+		return err
+	}
+	return s.repo.SetCover(album.ID, url)
+}
+
+func (s *AlbumService) renameTracks(album *models.Album) error {
+	ids := make([]primitive.ObjectID, len(album.Tracks))
+	for i, entry := range album.Tracks {
+		ids[i] = entry.TrackID
+	}
+
+	tracks, err := s.trackService.GetTracksByIDs(ids)
+	if err != nil {
+		return err
+	}
+	for _, t := range tracks {
+		t.Album = album.Title
+		if err := s.trackService.UpdateTrack(t); err != nil {
+			return fmt.Errorf("failed to rename album on track %s: %w", t.ID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+func (s *AlbumService) buildResponse(album *models.Album) (*dto.AlbumResponse, error) {
+	ids := make([]primitive.ObjectID, len(album.Tracks))
+	for i, entry := range album.Tracks {
+		ids[i] = entry.TrackID
+	}
+
+	tracks, err := s.trackService.GetTracksByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tracks for album %s: %w", album.ID.Hex(), err)
+	}
+
+	byID := make(map[primitive.ObjectID]*models.Track, len(tracks))
+	for _, t := range tracks {
+		byID[t.ID] = t
+	}
+
+	likes, err := s.likeRepo.CountLikes(models.LikeTargetAlbum, []primitive.ObjectID{album.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count likes of album %s: %w", album.ID.Hex(), err)
+	}
+
+	resp := mappers.ToAlbumResponse(album, byID)
+	resp.LikeCount = likes[album.ID]
+	return &resp, nil
+}
+
+func upsertAlbumTrack(album *models.Album, track *models.Track) {
+	disc := track.DiscNumber
+	if disc < 1 {
+		disc = 1
+	}
+
+	entry := models.AlbumTrack{
+		TrackID:     track.ID,
+		DiscNumber:  disc,
+		TrackNumber: track.TrackNumber,
+	}
+
+	replaced := false
+	for i := range album.Tracks {
+		if album.Tracks[i].TrackID == track.ID {
+			album.Tracks[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		album.Tracks = append(album.Tracks, entry)
+	}
+
+	if !hasDisc(album, disc) {
+		album.Discs = append(album.Discs, models.AlbumDisc{Number: disc})
+	}
+
+	sortAlbum(album)
+}
+
+// sortAlbum orders the tracklist by disc then track number.
+// Unnumbered tracks go to the end of their disc, keeping upload order.
+func sortAlbum(album *models.Album) {
+	sort.SliceStable(album.Tracks, func(i, j int) bool {
+		a, b := album.Tracks[i], album.Tracks[j]
+		if a.DiscNumber != b.DiscNumber {
+			return a.DiscNumber < b.DiscNumber
+		}
+		if a.TrackNumber == 0 || b.TrackNumber == 0 {
+			return a.TrackNumber != 0 && b.TrackNumber == 0
+		}
+		return a.TrackNumber < b.TrackNumber
+	})
+	sort.SliceStable(album.Discs, func(i, j int) bool {
+		return album.Discs[i].Number < album.Discs[j].Number
+	})
+}
+
+func hasDisc(album *models.Album, number int) bool {
+	for _, d := range album.Discs {
+		if d.Number == number {
+			return true
+		}
+	}
+	return false
+}
+
+func setDiscSubtitle(album *models.Album, number int, subtitle string) {
+	for i := range album.Discs {
+		if album.Discs[i].Number == number {
+			album.Discs[i].Subtitle = subtitle
+			return
+		}
+	}
+	album.Discs = append(album.Discs, models.AlbumDisc{Number: number, Subtitle: subtitle})
+	sortAlbum(album)
+}
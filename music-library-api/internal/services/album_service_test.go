@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeAlbumRepo struct {
+	repositories.IAlbumRepository
+	album *models.Album
+}
+
+func (r *fakeAlbumRepo) GetAlbumByID(id string) (*models.Album, error) {
+	return r.album, nil
+}
+
+func (r *fakeAlbumRepo) SetCover(albumID primitive.ObjectID, url string) error {
+	r.album.Cover = url
+	return nil
+}
+
+func TestAlbumSetArtwork(t *testing.T) {
+	artwork := pngBytes(t)
+
+	tests := []struct {
+		name      string
+		cover     string
+		data      []byte
+		uploadErr error
+		uploaded  bool
+		wantErr   bool
+	}{
+		{
+			name:     "fills a missing cover",
+			data:     artwork,
+			uploaded: true,
+		},
+		{
+			name:  "keeps an existing cover",
+			cover: "https://img.example/mine.jpg",
+			data:  artwork,
+		},
+		{
+			name: "no artwork",
+		},
+		{
+			name:    "not an image",
+			data:    []byte("definitely not a picture"),
+			wantErr: true,
+		},
+		{
+			name:      "upload failure",
+			data:      artwork,
+			uploadErr: errors.New("boom"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			album := &models.Album{Cover: tt.cover}
+			album.ID = primitive.NewObjectID()
+			track := &models.Track{AlbumID: &album.ID}
+			svc := NewAlbumService(&fakeAlbumRepo{album: album}, nil, nil, &fakeUploader{err: tt.uploadErr})
+
+			err := svc.SetArtwork(track, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetArtwork() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			want := tt.cover
+			if tt.uploaded {
+				want = "https://img.example/album_covers/" + album.ID.Hex() + ".jpg"
+			}
+			if album.Cover != want {
+				t.Errorf("Cover = %q, want %q", album.Cover, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
+	"net/url"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IArtistService interface {
+	GetArtistByID(id string) (*models.ArtistProfile, error)
+	GetArtistProfile(id string) (*dto.ArtistProfileResponse, error)
+	UpdateMyArtistProfile(userID string, req *dto.UpdateArtistProfileRequest) (*dto.ArtistProfileResponse, error)
+	UpdateArtistProfile(id string, req *dto.UpdateArtistProfileRequest) (*dto.ArtistProfileResponse, error)
+	ResolveArtistUserID(id string) (primitive.ObjectID, error)
+	EnsureIndexes() error
+}
+
+var (
+	ErrArtistNotFound    = errors.New("artist not found")
+	ErrInvalidArtistLink = errors.New("invalid link")
+)
+
+type ArtistService struct {
+	repo           repositories.IArtistRepository
+	userRepo       repositories.IUserRepository
+	likeRepo       repositories.ILikeRepository
+	trackService   ITrackService
+	CloudinaryUtil *utils.CloudinaryUtil
+}
+
+func NewArtistService(repo repositories.IArtistRepository, userRepo repositories.IUserRepository, likeRepo repositories.ILikeRepository, trackService ITrackService, cloudinaryUtil *utils.CloudinaryUtil) IArtistService {
+	return &ArtistService{
+		repo:           repo,
+		userRepo:       userRepo,
+		likeRepo:       likeRepo,
+		trackService:   trackService,
+		CloudinaryUtil: cloudinaryUtil,
+	}
+}
+
+func (s *ArtistService) GetArtistByID(id string) (*models.ArtistProfile, error) {
+	return s.repo.GetArtistByID(id)
+}
+
+// GetArtistProfile resolves id as an artist profile ID first, then as the ID of
+// the managing user. Artists who never saved a profile get one built from their account.
+func (s *ArtistService) GetArtistProfile(id string) (*dto.ArtistProfileResponse, error) {
+	profile, err := s.resolveProfile(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildResponse(profile)
+}
+
+// ResolveArtistUserID returns the user behind an artist, with id resolved like
+// in GetArtistProfile. Artists are identified by their user, since not all
+// of them have a profile.
+func (s *ArtistService) ResolveArtistUserID(id string) (primitive.ObjectID, error) {
+	profile, err := s.resolveProfile(id)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return profile.UserID, nil
+}
+
+func (s *ArtistService) EnsureIndexes() error {
+	return s.repo.EnsureIndexes()
+}
+
+func (s *ArtistService) resolveProfile(id string) (*models.ArtistProfile, error) {
+	profile, err := s.repo.GetArtistByID(id)
+	if isNotFound(err) {
+		return s.profileForUser(id)
+	}
+	return profile, err
+}
+
+func (s *ArtistService) UpdateMyArtistProfile(userID string, req *dto.UpdateArtistProfileRequest) (*dto.ArtistProfileResponse, error) {
+	profile, err := s.profileForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.saveProfile(profile, req)
+}
+
+func (s *ArtistService) UpdateArtistProfile(id string, req *dto.UpdateArtistProfileRequest) (*dto.ArtistProfileResponse, error) {
+	profile, err := s.repo.GetArtistByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.saveProfile(profile, req)
+}
+
+func (s *ArtistService) profileForUser(userID string) (*models.ArtistProfile, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if isNotFound(err) || (err == nil && user == nil) {
+		return nil, ErrArtistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != models.RoleArtist && user.Role != models.RoleAdmin {
+		return nil, ErrArtistNotFound
+	}
+
+	profile, err := s.repo.GetArtistByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &models.ArtistProfile{
+			UserID: user.ID,
+			Name:   user.Name,
+			Links:  []models.ArtistLink{},
+		}
+	}
+	return profile, nil
+}
+
+func (s *ArtistService) saveProfile(profile *models.ArtistProfile, req *dto.UpdateArtistProfileRequest) (*dto.ArtistProfileResponse, error) {
+	var links []models.ArtistLink
+	if len(req.Links) > 0 {
+		var err error
+		if links, err = parseArtistLinks(req.Links); err != nil {
+			return nil, err
+		}
+	}
+
+	var avatarURL string
+	if req.Avatar != nil {
+		var err error
+		if avatarURL, err = s.uploadAvatar(req.Avatar); err != nil {
+			return nil, fmt.Errorf("failed to upload avatar: %w", err)
+		}
+	}
+
+	apply := func(profile *models.ArtistProfile) {
+		if req.Name != "" {
+			profile.Name = strings.TrimSpace(req.Name)
+		}
+		if req.Bio != "" {
+			profile.Bio = strings.TrimSpace(req.Bio)
+		}
+		if links != nil {
+			profile.Links = links
+		}
+		if avatarURL != "" {
+			profile.Avatar = avatarURL
+		}
+	}
+	apply(profile)
+
+	var err error
+	if profile.ID.IsZero() {
+		err = s.repo.CreateArtist(profile)
+		if mongo.IsDuplicateKeyError(err) {
+			// A concurrent request created the profile first: update that one
+			existing, getErr := s.repo.GetArtistByUserID(profile.UserID)
+			if getErr != nil {
+				return nil, getErr
+			}
+			if existing == nil {
+				return nil, err
+			}
+			profile = existing
+			apply(profile)
+			err = s.repo.UpdateArtist(profile)
+		}
+	} else {
+		err = s.repo.UpdateArtist(profile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(profile)
+}
+
+func (s *ArtistService) buildResponse(profile *models.ArtistProfile) (*dto.ArtistProfileResponse, error) {
+	tracks, err := s.trackService.GetTracksByUser(profile.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve discography: %w", err)
+	}
+
+	stats, err := s.trackService.GetUserTrackStats(profile.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate artist statistics: %w", err)
+	}
+
+	likes, err := s.likeRepo.CountLikes(models.LikeTargetArtist, []primitive.ObjectID{profile.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count artist likes: %w", err)
+	}
+
+	resp := mappers.ToArtistProfileResponse(profile, tracks)
+	resp.Stats.TrackCount = stats.TrackCount
+	resp.Stats.TotalDuration = stats.TotalDuration
+	resp.Stats.PlayCount = stats.PlayCount
+	resp.Stats.LikeCount = likes[profile.UserID]
+	return &resp, nil
+}
+
+func (s *ArtistService) uploadAvatar(fileHeader *multipart.FileHeader) (string, error) {
+	if s.CloudinaryUtil == nil {
+		return "", fmt.Errorf("cloudinary util is not configured")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer file.Close()
+
+	return s.CloudinaryUtil.UploadImage(file, fileHeader, "artist_avatars")
+}
+
+// parseArtistLinks validates http(s) URLs and labels them by host, e.g. "youtube.com".
+func parseArtistLinks(raw []string) ([]models.ArtistLink, error) {
+	if strings.Contains(raw[0], ",") {
+		raw = strings.Split(raw[0], ",")
+	}
+
+	links := []models.ArtistLink{}
+	for _, link := range utils.UniqueStrings(raw) {
+		u, err := url.ParseRequestURI(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidArtistLink, link)
+		}
+		links = append(links, models.ArtistLink{
+			Label: strings.TrimPrefix(u.Hostname(), "www."),
+			URL:   u.String(),
+		})
+	}
+	return links, nil
+}
+
+// isNotFound reports whether a lookup by ID found nothing, including because
+// the ID is not a valid ObjectID.
+func isNotFound(err error) bool {
+	return errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex)
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type fakeArtistRepo struct {
+	repositories.IArtistRepository
+	byID     map[string]*models.ArtistProfile
+	err      error                 // returned by every lookup when set
+	raceWith *models.ArtistProfile // created by a concurrent request on CreateArtist
+	created  []*models.ArtistProfile
+	updated  []*models.ArtistProfile
+}
+
+func (r *fakeArtistRepo) GetArtistByID(id string) (*models.ArtistProfile, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if p := r.byID[id]; p != nil {
+		return p, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *fakeArtistRepo) GetArtistByUserID(userID primitive.ObjectID) (*models.ArtistProfile, error) {
+	if r.raceWith != nil && r.raceWith.UserID == userID {
+		return r.raceWith, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeArtistRepo) CreateArtist(artist *models.ArtistProfile) error {
+	if r.raceWith != nil {
+		return mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}}}
+	}
+	r.created = append(r.created, artist)
+	return nil
+}
+
+func (r *fakeArtistRepo) UpdateArtist(artist *models.ArtistProfile) error {
+	r.updated = append(r.updated, artist)
+	return nil
+}
+
+type fakeUserRepo struct {
+	repositories.IUserRepository
+	users map[string]*models.User
+	err   error
+}
+
+func (r *fakeUserRepo) GetUserByID(id string) (*models.User, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if u := r.users[id]; u != nil {
+		return u, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+type fakeLikeRepo struct {
+	repositories.ILikeRepository
+}
+
+func (r *fakeLikeRepo) CountLikes(targetType string, targetIDs []primitive.ObjectID) (map[primitive.ObjectID]int64, error) {
+	return map[primitive.ObjectID]int64{}, nil
+}
+
+func (s *fakeTrackService) GetTracksByUser(userID primitive.ObjectID) ([]*models.Track, error) {
+	return nil, nil
+}
+
+func (s *fakeTrackService) GetUserTrackStats(userID primitive.ObjectID) (*repositories.TrackStats, error) {
+	return &repositories.TrackStats{}, nil
+}
+
+func TestArtistServiceResolveProfile(t *testing.T) {
+	artist := &models.User{Name: "Đen", Role: models.RoleArtist}
+	artist.ID = primitive.NewObjectID()
+	listener := &models.User{Name: "Fan", Role: models.RoleUser}
+	listener.ID = primitive.NewObjectID()
+	profile := &models.ArtistProfile{UserID: artist.ID, Name: "Đen Vâu"}
+	profile.ID = primitive.NewObjectID()
+	dbDown := errors.New("connection refused")
+
+	tests := []struct {
+		name      string
+		id        string
+		artistErr error
+		userErr   error
+		wantUser  primitive.ObjectID
+		wantErr   error
+	}{
+		{name: "profile ID", id: profile.ID.Hex(), wantUser: artist.ID},
+		{name: "user ID without a saved profile", id: artist.ID.Hex(), wantUser: artist.ID},
+		{name: "not an artist", id: listener.ID.Hex(), wantErr: ErrArtistNotFound},
+		{name: "unknown ID", id: primitive.NewObjectID().Hex(), wantErr: ErrArtistNotFound},
+		{name: "invalid ID", id: "nope", wantErr: ErrArtistNotFound},
+		{name: "profile lookup fails", id: profile.ID.Hex(), artistErr: dbDown, wantErr: dbDown},
+		{name: "user lookup fails", id: artist.ID.Hex(), userErr: dbDown, wantErr: dbDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewArtistService(
+				&fakeArtistRepo{byID: map[string]*models.ArtistProfile{profile.ID.Hex(): profile}, err: tt.artistErr},
+				&fakeUserRepo{users: map[string]*models.User{artist.ID.Hex(): artist, listener.ID.Hex(): listener}, err: tt.userErr},
+				nil, nil, nil,
+			)
+
+			got, err := svc.ResolveArtistUserID(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveArtistUserID() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.wantUser {
+				t.Errorf("ResolveArtistUserID() = %s, want %s", got.Hex(), tt.wantUser.Hex())
+			}
+		})
+	}
+}
+
+func TestArtistServiceConcurrentCreate(t *testing.T) {
+	user := &models.User{Name: "Đen", Role: models.RoleArtist}
+	user.ID = primitive.NewObjectID()
+	existing := &models.ArtistProfile{UserID: user.ID, Name: "Đen", Bio: "kept"}
+	existing.ID = primitive.NewObjectID()
+
+	repo := &fakeArtistRepo{raceWith: existing}
+	svc := NewArtistService(repo, &fakeUserRepo{users: map[string]*models.User{user.ID.Hex(): user}}, &fakeLikeRepo{}, &fakeTrackService{}, nil)
+
+	resp, err := svc.UpdateMyArtistProfile(user.ID.Hex(), &dto.UpdateArtistProfileRequest{Name: "Đen Vâu"})
+	if err != nil {
+		t.Fatalf("UpdateMyArtistProfile() error = %v", err)
+	}
+	if len(repo.updated) != 1 || repo.updated[0] != existing {
+		t.Fatalf("the profile created concurrently was not updated: %+v", repo.updated)
+	}
+	if existing.Name != "Đen Vâu" || existing.Bio != "kept" {
+		t.Errorf("profile = %+v, want the new name and the existing bio", existing)
+	}
+	if resp.ID != existing.ID.Hex() {
+		t.Errorf("response ID = %s, want %s", resp.ID, existing.ID.Hex())
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"music-library-api/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Actor is the user making a request. UserID is empty for anonymous requests.
+type Actor struct {
+	UserID     string
+	Role       string // models.Role*
+	ShareToken string // playlist share link token, if the request carries one
+}
+
+func (a Actor) IsAdmin() bool {
+	return a.Role == models.RoleAdmin
+}
+
+// ObjectID returns the actor's user ID, or NilObjectID when anonymous.
+func (a Actor) ObjectID() primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(a.UserID)
+	return id
+}
+
+type IAuthorizationService interface {
+	PlaylistAccess(pl *models.Playlist, actor Actor) string
+	AuthorizePlaylist(pl *models.Playlist, actor Actor, need string) error
+}
+
+var ErrForbidden = errors.New("forbidden")
+
+// playlistRoleRank orders the playlist roles; "" means no access.
+var playlistRoleRank = map[string]int{
+	"":                        0,
+	models.PlaylistRoleViewer: 1,
+	models.PlaylistRoleEditor: 2,
+	models.PlaylistRoleOwner:  3,
+}
+
+// AuthorizationService decides what a user may do with a resource. Handlers
+// call it instead of comparing owner IDs themselves.
+type AuthorizationService struct{}
+
+func NewAuthorizationService() IAuthorizationService {
+	return &AuthorizationService{}
+}
+
+// PlaylistAccess returns the actor's role on a playlist: owner (admins too),
+// editor or viewer for collaborators, viewer for anyone when the playlist is
+// not private or the actor holds a valid share token, or "" otherwise.
+func (s *AuthorizationService) PlaylistAccess(pl *models.Playlist, actor Actor) string {
+	if actor.UserID != "" {
+		if actor.IsAdmin() || pl.UserID.Hex() == actor.UserID {
+			return models.PlaylistRoleOwner
+		}
+		if c := pl.Collaborator(actor.ObjectID()); c != nil {
+			return c.Role
+		}
+	}
+	if pl.Visibility != models.PlaylistVisibilityPrivate || pl.ShareLink(actor.ShareToken, time.Now()) != nil {
+		return models.PlaylistRoleViewer
+	}
+	return ""
+}
+
+// AuthorizePlaylist returns an error wrapping ErrForbidden unless the actor
+// has at least the needed role.
+func (s *AuthorizationService) AuthorizePlaylist(pl *models.Playlist, actor Actor, need string) error {
+	if playlistRoleRank[s.PlaylistAccess(pl, actor)] >= playlistRoleRank[need] {
+		return nil
+	}
+	switch need {
+	case models.PlaylistRoleOwner:
+		return fmt.Errorf("%w: only the owner of this playlist can do this", ErrForbidden)
+	case models.PlaylistRoleEditor:
+		return fmt.Errorf("%w: you need editor access to this playlist", ErrForbidden)
+	default:
+		return fmt.Errorf("%w: you do not have access to this playlist", ErrForbidden)
+	}
+}
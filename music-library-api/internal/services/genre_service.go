@@ -0,0 +1,390 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IGenreService interface {
+	GetGenreTree() ([]dto.GenreResponse, error)
+	GetGenre(slug string) (*dto.GenreResponse, error)
+	GetGenreTracks(slug string, page, limit int) ([]*models.Track, int64, error)
+	CreateGenre(req *dto.CreateGenreRequest) (*dto.GenreResponse, error)
+	UpdateGenre(slug string, req *dto.UpdateGenreRequest) (*dto.GenreResponse, error)
+	MergeGenre(sourceSlug, targetSlug string) (*dto.MergeGenreResponse, error)
+	NormalizeTrackGenre(track *models.Track) error
+}
+
+type GenreService struct {
+	repo         repositories.IGenreRepository
+	trackService ITrackService
+}
+
+func NewGenreService(repo repositories.IGenreRepository, trackService ITrackService) IGenreService {
+	return &GenreService{
+		repo:         repo,
+		trackService: trackService,
+	}
+}
+
+func (s *GenreService) GetGenreTree() ([]dto.GenreResponse, error) {
+	genres, err := s.repo.GetAllGenres()
+	if err != nil {
+		return nil, err
+	}
+	return mappers.ToGenreTree(genres), nil
+}
+
+// GetGenre returns a genre with its sub-genres nested under it.
+func (s *GenreService) GetGenre(slug string) (*dto.GenreResponse, error) {
+	genre, err := s.getBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.repo.GetDescendants(genre.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := mappers.ToGenreTree(append([]*models.Genre{genre}, descendants...))
+	return &tree[0], nil
+}
+
+// GetGenreTracks lists tracks of a genre and all of its sub-genres.
+func (s *GenreService) GetGenreTracks(slug string, page, limit int) ([]*models.Track, int64, error) {
+	genre, err := s.getBySlug(slug)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	descendants, err := s.repo.GetDescendants(genre.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := []primitive.ObjectID{genre.ID}
+	for _, d := range descendants {
+		ids = append(ids, d.ID)
+	}
+
+	tracks, err := s.trackService.GetTracksByGenreIDs(ids, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.trackService.CountTracksByGenreIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tracks, total, nil
+}
+
+func (s *GenreService) CreateGenre(req *dto.CreateGenreRequest) (*dto.GenreResponse, error) {
+	slug := utils.Slugify(req.Slug)
+	if slug == "" {
+		slug = utils.Slugify(req.Name)
+	}
+	if slug == "" {
+		return nil, errors.New("genre name must contain letters or digits")
+	}
+
+	genre := &models.Genre{
+		Slug:         slug,
+		Name:         strings.TrimSpace(req.Name),
+		Aliases:      cleanAliases(req.Aliases),
+		DisplayNames: cleanDisplayNames(req.DisplayNames),
+		Ancestors:    []primitive.ObjectID{},
+	}
+
+	if req.ParentSlug != "" {
+		parent, err := s.getBySlug(req.ParentSlug)
+		if err != nil {
+			return nil, err
+		}
+		setGenreParent(genre, parent)
+	}
+
+	if err := s.assignKeys(genre); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateGenre(genre); err != nil {
+		return nil, err
+	}
+
+	if err := s.linkExistingTracks(genre); err != nil {
+		return nil, err
+	}
+
+	resp := mappers.ToGenreResponse(genre)
+	return &resp, nil
+}
+
+func (s *GenreService) UpdateGenre(slug string, req *dto.UpdateGenreRequest) (*dto.GenreResponse, error) {
+	genre, err := s.getBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := false
+	if name := strings.TrimSpace(req.Name); name != "" && name != genre.Name {
+		genre.Name = name
+		renamed = true
+	}
+	if req.Aliases != nil {
+		genre.Aliases = cleanAliases(req.Aliases)
+	}
+	if req.DisplayNames != nil {
+		genre.DisplayNames = cleanDisplayNames(req.DisplayNames)
+	}
+
+	reparented := false
+	if req.ParentSlug != nil {
+		var parent *models.Genre
+		if *req.ParentSlug != "" {
+			parent, err = s.getBySlug(*req.ParentSlug)
+			if err != nil {
+				return nil, err
+			}
+			if parent.ID == genre.ID || containsObjectID(parent.Ancestors, genre.ID) {
+				return nil, errors.New("a genre cannot be moved under itself or one of its sub-genres")
+			}
+		}
+		setGenreParent(genre, parent)
+		reparented = true
+	}
+
+	if err := s.assignKeys(genre); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateGenre(genre); err != nil {
+		return nil, err
+	}
+
+	if reparented {
+		if err := s.rebuildSubtree(genre); err != nil {
+			return nil, err
+		}
+	}
+	if renamed {
+		if _, err := s.trackService.ReassignGenre([]primitive.ObjectID{genre.ID}, genre.ID, genre.Name); err != nil {
+			return nil, fmt.Errorf("failed to rename genre on tracks: %w", err)
+		}
+	}
+	if err := s.linkExistingTracks(genre); err != nil {
+		return nil, err
+	}
+
+	resp := mappers.ToGenreResponse(genre)
+	return &resp, nil
+}
+
+// MergeGenre folds source into target: tracks are re-pointed, sub-genres are
+// moved under target, and source's names become aliases of target.
+func (s *GenreService) MergeGenre(sourceSlug, targetSlug string) (*dto.MergeGenreResponse, error) {
+	source, err := s.getBySlug(sourceSlug)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.getBySlug(targetSlug)
+	if err != nil {
+		return nil, err
+	}
+	if source.ID == target.ID {
+		return nil, errors.New("cannot merge a genre into itself")
+	}
+	if containsObjectID(target.Ancestors, source.ID) {
+		return nil, errors.New("cannot merge a genre into one of its own sub-genres")
+	}
+
+	// Check that target can take over source's names before changing anything;
+	// source's own keys are freed by the merge so they do not count as clashes
+	aliases := append([]string{}, target.Aliases...)
+	aliases = append(aliases, source.Slug, source.Name)
+	aliases = append(aliases, source.Aliases...)
+	for _, name := range source.DisplayNames {
+		aliases = append(aliases, name)
+	}
+	target.Aliases = cleanAliases(aliases)
+	if err := s.assignKeys(target, source.ID); err != nil {
+		return nil, err
+	}
+
+	moved, err := s.trackService.ReassignGenre([]primitive.ObjectID{source.ID}, target.ID, target.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-point tracks: %w", err)
+	}
+
+	children, err := s.repo.GetChildren(source.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		setGenreParent(child, target)
+		if err := s.repo.UpdateGenre(child); err != nil {
+			return nil, err
+		}
+		if err := s.rebuildSubtree(child); err != nil {
+			return nil, err
+		}
+	}
+
+	// Free source's keys before target claims them. The keys were checked
+	// above, so put source back if target still cannot be saved.
+	if err := s.repo.DeleteGenre(source); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateGenre(target); err != nil {
+		if restoreErr := s.repo.CreateGenre(source); restoreErr != nil {
+			return nil, fmt.Errorf("failed to merge genre: %w (restoring %s also failed: %v)", err, source.Slug, restoreErr)
+		}
+		return nil, err
+	}
+
+	return &dto.MergeGenreResponse{
+		Genre:       mappers.ToGenreResponse(target),
+		TracksMoved: moved,
+	}, nil
+}
+
+// NormalizeTrackGenre resolves the free-text genre of a track against the
+// taxonomy, storing the canonical name and genre ID. Unknown genres are kept as typed.
+func (s *GenreService) NormalizeTrackGenre(track *models.Track) error {
+	track.Genre = strings.TrimSpace(track.Genre)
+	track.GenreID = nil
+
+	key := utils.NormalizeKey(track.Genre)
+	if key == "" {
+		return nil
+	}
+
+	genre, err := s.repo.FindGenreByKey(key)
+	if err != nil {
+		return err
+	}
+	if genre != nil {
+		track.Genre = genre.Name
+		track.GenreID = &genre.ID
+	}
+	return nil
+}
+
+func (s *GenreService) getBySlug(slug string) (*models.Genre, error) {
+	genre, err := s.repo.GetGenreBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	if genre == nil {
+		return nil, fmt.Errorf("genre %s not found", slug)
+	}
+	return genre, nil
+}
+
+// assignKeys rebuilds the lookup keys and rejects names already used by
+// another genre. Genres in ignore (e.g. one being merged away) may share keys.
+func (s *GenreService) assignKeys(genre *models.Genre, ignore ...primitive.ObjectID) error {
+	names := append([]string{genre.Slug, genre.Name}, genre.Aliases...)
+	for _, name := range genre.DisplayNames {
+		names = append(names, name)
+	}
+
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		key := utils.NormalizeKey(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		other, err := s.repo.FindGenreByKey(key)
+		if err != nil {
+			return err
+		}
+		if other != nil && other.ID != genre.ID && !containsObjectID(ignore, other.ID) {
+			return fmt.Errorf("%q is already used by genre %s", name, other.Slug)
+		}
+		keys = append(keys, key)
+	}
+
+	genre.Keys = keys
+	return nil
+}
+
+// rebuildSubtree recomputes the ancestors of every genre below root.
+func (s *GenreService) rebuildSubtree(root *models.Genre) error {
+	children, err := s.repo.GetChildren(root.ID)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		setGenreParent(child, root)
+		if err := s.repo.UpdateGenre(child); err != nil {
+			return err
+		}
+		if err := s.rebuildSubtree(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GenreService) linkExistingTracks(genre *models.Genre) error {
+	names := append([]string{genre.Name, genre.Slug}, genre.Aliases...)
+	for _, name := range genre.DisplayNames {
+		names = append(names, name)
+	}
+	if _, err := s.trackService.LinkUnassignedGenre(names, genre.ID, genre.Name); err != nil {
+		return fmt.Errorf("failed to link existing tracks: %w", err)
+	}
+	return nil
+}
+
+func setGenreParent(genre, parent *models.Genre) {
+	if parent == nil {
+		genre.ParentID = nil
+		genre.Ancestors = []primitive.ObjectID{}
+		return
+	}
+	genre.ParentID = &parent.ID
+	genre.Ancestors = append(append([]primitive.ObjectID{}, parent.Ancestors...), parent.ID)
+}
+
+func cleanAliases(aliases []string) []string {
+	if len(aliases) == 1 && strings.Contains(aliases[0], ",") {
+		aliases = strings.Split(aliases[0], ",")
+	}
+	cleaned := utils.UniqueStrings(aliases)
+	if cleaned == nil {
+		return []string{}
+	}
+	return cleaned
+}
+
+func cleanDisplayNames(names map[string]string) map[string]string {
+	cleaned := map[string]string{}
+	for locale, name := range names {
+		locale = strings.ToLower(strings.TrimSpace(locale))
+		name = strings.TrimSpace(name)
+		if locale != "" && name != "" {
+			cleaned[locale] = name
+		}
+	}
+	return cleaned
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ILikeService interface {
+	Like(actor Actor, targetType, id string) (*dto.LikeResponse, error)
+	Unlike(actor Actor, targetType, id string) (*dto.LikeResponse, error)
+	GetLikes(actor Actor, targetType string, page, limit int) ([]dto.LikeItemResponse, int64, error)
+	EnsureIndexes() error
+}
+
+var (
+	ErrLikeTargetNotFound = errors.New("nothing to like with this ID")
+	ErrInvalidLikeTarget  = errors.New("type must be track, album, artist or playlist")
+)
+
+type LikeService struct {
+	repo            repositories.ILikeRepository
+	trackService    ITrackService
+	albumService    IAlbumService
+	artistService   IArtistService
+	playlistService IPlaylistService
+}
+
+func NewLikeService(repo repositories.ILikeRepository, trackService ITrackService, albumService IAlbumService, artistService IArtistService, playlistService IPlaylistService) ILikeService {
+	return &LikeService{
+		repo:            repo,
+		trackService:    trackService,
+		albumService:    albumService,
+		artistService:   artistService,
+		playlistService: playlistService,
+	}
+}
+
+// Like is idempotent: liking a target twice keeps the first like.
+func (s *LikeService) Like(actor Actor, targetType, id string) (*dto.LikeResponse, error) {
+	targetID, track, err := s.resolve(targetType, id)
+	if err != nil {
+		return nil, err
+	}
+	inserted, err := s.repo.Like(actor.ObjectID(), targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return s.likeResponse(actor, targetType, targetID, track, true, inserted)
+}
+
+// Unlike also works for targets deleted since they were liked, so that they
+// can be removed from the user's likes.
+func (s *LikeService) Unlike(actor Actor, targetType, id string) (*dto.LikeResponse, error) {
+	targetID, track, err := s.resolve(targetType, id)
+	if errors.Is(err, ErrLikeTargetNotFound) {
+		targetID, err = primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, ErrLikeTargetNotFound
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	deleted, err := s.repo.Unlike(actor.ObjectID(), targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return s.likeResponse(actor, targetType, targetID, track, false, deleted)
+}
+
+// GetLikes lists the actor's likes of one target type, most recent first.
+func (s *LikeService) GetLikes(actor Actor, targetType string, page, limit int) ([]dto.LikeItemResponse, int64, error) {
+	if !validLikeTarget(targetType) {
+		return nil, 0, ErrInvalidLikeTarget
+	}
+	likes, err := s.repo.GetLikes(actor.ObjectID(), targetType, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.repo.CountUserLikes(actor.ObjectID(), targetType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]dto.LikeItemResponse, len(likes))
+	for i, l := range likes {
+		resp[i] = mappers.ToLikeItemResponse(l)
+	}
+	return resp, total, nil
+}
+
+func (s *LikeService) EnsureIndexes() error {
+	return s.repo.EnsureIndexes()
+}
+
+// resolve finds the ID a like is stored under. Artists are liked by user ID,
+// whether id is their profile ID or their user ID. Liked tracks are returned
+// as well, for their stored like count.
+func (s *LikeService) resolve(targetType, id string) (primitive.ObjectID, *models.Track, error) {
+	switch targetType {
+	case models.LikeTargetTrack:
+		track, err := s.trackService.GetTrackByID(id)
+		if err != nil {
+			return primitive.NilObjectID, nil, ErrLikeTargetNotFound
+		}
+		return track.ID, track, nil
+	case models.LikeTargetAlbum:
+		album, err := s.albumService.GetAlbumByID(id)
+		if err != nil {
+			return primitive.NilObjectID, nil, ErrLikeTargetNotFound
+		}
+		return album.ID, nil, nil
+	case models.LikeTargetArtist:
+		userID, err := s.artistService.ResolveArtistUserID(id)
+		if err != nil {
+			return primitive.NilObjectID, nil, ErrLikeTargetNotFound
+		}
+		return userID, nil, nil
+	case models.LikeTargetPlaylist:
+		pl, err := s.playlistService.GetPlaylistByID(id)
+		if err != nil {
+			return primitive.NilObjectID, nil, ErrLikeTargetNotFound
+		}
+		return pl.ID, nil, nil
+	}
+	return primitive.NilObjectID, nil, ErrInvalidLikeTarget
+}
+
+// likeResponse reports the like count after a like or unlike. Tracks keep
+// theirs on the track, so that smart playlists can filter and sort on it;
+// it only changes when a like was actually added or removed.
+func (s *LikeService) likeResponse(actor Actor, targetType string, targetID primitive.ObjectID, track *models.Track, liked, changed bool) (*dto.LikeResponse, error) {
+	resp := &dto.LikeResponse{TargetType: targetType, TargetID: targetID.Hex(), Liked: liked}
+
+	if targetType != models.LikeTargetTrack {
+		counts, err := s.repo.CountLikes(targetType, []primitive.ObjectID{targetID})
+		if err != nil {
+			return nil, err
+		}
+		resp.LikeCount = counts[targetID]
+		return resp, nil
+	}
+
+	if track != nil {
+		resp.LikeCount = track.LikeCount
+	}
+	if !changed {
+		return resp, nil
+	}
+	if track != nil {
+		delta := int64(1)
+		if !liked {
+			delta = -1
+		}
+		count, err := s.trackService.IncrementLikeCount(targetID, delta)
+		if err != nil {
+			return nil, err
+		}
+		resp.LikeCount = count
+	}
+	if err := s.playlistService.RefreshLikedSongs(actor.ObjectID()); err != nil {
+		// The like is saved; Liked Songs is created when the user next opens it
+		log.Printf("Failed to refresh the liked songs of %s: %v", actor.UserID, err)
+	}
+	return resp, nil
+}
+
+func validLikeTarget(targetType string) bool {
+	switch targetType {
+	case models.LikeTargetTrack, models.LikeTargetAlbum, models.LikeTargetArtist, models.LikeTargetPlaylist:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	unknownLanguage = "und"
+	maxLyricsSize   = 512 * 1024
+)
+
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+type ILyricsService interface {
+	GetLyrics(trackID, language string) (*dto.LyricsResponse, error)
+	GetLyricsLRC(trackID, language string) (string, error)
+	UpsertLyrics(trackID string, req *dto.UpsertLyricsRequest) (*dto.LyricsResponse, error)
+	DeleteLyrics(trackID, language string) error
+	ImportID3Lyrics(track *models.Track, tag *utils.ID3Tag) error
+}
+
+type LyricsService struct {
+	repo         repositories.ILyricsRepository
+	trackService ITrackService
+}
+
+func NewLyricsService(repo repositories.ILyricsRepository, trackService ITrackService) ILyricsService {
+	return &LyricsService{
+		repo:         repo,
+		trackService: trackService,
+	}
+}
+
+// GetLyrics returns the lyrics in the requested language, or the best available
+// one (synced first) when language is empty.
+func (s *LyricsService) GetLyrics(trackID, language string) (*dto.LyricsResponse, error) {
+	lyrics, all, err := s.pick(trackID, language)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make([]string, len(all))
+	for i, l := range all {
+		languages[i] = l.Language
+	}
+
+	resp := mappers.ToLyricsResponse(lyrics, languages)
+	return &resp, nil
+}
+
+// GetLyricsLRC renders the lyrics as an LRC file. Plain lyrics are returned
+// without time tags.
+func (s *LyricsService) GetLyricsLRC(trackID, language string) (string, error) {
+	lyrics, _, err := s.pick(trackID, language)
+	if err != nil {
+		return "", err
+	}
+
+	if !lyrics.Synced {
+		return lyrics.Text + "\n", nil
+	}
+
+	lines := make([]utils.LyricLine, len(lyrics.Lines))
+	for i, l := range lyrics.Lines {
+		lines[i] = utils.LyricLine{TimeMs: l.TimeMs, Text: l.Text}
+	}
+
+	tags := map[string]string{"la": lyrics.Language}
+	if track, err := s.trackService.GetTrackByID(trackID); err == nil {
+		tags["ti"] = track.Title
+		tags["ar"] = track.Artist
+		if track.Album != "" {
+			tags["al"] = track.Album
+		}
+	}
+	return utils.FormatLRC(tags, lines), nil
+}
+
+func (s *LyricsService) UpsertLyrics(trackID string, req *dto.UpsertLyricsRequest) (*dto.LyricsResponse, error) {
+	track, err := s.trackService.GetTrackByID(trackID)
+	if err != nil {
+		return nil, fmt.Errorf("track not found")
+	}
+
+	language := strings.ToLower(strings.TrimSpace(req.Language))
+	if language == "" {
+		language = unknownLanguage
+	}
+	if !languageCode.MatchString(language) {
+		return nil, fmt.Errorf("invalid language code: %s", req.Language)
+	}
+
+	content := req.Text
+	if req.File != nil {
+		content, err = readLyricsFile(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, errors.New("lyrics text or file is required")
+	}
+
+	lyrics := &models.Lyrics{TrackID: track.ID, Language: language, Source: models.LyricsSourceManual}
+	lrc := utils.ParseLRC(content)
+	if len(lrc.Lines) > 0 {
+		lyrics.Source = models.LyricsSourceLRC
+		setSyncedLines(lyrics, lrc.Lines)
+	} else {
+		lyrics.Text = strings.TrimSpace(content)
+	}
+
+	saved, err := s.save(lyrics)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reindex(track.ID); err != nil {
+		return nil, err
+	}
+	return s.GetLyrics(trackID, saved.Language)
+}
+
+// DeleteLyrics removes one language, or all lyrics of the track when language is empty.
+func (s *LyricsService) DeleteLyrics(trackID, language string) error {
+	id, err := primitive.ObjectIDFromHex(trackID)
+	if err != nil {
+		return fmt.Errorf("track not found")
+	}
+
+	deleted, err := s.repo.DeleteLyrics(id, strings.ToLower(language))
+	if err != nil {
+		return err
+	}
+	if deleted == 0 && language != "" {
+		return fmt.Errorf("lyrics not found")
+	}
+	return s.reindex(id)
+}
+
+// ImportID3Lyrics stores SYLT (synced) and USLT (plain) frames of an uploaded file.
+// Synced lyrics win when both exist for the same language.
+func (s *LyricsService) ImportID3Lyrics(track *models.Track, tag *utils.ID3Tag) error {
+	synced := tag.SyncedLyrics()
+	unsynced := tag.UnsyncedLyrics()
+	if len(synced) == 0 && len(unsynced) == 0 {
+		return nil
+	}
+
+	stored := map[string]bool{}
+	for _, frame := range synced {
+		lyrics := &models.Lyrics{TrackID: track.ID, Language: id3Language(frame.Language), Source: models.LyricsSourceSYLT}
+		setSyncedLines(lyrics, frame.Lines)
+		if _, err := s.save(lyrics); err != nil {
+			return err
+		}
+		stored[lyrics.Language] = true
+	}
+
+	for _, frame := range unsynced {
+		language := id3Language(frame.Language)
+		if stored[language] || frame.Text == "" {
+			continue
+		}
+
+		lyrics := &models.Lyrics{TrackID: track.ID, Language: language, Source: models.LyricsSourceUSLT}
+		// Some taggers put LRC content in USLT
+		if lrc := utils.ParseLRC(frame.Text); len(lrc.Lines) > 0 {
+			setSyncedLines(lyrics, lrc.Lines)
+		} else {
+			lyrics.Text = frame.Text
+		}
+		if _, err := s.save(lyrics); err != nil {
+			return err
+		}
+		stored[language] = true
+	}
+
+	return s.reindex(track.ID)
+}
+
+func (s *LyricsService) pick(trackID, language string) (*models.Lyrics, []*models.Lyrics, error) {
+	id, err := primitive.ObjectIDFromHex(trackID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("track not found")
+	}
+
+	all, err := s.repo.GetLyricsByTrack(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(all) == 0 {
+		return nil, nil, fmt.Errorf("lyrics not found")
+	}
+	if language == "" {
+		return all[0], all, nil
+	}
+
+	language = strings.ToLower(language)
+	for _, l := range all {
+		if l.Language == language {
+			return l, all, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("lyrics not found for language %s", language)
+}
+
+// save replaces the lyrics of the same track and language, if any.
+func (s *LyricsService) save(lyrics *models.Lyrics) (*models.Lyrics, error) {
+	existing, err := s.repo.GetLyrics(lyrics.TrackID, lyrics.Language)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return lyrics, s.repo.CreateLyrics(lyrics)
+	}
+
+	existing.Synced = lyrics.Synced
+	existing.Text = lyrics.Text
+	existing.Lines = lyrics.Lines
+	existing.Source = lyrics.Source
+	return existing, s.repo.UpdateLyrics(existing)
+}
+
+// reindex copies every language of the track's lyrics onto the track for search.
+func (s *LyricsService) reindex(trackID primitive.ObjectID) error {
+	all, err := s.repo.GetLyricsByTrack(trackID)
+	if err != nil {
+		return err
+	}
+
+	texts := make([]string, len(all))
+	for i, l := range all {
+		texts[i] = l.Text
+	}
+	if err := s.trackService.SetLyricsText(trackID, strings.Join(texts, "\n")); err != nil {
+		return fmt.Errorf("failed to index lyrics: %w", err)
+	}
+	return nil
+}
+
+func setSyncedLines(lyrics *models.Lyrics, lines []utils.LyricLine) {
+	lyrics.Synced = true
+	lyrics.Lines = make([]models.LyricLine, len(lines))
+	texts := make([]string, 0, len(lines))
+	for i, l := range lines {
+		lyrics.Lines[i] = models.LyricLine{TimeMs: l.TimeMs, Text: l.Text}
+		if l.Text != "" {
+			texts = append(texts, l.Text)
+		}
+	}
+	lyrics.Text = strings.Join(texts, "\n")
+}
+
+func readLyricsFile(req *dto.UpsertLyricsRequest) (string, error) {
+	name := strings.ToLower(req.File.Filename)
+	if !strings.HasSuffix(name, ".lrc") && !strings.HasSuffix(name, ".txt") {
+		return "", errors.New("only .lrc and .txt lyrics files allowed")
+	}
+	if req.File.Size > maxLyricsSize {
+		return "", errors.New("lyrics file is too large")
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxLyricsSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read lyrics file: %w", err)
+	}
+	return string(data), nil
+}
+
+// id3Language maps the 3-letter ID3 language field, which taggers often fill
+// with "XXX" or garbage, to a usable code.
+func id3Language(code string) string {
+	code = strings.ToLower(strings.TrimSpace(strings.Trim(code, "\x00")))
+	if !languageCode.MatchString(code) || code == "xxx" {
+		return unknownLanguage
+	}
+	return code
+}
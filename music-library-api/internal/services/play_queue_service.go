@@ -0,0 +1,477 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IPlayQueueService interface {
+	GetQueue(userID string) (*dto.PlayQueueResponse, error)
+	BuildQueue(actor Actor, device string, req *dto.BuildPlayQueueRequest) (*dto.PlayQueueResponse, error)
+	UpdatePlayback(userID, device string, req *dto.UpdatePlaybackRequest) (*dto.PlayQueueResponse, error)
+	AddTracks(userID, device string, req *dto.AddToPlayQueueRequest) (*dto.PlayQueueResponse, error)
+	RemoveItem(userID, device, itemID string) (*dto.PlayQueueResponse, error)
+	Next(userID, device string) (*dto.PlayQueueResponse, error)
+	Previous(userID, device string) (*dto.PlayQueueResponse, error)
+	Clear(userID, device string) (*dto.PlayQueueResponse, error)
+	Subscribe(userID string) (<-chan *dto.PlayQueueResponse, func())
+	EnsureIndexes() error
+}
+
+var (
+	ErrPlayQueueItemNotFound   = errors.New("queue item not found")
+	ErrPlayQueueSourceNotFound = errors.New("playlist or album not found")
+	ErrEmptyPlayQueueSource    = errors.New("there are no tracks to play")
+	ErrInvalidQueuePosition    = errors.New("start_index is out of range")
+	ErrPlayQueueConflict       = errors.New("the queue is being changed from another device, try again")
+)
+
+const (
+	queueSaveAttempts  = 3
+	restartThresholdMs = 3000 // Previous restarts the current track past this position
+)
+
+// PlayQueueService keeps the queue of each user in MongoDB and pushes every
+// change to the user's connected devices. Subscribers live in memory, so
+// devices must be connected to the same instance to be notified.
+type PlayQueueService struct {
+	repo            repositories.IPlayQueueRepository
+	trackService    ITrackService
+	playlistService IPlaylistService
+	albumService    IAlbumService
+	authz           IAuthorizationService
+
+	mu          sync.Mutex
+	subscribers map[primitive.ObjectID]map[chan *dto.PlayQueueResponse]struct{}
+}
+
+func NewPlayQueueService(repo repositories.IPlayQueueRepository, trackService ITrackService, playlistService IPlaylistService, albumService IAlbumService, authz IAuthorizationService) IPlayQueueService {
+	return &PlayQueueService{
+		repo:            repo,
+		trackService:    trackService,
+		playlistService: playlistService,
+		albumService:    albumService,
+		authz:           authz,
+		subscribers:     map[primitive.ObjectID]map[chan *dto.PlayQueueResponse]struct{}{},
+	}
+}
+
+// GetQueue returns the user's queue, empty if they never played anything.
+func (s *PlayQueueService) GetQueue(userID string) (*dto.PlayQueueResponse, error) {
+	q, err := s.load(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(q)
+}
+
+// BuildQueue replaces the queue with the tracks of a source, starting at
+// StartIndex. Deleted tracks are skipped.
+func (s *PlayQueueService) BuildQueue(actor Actor, device string, req *dto.BuildPlayQueueRequest) (*dto.PlayQueueResponse, error) {
+	trackIDs, source, err := s.sourceTracks(actor, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.StartIndex >= len(trackIDs) && len(trackIDs) > 0 {
+		return nil, ErrInvalidQueuePosition
+	}
+
+	missing, err := s.trackService.FindMissingIDs(trackIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check track IDs: %w", err)
+	}
+	gone := make(map[primitive.ObjectID]bool, len(missing))
+	for _, id := range missing {
+		gone[id] = true
+	}
+
+	// The start track is the first one left at or after StartIndex
+	items := make([]models.PlayQueueItem, 0, len(trackIDs))
+	var current *primitive.ObjectID
+	for i, id := range trackIDs {
+		if gone[id] {
+			continue
+		}
+		item := models.PlayQueueItem{ID: primitive.NewObjectID(), TrackID: id}
+		items = append(items, item)
+		if current == nil && i >= req.StartIndex {
+			current = &item.ID
+		}
+	}
+	if len(items) == 0 {
+		return nil, ErrEmptyPlayQueueSource
+	}
+
+	return s.mutate(actor.UserID, device, func(q *models.PlayQueue) error {
+		q.Items = items
+		q.CurrentID = current
+		q.Source = source
+		q.Shuffle = req.Shuffle
+		if req.Repeat != "" {
+			q.Repeat = req.Repeat
+		}
+		if q.Shuffle {
+			shuffleQueue(q)
+		}
+		q.Playing = current != nil
+		setQueuePosition(q, 0)
+		return nil
+	})
+}
+
+func (s *PlayQueueService) UpdatePlayback(userID, device string, req *dto.UpdatePlaybackRequest) (*dto.PlayQueueResponse, error) {
+	return s.mutate(userID, device, func(q *models.PlayQueue) error {
+		if req.CurrentItemID != nil {
+			id, err := queueItemID(q, *req.CurrentItemID)
+			if err != nil {
+				return err
+			}
+			if q.CurrentID == nil || *q.CurrentID != id {
+				q.CurrentID = &id
+				setQueuePosition(q, 0)
+			}
+		}
+		if req.PositionMs != nil {
+			setQueuePosition(q, *req.PositionMs)
+		}
+		if req.Playing != nil {
+			if q.Playing != *req.Playing && req.PositionMs == nil {
+				// Freeze or restart the clock clients extrapolate from
+				setQueuePosition(q, currentQueuePosition(q))
+			}
+			q.Playing = *req.Playing
+		}
+		if req.Shuffle != nil && *req.Shuffle != q.Shuffle {
+			q.Shuffle = *req.Shuffle
+			if q.Shuffle {
+				shuffleQueue(q)
+			} else {
+				q.ShuffleOrder = nil
+			}
+		}
+		if req.Repeat != nil {
+			q.Repeat = *req.Repeat
+		}
+		return nil
+	})
+}
+
+// AddTracks appends tracks to the queue, or inserts them right after the
+// current track when req.Next is set.
+func (s *PlayQueueService) AddTracks(userID, device string, req *dto.AddToPlayQueueRequest) (*dto.PlayQueueResponse, error) {
+	trackIDs, err := utils.ConvertToObjectIDs(req.TrackIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrackIDs, err)
+	}
+	missing, err := s.trackService.FindMissingIDs(trackIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check track IDs: %w", err)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: these track IDs do not exist: %v", ErrInvalidTrackIDs, missing)
+	}
+
+	return s.mutate(userID, device, func(q *models.PlayQueue) error {
+		items := models.NewPlayQueueItems(trackIDs)
+		ids := make([]primitive.ObjectID, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+
+		at := len(q.Items)
+		if req.Next && q.CurrentID != nil {
+			at = slices.IndexFunc(q.Items, func(item models.PlayQueueItem) bool { return item.ID == *q.CurrentID }) + 1
+		}
+		q.Items = slices.Insert(q.Items, at, items...)
+
+		if q.Shuffle {
+			at := len(q.ShuffleOrder)
+			if req.Next && q.CurrentID != nil {
+				at = slices.Index(q.ShuffleOrder, *q.CurrentID) + 1
+			}
+			q.ShuffleOrder = slices.Insert(q.ShuffleOrder, at, ids...)
+		}
+		return nil
+	})
+}
+
+// RemoveItem takes an item out of the queue. Removing the current item moves
+// on to the next one.
+func (s *PlayQueueService) RemoveItem(userID, device, itemID string) (*dto.PlayQueueResponse, error) {
+	return s.mutate(userID, device, func(q *models.PlayQueue) error {
+		id, err := queueItemID(q, itemID)
+		if err != nil {
+			return err
+		}
+
+		if q.CurrentID != nil && *q.CurrentID == id {
+			order := q.PlayOrder()
+			i := q.CurrentIndex()
+			q.CurrentID = nil
+			if i+1 < len(order) {
+				q.CurrentID = &order[i+1].ID
+			} else {
+				q.Playing = false
+			}
+			setQueuePosition(q, 0)
+		}
+
+		q.Items = slices.DeleteFunc(q.Items, func(item models.PlayQueueItem) bool { return item.ID == id })
+		q.ShuffleOrder = slices.DeleteFunc(q.ShuffleOrder, func(other primitive.ObjectID) bool { return other == id })
+		return nil
+	})
+}
+
+// Next skips to the next track, wrapping around with repeat all. Past the
+// last track playback stops.
+func (s *PlayQueueService) Next(userID, device string) (*dto.PlayQueueResponse, error) {
+	return s.mutate(userID, device, func(q *models.PlayQueue) error {
+		order := q.PlayOrder()
+		i := q.CurrentIndex()
+		switch {
+		case i+1 < len(order):
+			q.CurrentID = &order[i+1].ID
+		case q.Repeat == models.RepeatAll && len(order) > 0:
+			q.CurrentID = &order[0].ID
+		default:
+			q.CurrentID = nil
+			q.Playing = false
+		}
+		setQueuePosition(q, 0)
+		return nil
+	})
+}
+
+// Previous restarts the current track, or goes back to the previous one when
+// the current track has just started.
+func (s *PlayQueueService) Previous(userID, device string) (*dto.PlayQueueResponse, error) {
+	return s.mutate(userID, device, func(q *models.PlayQueue) error {
+		order := q.PlayOrder()
+		i := q.CurrentIndex()
+		if i >= 0 && currentQueuePosition(q) < restartThresholdMs {
+			switch {
+			case i > 0:
+				q.CurrentID = &order[i-1].ID
+			case q.Repeat == models.RepeatAll:
+				q.CurrentID = &order[len(order)-1].ID
+			}
+		}
+		setQueuePosition(q, 0)
+		return nil
+	})
+}
+
+// Clear empties the queue but keeps the shuffle and repeat modes.
+func (s *PlayQueueService) Clear(userID, device string) (*dto.PlayQueueResponse, error) {
+	return s.mutate(userID, device, func(q *models.PlayQueue) error {
+		q.Items = []models.PlayQueueItem{}
+		q.ShuffleOrder = nil
+		q.CurrentID = nil
+		q.Source = nil
+		q.Playing = false
+		setQueuePosition(q, 0)
+		return nil
+	})
+}
+
+// Subscribe returns a channel receiving the user's queue after every change,
+// and the function to call when the device disconnects. A slow reader only
+// misses intermediate states: the latest one is always delivered.
+func (s *PlayQueueService) Subscribe(userID string) (<-chan *dto.PlayQueueResponse, func()) {
+	id, _ := primitive.ObjectIDFromHex(userID)
+	ch := make(chan *dto.PlayQueueResponse, 1)
+
+	s.mu.Lock()
+	if s.subscribers[id] == nil {
+		s.subscribers[id] = map[chan *dto.PlayQueueResponse]struct{}{}
+	}
+	s.subscribers[id][ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers[id], ch)
+		if len(s.subscribers[id]) == 0 {
+			delete(s.subscribers, id)
+		}
+	}
+}
+
+func (s *PlayQueueService) EnsureIndexes() error {
+	return s.repo.EnsureIndexes()
+}
+
+func (s *PlayQueueService) publish(userID primitive.ObjectID, resp *dto.PlayQueueResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers[userID] {
+		// Replace an undelivered state with the newer one
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- resp
+	}
+}
+
+// mutate applies fn to the user's queue and saves it with optimistic
+// concurrency. On a conflict with another device fn is applied again to the
+// fresh queue, so concurrent changes are not lost.
+func (s *PlayQueueService) mutate(userID, device string, fn func(q *models.PlayQueue) error) (*dto.PlayQueueResponse, error) {
+	for range queueSaveAttempts {
+		q, err := s.load(userID)
+		if err != nil {
+			return nil, err
+		}
+		version := q.Version
+		if err := fn(q); err != nil {
+			return nil, err
+		}
+		q.Device = device
+
+		ok, err := s.repo.SaveQueue(q, version)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		resp, err := s.toResponse(q)
+		if err != nil {
+			return nil, err
+		}
+		s.publish(q.UserID, resp)
+		return resp, nil
+	}
+	return nil, ErrPlayQueueConflict
+}
+
+func (s *PlayQueueService) load(userID string) (*models.PlayQueue, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	q, err := s.repo.GetQueue(id)
+	if err != nil {
+		return nil, err
+	}
+	if q == nil {
+		q = &models.PlayQueue{UserID: id, Items: []models.PlayQueueItem{}, Repeat: models.RepeatOff}
+	}
+	return q, nil
+}
+
+func (s *PlayQueueService) toResponse(q *models.PlayQueue) (*dto.PlayQueueResponse, error) {
+	ids := make([]primitive.ObjectID, len(q.Items))
+	for i, item := range q.Items {
+		ids[i] = item.TrackID
+	}
+	tracks, err := s.trackService.GetTracksByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tracks: %w", err)
+	}
+	byID := make(map[primitive.ObjectID]*models.Track, len(tracks))
+	for _, t := range tracks {
+		byID[t.ID] = t
+	}
+	return mappers.ToPlayQueueResponse(q, byID), nil
+}
+
+// sourceTracks returns the tracks of the source a queue is built from, in order.
+func (s *PlayQueueService) sourceTracks(actor Actor, req *dto.BuildPlayQueueRequest) ([]primitive.ObjectID, *models.PlayQueueSource, error) {
+	if req.SourceType == models.PlayQueueSourceTracks {
+		ids, err := utils.ConvertToObjectIDs(req.TrackIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidTrackIDs, err)
+		}
+		return ids, &models.PlayQueueSource{Type: models.PlayQueueSourceTracks}, nil
+	}
+
+	var ids []primitive.ObjectID
+	switch req.SourceType {
+	case models.PlayQueueSourcePlaylist:
+		pl, err := s.playlistService.GetPlaylistByID(req.SourceID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, nil, ErrPlayQueueSourceNotFound
+			}
+			return nil, nil, err
+		}
+		if s.authz.PlaylistAccess(pl, actor) == "" {
+			return nil, nil, ErrPlayQueueSourceNotFound
+		}
+		for _, e := range pl.Entries {
+			ids = append(ids, e.TrackID)
+		}
+		return ids, &models.PlayQueueSource{Type: req.SourceType, ID: &pl.ID}, nil
+
+	case models.PlayQueueSourceAlbum:
+		album, err := s.albumService.GetAlbumByID(req.SourceID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, nil, ErrPlayQueueSourceNotFound
+			}
+			return nil, nil, err
+		}
+		for _, t := range album.Tracks {
+			ids = append(ids, t.TrackID)
+		}
+		return ids, &models.PlayQueueSource{Type: req.SourceType, ID: &album.ID}, nil
+	}
+	return nil, nil, ErrPlayQueueSourceNotFound
+}
+
+// shuffleQueue shuffles the items, keeping the current one first so that the
+// track playing is not interrupted.
+func shuffleQueue(q *models.PlayQueue) {
+	order := make([]primitive.ObjectID, 0, len(q.Items))
+	for _, item := range q.Items {
+		if q.CurrentID == nil || item.ID != *q.CurrentID {
+			order = append(order, item.ID)
+		}
+	}
+	rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	if q.CurrentID != nil {
+		order = slices.Insert(order, 0, *q.CurrentID)
+	}
+	q.ShuffleOrder = order
+}
+
+func setQueuePosition(q *models.PlayQueue, ms int64) {
+	q.PositionMs = ms
+	q.PositionAt = time.Now().UTC()
+}
+
+// currentQueuePosition extrapolates the position of a playing track from the
+// last reported one.
+func currentQueuePosition(q *models.PlayQueue) int64 {
+	if !q.Playing {
+		return q.PositionMs
+	}
+	return q.PositionMs + time.Since(q.PositionAt).Milliseconds()
+}
+
+func queueItemID(q *models.PlayQueue, itemID string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return primitive.NilObjectID, ErrPlayQueueItemNotFound
+	}
+	if !slices.ContainsFunc(q.Items, func(item models.PlayQueueItem) bool { return item.ID == id }) {
+		return primitive.NilObjectID, ErrPlayQueueItemNotFound
+	}
+	return id, nil
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IPlayService interface {
+	Scrobble(userID string, req *dto.ScrobbleRequest) (*dto.ScrobbleResponse, error)
+	GetHistory(userID string, page, limit int) ([]dto.PlayHistoryItemResponse, int64, error)
+	GetNowPlaying(userID string) (*dto.NowPlayingResponse, error)
+	EnsureCollections() error
+}
+
+var (
+	ErrPlayTrackNotFound = errors.New("track not found")
+	ErrInvalidPlayTime   = errors.New("started_at cannot be in the future")
+	ErrMissingPlayTime   = errors.New("started_at is required for played events")
+)
+
+// The Last.fm scrobbling rule: a track longer than 30 seconds counts as
+// played once half of it, or 4 minutes, has been listened to.
+const (
+	minScrobbleDuration = 30 // seconds
+	scrobbleAfter       = 4 * time.Minute
+)
+
+type PlayService struct {
+	repo         repositories.IPlayRepository
+	trackService ITrackService
+}
+
+func NewPlayService(repo repositories.IPlayRepository, trackService ITrackService) IPlayService {
+	return &PlayService{repo: repo, trackService: trackService}
+}
+
+// Scrobble records a now_playing or played event. A played event below the
+// threshold, or already recorded, is accepted but not counted. Played events
+// must carry started_at: it identifies the play, so a retried event is not
+// counted twice.
+func (s *PlayService) Scrobble(userID string, req *dto.ScrobbleRequest) (*dto.ScrobbleResponse, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	track, err := s.trackService.GetTrackByID(req.TrackID)
+	if err != nil {
+		return nil, ErrPlayTrackNotFound
+	}
+
+	if req.Event == models.PlayEventPlayed && req.StartedAt == nil {
+		return nil, ErrMissingPlayTime
+	}
+	// No one listens to more of a track than it lasts
+	playedMs := req.PlayedMs
+	if track.Duration > 0 {
+		playedMs = min(playedMs, int64(track.Duration)*1000)
+	}
+
+	now := time.Now().UTC()
+	startedAt := now
+	if req.StartedAt != nil {
+		if req.StartedAt.After(now) {
+			return nil, ErrInvalidPlayTime
+		}
+		startedAt = req.StartedAt.UTC()
+	}
+	// Mongo stores milliseconds; truncate so retries match the stored play
+	startedAt = startedAt.Truncate(time.Millisecond)
+
+	resp := &dto.ScrobbleResponse{Event: req.Event, TrackID: track.ID.Hex()}
+
+	if req.Event == models.PlayEventNowPlaying {
+		err := s.repo.SetNowPlaying(&models.NowPlaying{
+			UserID:    userIDObj,
+			TrackID:   track.ID,
+			StartedAt: startedAt,
+			ExpiresAt: startedAt.Add(time.Duration(track.Duration) * time.Second),
+		})
+		return resp, err
+	}
+
+	if reason := scrobbleRejection(track.Duration, playedMs); reason != "" {
+		resp.Reason = reason
+		return resp, nil
+	}
+
+	recorded, err := s.repo.RecordPlay(&models.Play{
+		Meta:     models.PlayMeta{UserID: userIDObj, TrackID: track.ID},
+		PlayedAt: startedAt,
+		PlayedMs: playedMs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		resp.Reason = "this play was already recorded"
+		return resp, nil
+	}
+
+	resp.Counted = true
+	if resp.PlayCount, err = s.trackService.IncrementPlayCount(track.ID); err != nil {
+		return nil, fmt.Errorf("failed to count play: %w", err)
+	}
+	if err := s.repo.IncrementUserTrackPlays(userIDObj, track.ID, startedAt); err != nil {
+		// The play itself is stored; only the rollup is behind
+		log.Printf("Failed to count play of track %s by user %s: %v", track.ID.Hex(), userID, err)
+	}
+	return resp, nil
+}
+
+// GetHistory returns the user's plays, most recent first.
+func (s *PlayService) GetHistory(userID string, page, limit int) ([]dto.PlayHistoryItemResponse, int64, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	plays, err := s.repo.GetHistory(userIDObj, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.repo.CountHistory(userIDObj)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]primitive.ObjectID, len(plays))
+	for i, p := range plays {
+		ids[i] = p.Meta.TrackID
+	}
+	tracks, err := s.trackService.GetTracksByIDs(ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve tracks: %w", err)
+	}
+	byID := make(map[primitive.ObjectID]*models.Track, len(tracks))
+	for _, t := range tracks {
+		byID[t.ID] = t
+	}
+
+	items := make([]dto.PlayHistoryItemResponse, len(plays))
+	for i, p := range plays {
+		items[i] = mappers.ToPlayHistoryItemResponse(p, byID[p.Meta.TrackID])
+	}
+	return items, total, nil
+}
+
+// GetNowPlaying returns the track the user is listening to, or nil.
+func (s *PlayService) GetNowPlaying(userID string) (*dto.NowPlayingResponse, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	np, err := s.repo.GetNowPlaying(userIDObj)
+	if err != nil || np == nil {
+		return nil, err
+	}
+
+	track, err := s.trackService.GetTrackByID(np.TrackID.Hex())
+	if err != nil {
+		track = nil
+	}
+	return mappers.ToNowPlayingResponse(np, track), nil
+}
+
+func (s *PlayService) EnsureCollections() error {
+	return s.repo.EnsureCollections()
+}
+
+// scrobbleRejection explains why a play does not count, or returns "" if it does.
+func scrobbleRejection(durationSec int, playedMs int64) string {
+	if durationSec <= minScrobbleDuration {
+		return fmt.Sprintf("tracks of %d seconds or less are not counted", minScrobbleDuration)
+	}
+	threshold := min(time.Duration(durationSec)*time.Second/2, scrobbleAfter)
+	if time.Duration(playedMs)*time.Millisecond < threshold {
+		return fmt.Sprintf("a play counts after %d seconds of listening", int(threshold.Seconds()))
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestScrobbleRejection(t *testing.T) {
+	tests := []struct {
+		name        string
+		durationSec int
+		playedMs    int64
+		counted     bool
+	}{
+		{"30 second track", 30, 30_000, false},
+		{"unknown duration", 0, 60_000, false},
+		{"half of a short track", 60, 30_000, true},
+		{"just under half", 60, 29_999, false},
+		{"four minutes of a long track", 600, 240_000, true},
+		{"half of a long track is not needed", 600, 239_999, false},
+		{"half of an eight minute track", 480, 240_000, true},
+		{"nothing played", 200, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := scrobbleRejection(tt.durationSec, tt.playedMs)
+			if (reason == "") != tt.counted {
+				t.Errorf("scrobbleRejection(%d, %d) = %q, want counted = %v", tt.durationSec, tt.playedMs, reason, tt.counted)
+			}
+		})
+	}
+}
+
+type fakePlayRepo struct {
+	repositories.IPlayRepository
+	keys  map[string]bool
+	plays []*models.Play
+}
+
+func (r *fakePlayRepo) RecordPlay(play *models.Play) (bool, error) {
+	key := play.Meta.UserID.Hex() + play.Meta.TrackID.Hex() + play.PlayedAt.String()
+	if r.keys[key] {
+		return false, nil
+	}
+	r.keys[key] = true
+	r.plays = append(r.plays, play)
+	return true, nil
+}
+
+func (r *fakePlayRepo) IncrementUserTrackPlays(userID, trackID primitive.ObjectID, playedAt time.Time) error {
+	return nil
+}
+
+func (s *fakeTrackService) GetTrackByID(id string) (*models.Track, error) {
+	for _, t := range s.tracks {
+		if t.ID.Hex() == id {
+			return t, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (s *fakeTrackService) IncrementPlayCount(id primitive.ObjectID) (int64, error) {
+	s.tracks[id].PlayCount++
+	return s.tracks[id].PlayCount, nil
+}
+
+func TestScrobblePlayed(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+	started := time.Now().UTC().Add(-time.Hour).Truncate(time.Millisecond)
+	future := time.Now().Add(time.Hour)
+
+	track := &models.Track{Duration: 200}
+	track.ID = primitive.NewObjectID()
+
+	tests := []struct {
+		name      string
+		req       dto.ScrobbleRequest
+		err       error
+		counted   bool
+		playedMs  int64
+		playCount int64
+	}{
+		{
+			name: "started_at is required",
+			req:  dto.ScrobbleRequest{TrackID: track.ID.Hex(), Event: models.PlayEventPlayed, PlayedMs: 200_000},
+			err:  ErrMissingPlayTime,
+		},
+		{
+			name: "started_at in the future",
+			req:  dto.ScrobbleRequest{TrackID: track.ID.Hex(), Event: models.PlayEventPlayed, PlayedMs: 200_000, StartedAt: &future},
+			err:  ErrInvalidPlayTime,
+		},
+		{
+			name: "unknown track",
+			req:  dto.ScrobbleRequest{TrackID: primitive.NewObjectID().Hex(), Event: models.PlayEventPlayed, StartedAt: &started},
+			err:  ErrPlayTrackNotFound,
+		},
+		{
+			name: "below the threshold",
+			req:  dto.ScrobbleRequest{TrackID: track.ID.Hex(), Event: models.PlayEventPlayed, PlayedMs: 1_000, StartedAt: &started},
+		},
+		{
+			name:      "played_ms is capped at the track duration",
+			req:       dto.ScrobbleRequest{TrackID: track.ID.Hex(), Event: models.PlayEventPlayed, PlayedMs: 10 * 3600_000, StartedAt: &started},
+			counted:   true,
+			playedMs:  200_000,
+			playCount: 1,
+		},
+		{
+			name: "a retry is not counted twice",
+			req:  dto.ScrobbleRequest{TrackID: track.ID.Hex(), Event: models.PlayEventPlayed, PlayedMs: 150_000, StartedAt: &started},
+		},
+	}
+
+	// The cases share the repository, so that the retry sees the earlier play
+	repo := &fakePlayRepo{keys: map[string]bool{}}
+	tracks := &fakeTrackService{tracks: map[primitive.ObjectID]*models.Track{track.ID: track}}
+	svc := NewPlayService(repo, tracks)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plays := len(repo.plays)
+			resp, err := svc.Scrobble(userID, &tt.req)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Scrobble() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if resp.Counted != tt.counted {
+				t.Errorf("Counted = %v, want %v (reason %q)", resp.Counted, tt.counted, resp.Reason)
+			}
+			if !tt.counted {
+				if len(repo.plays) != plays {
+					t.Errorf("an uncounted play was recorded")
+				}
+				return
+			}
+			if resp.PlayCount != tt.playCount {
+				t.Errorf("PlayCount = %d, want %d", resp.PlayCount, tt.playCount)
+			}
+			if got := repo.plays[len(repo.plays)-1]; got.PlayedMs != tt.playedMs || !got.PlayedAt.Equal(started) {
+				t.Errorf("recorded %d ms at %v, want %d ms at %v", got.PlayedMs, got.PlayedAt, tt.playedMs, started)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IPlaylistCollaborationService interface {
+	Invite(pl *models.Playlist, inviter Actor, req *dto.InvitePlaylistCollaboratorRequest) (*dto.PlaylistInvitationResponse, error)
+	GetPlaylistInvitations(pl *models.Playlist) ([]dto.PlaylistInvitationResponse, error)
+	RevokeInvitation(pl *models.Playlist, invitationID string) error
+	GetMyInvitations(actor Actor) ([]dto.PlaylistInvitationResponse, error)
+	AcceptInvitation(invitationID string, actor Actor) (*dto.PlaylistInvitationResponse, error)
+	DeclineInvitation(invitationID string, actor Actor) (*dto.PlaylistInvitationResponse, error)
+	SetCollaboratorRole(pl *models.Playlist, userID, role string) error
+	RemoveCollaborator(pl *models.Playlist, userID string) error
+}
+
+var (
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrInviteeNotFound      = errors.New("the invited user does not exist")
+	ErrInvalidInvitation    = errors.New("invalid invitation")
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+)
+
+// PlaylistCollaborationService manages playlist collaborators and the
+// invitations that add them. Callers authorize the playlist first.
+type PlaylistCollaborationService struct {
+	repo         repositories.IPlaylistInvitationRepository
+	playlistRepo repositories.IPlaylistRepository
+	userRepo     repositories.IUserRepository
+}
+
+func NewPlaylistCollaborationService(repo repositories.IPlaylistInvitationRepository, playlistRepo repositories.IPlaylistRepository, userRepo repositories.IUserRepository) IPlaylistCollaborationService {
+	return &PlaylistCollaborationService{
+		repo:         repo,
+		playlistRepo: playlistRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// Invite invites a user by ID or email. Inviting someone who already has a
+// pending invitation updates its role instead of creating another one.
+func (s *PlaylistCollaborationService) Invite(pl *models.Playlist, inviter Actor, req *dto.InvitePlaylistCollaboratorRequest) (*dto.PlaylistInvitationResponse, error) {
+	var invitee *models.User
+	var err error
+	if req.UserID != "" {
+		invitee, err = s.userRepo.GetUserByID(req.UserID)
+	} else {
+		invitee, err = s.userRepo.GetUserByEmail(strings.TrimSpace(req.Email))
+	}
+	if err != nil || invitee == nil {
+		return nil, ErrInviteeNotFound
+	}
+
+	if invitee.ID == pl.UserID {
+		return nil, fmt.Errorf("%w: the user already owns this playlist", ErrInvalidInvitation)
+	}
+	if pl.Collaborator(invitee.ID) != nil {
+		return nil, fmt.Errorf("%w: the user is already a collaborator, change their role instead", ErrInvalidInvitation)
+	}
+
+	invitation, err := s.repo.GetPendingInvitation(pl.ID, invitee.ID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation != nil {
+		invitation.Role = req.Role
+		invitation.InviterID = inviter.ObjectID()
+		err = s.repo.UpdateInvitation(invitation)
+	} else {
+		invitation = &models.PlaylistInvitation{
+			PlaylistID: pl.ID,
+			InviteeID:  invitee.ID,
+			InviterID:  inviter.ObjectID(),
+			Role:       req.Role,
+			Status:     models.InvitationPending,
+		}
+		err = s.repo.CreateInvitation(invitation)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := mappers.ToPlaylistInvitationResponse(invitation, pl.Title)
+	return &resp, nil
+}
+
+func (s *PlaylistCollaborationService) GetPlaylistInvitations(pl *models.Playlist) ([]dto.PlaylistInvitationResponse, error) {
+	invitations, err := s.repo.GetPendingByPlaylist(pl.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.PlaylistInvitationResponse, len(invitations))
+	for i, inv := range invitations {
+		resp[i] = mappers.ToPlaylistInvitationResponse(inv, pl.Title)
+	}
+	return resp, nil
+}
+
+func (s *PlaylistCollaborationService) RevokeInvitation(pl *models.Playlist, invitationID string) error {
+	invitation, err := s.repo.GetInvitationByID(invitationID)
+	if err != nil || invitation.PlaylistID != pl.ID || invitation.Status != models.InvitationPending {
+		return ErrInvitationNotFound
+	}
+	return s.respond(invitation, models.InvitationRevoked)
+}
+
+// GetMyInvitations lists the pending invitations of the actor.
+func (s *PlaylistCollaborationService) GetMyInvitations(actor Actor) ([]dto.PlaylistInvitationResponse, error) {
+	invitations, err := s.repo.GetPendingByInvitee(actor.ObjectID())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.PlaylistInvitationResponse, 0, len(invitations))
+	for _, inv := range invitations {
+		pl, err := s.playlistRepo.GetPlaylistByID(inv.PlaylistID.Hex())
+		if err != nil {
+			// The playlist was deleted since
+			continue
+		}
+		resp = append(resp, mappers.ToPlaylistInvitationResponse(inv, pl.Title))
+	}
+	return resp, nil
+}
+
+// AcceptInvitation makes the invitee a collaborator with the invited role.
+func (s *PlaylistCollaborationService) AcceptInvitation(invitationID string, actor Actor) (*dto.PlaylistInvitationResponse, error) {
+	invitation, pl, err := s.ownInvitation(invitationID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.playlistRepo.UpsertCollaborator(pl.ID, models.PlaylistCollaborator{
+		UserID:  invitation.InviteeID,
+		Role:    invitation.Role,
+		AddedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.respond(invitation, models.InvitationAccepted); err != nil {
+		return nil, err
+	}
+
+	resp := mappers.ToPlaylistInvitationResponse(invitation, pl.Title)
+	return &resp, nil
+}
+
+func (s *PlaylistCollaborationService) DeclineInvitation(invitationID string, actor Actor) (*dto.PlaylistInvitationResponse, error) {
+	invitation, pl, err := s.ownInvitation(invitationID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.respond(invitation, models.InvitationDeclined); err != nil {
+		return nil, err
+	}
+
+	resp := mappers.ToPlaylistInvitationResponse(invitation, pl.Title)
+	return &resp, nil
+}
+
+func (s *PlaylistCollaborationService) SetCollaboratorRole(pl *models.Playlist, userID, role string) error {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil || pl.Collaborator(id) == nil {
+		return ErrCollaboratorNotFound
+	}
+	return s.playlistRepo.UpsertCollaborator(pl.ID, models.PlaylistCollaborator{
+		UserID:  id,
+		Role:    role,
+		AddedAt: pl.Collaborator(id).AddedAt,
+	})
+}
+
+func (s *PlaylistCollaborationService) RemoveCollaborator(pl *models.Playlist, userID string) error {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return ErrCollaboratorNotFound
+	}
+	removed, err := s.playlistRepo.RemoveCollaborator(pl.ID, id)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrCollaboratorNotFound
+	}
+	return nil
+}
+
+// ownInvitation loads a pending invitation addressed to the actor, with its playlist.
+func (s *PlaylistCollaborationService) ownInvitation(invitationID string, actor Actor) (*models.PlaylistInvitation, *models.Playlist, error) {
+	invitation, err := s.repo.GetInvitationByID(invitationID)
+	if err != nil || invitation.InviteeID.Hex() != actor.UserID || invitation.Status != models.InvitationPending {
+		return nil, nil, ErrInvitationNotFound
+	}
+	pl, err := s.playlistRepo.GetPlaylistByID(invitation.PlaylistID.Hex())
+	if err != nil {
+		return nil, nil, ErrInvitationNotFound
+	}
+	return invitation, pl, nil
+}
+
+func (s *PlaylistCollaborationService) respond(invitation *models.PlaylistInvitation, status string) error {
+	now := time.Now()
+	invitation.Status = status
+	invitation.RespondedAt = &now
+	return s.repo.UpdateInvitation(invitation)
+}
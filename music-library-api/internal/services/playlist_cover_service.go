@@ -0,0 +1,290 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IPlaylistCoverService interface {
+	Enqueue(playlistID primitive.ObjectID)
+	EnqueueMissing() (int, error)
+	Regenerate(playlistID primitive.ObjectID) error
+	GetCoverURLs(playlistIDs []primitive.ObjectID) (map[primitive.ObjectID]string, error)
+	DeleteCover(playlistID primitive.ObjectID) error
+	Start()
+}
+
+const (
+	mosaicSize      = 600     // pixels per side of a mosaic
+	maxArtworkBytes = 8 << 20 // largest album artwork downloaded for a mosaic
+	maxArtworkSide  = 4096    // largest width or height decoded, whatever the file size
+)
+
+// PlaylistCoverService generates playlist covers in the background. Playlists
+// waiting for a new cover are kept in a set, so a burst of edits to the same
+// playlist regenerates its cover once.
+type PlaylistCoverService struct {
+	repo         repositories.IPlaylistCoverRepository
+	playlistRepo repositories.IPlaylistRepository
+	likeRepo     repositories.ILikeRepository
+	trackService ITrackService
+	albumService IAlbumService
+	uploader     utils.ImageUploader
+	httpClient   *http.Client
+
+	mu      sync.Mutex
+	pending map[primitive.ObjectID]struct{}
+	wake    chan struct{}
+}
+
+func NewPlaylistCoverService(repo repositories.IPlaylistCoverRepository, playlistRepo repositories.IPlaylistRepository, likeRepo repositories.ILikeRepository, trackService ITrackService, albumService IAlbumService, uploader utils.ImageUploader) IPlaylistCoverService {
+	return &PlaylistCoverService{
+		repo:         repo,
+		playlistRepo: playlistRepo,
+		likeRepo:     likeRepo,
+		trackService: trackService,
+		albumService: albumService,
+		uploader:     uploader,
+		httpClient:   &http.Client{Timeout: 15 * time.Second},
+		pending:      map[primitive.ObjectID]struct{}{},
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Enqueue schedules the cover of a playlist to be regenerated. It never blocks.
+func (s *PlaylistCoverService) Enqueue(playlistID primitive.ObjectID) {
+	s.mu.Lock()
+	s.pending[playlistID] = struct{}{}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// EnqueueMissing schedules every playlist that has no cover at all, such as
+// the playlists created before covers were generated.
+func (s *PlaylistCoverService) EnqueueMissing() (int, error) {
+	ids, err := s.playlistRepo.GetPlaylistIDsWithoutCover()
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		s.Enqueue(id)
+	}
+	return len(ids), nil
+}
+
+// Start runs the worker that regenerates the enqueued covers.
+func (s *PlaylistCoverService) Start() {
+	go func() {
+		for range s.wake {
+			for {
+				id, ok := s.next()
+				if !ok {
+					break
+				}
+				if err := s.Regenerate(id); err != nil {
+					log.Printf("Failed to generate cover of playlist %s: %v", id.Hex(), err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *PlaylistCoverService) next() (primitive.ObjectID, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.pending {
+		delete(s.pending, id)
+		return id, true
+	}
+	return primitive.NilObjectID, false
+}
+
+// Regenerate builds the cover of a playlist from the artwork of its first
+// four distinct albums. Nothing is uploaded when the albums have not changed
+// since the last cover, or when the playlist has an uploaded cover, which
+// always takes precedence.
+func (s *PlaylistCoverService) Regenerate(playlistID primitive.ObjectID) error {
+	pl, err := s.playlistRepo.GetPlaylistByID(playlistID.Hex())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return s.repo.DeleteByPlaylist(playlistID)
+	}
+	if err != nil {
+		return err
+	}
+	if pl.AlbumCover != "" {
+		return nil
+	}
+
+	trackIDs := pl.TrackIDs
+	switch {
+	case pl.Smart != nil:
+		ctx, err := smartContext(s.likeRepo, pl)
+		if err != nil {
+			return err
+		}
+		tracks, err := s.trackService.FindSmartTracks(pl.Smart, ctx)
+		if err != nil {
+			return err
+		}
+		trackIDs = make([]primitive.ObjectID, len(tracks))
+		for i, t := range tracks {
+			trackIDs[i] = t.ID
+		}
+	case pl.IsLikedSongs():
+		entries, err := likedSongsEntries(s.likeRepo, pl.UserID)
+		if err != nil {
+			return err
+		}
+		trackIDs = make([]primitive.ObjectID, len(entries))
+		for i, e := range entries {
+			trackIDs[i] = e.TrackID
+		}
+	}
+
+	albums, err := s.coverAlbums(trackIDs)
+	if err != nil {
+		return err
+	}
+	albumIDs := make([]primitive.ObjectID, len(albums))
+	for i, a := range albums {
+		albumIDs[i] = a.ID
+	}
+
+	current, err := s.repo.GetCover(pl.ID)
+	if err != nil {
+		return err
+	}
+	if current != nil && slices.Equal(current.AlbumIDs, albumIDs) {
+		return nil
+	}
+
+	// With fewer than four albums there is no mosaic to build
+	var url string
+	switch {
+	case len(albums) == 4:
+		if url, err = s.uploadMosaic(pl.ID, albums); err != nil {
+			return err
+		}
+	case len(albums) > 0:
+		url = albums[0].Cover
+	}
+
+	return s.repo.SaveCover(&models.PlaylistCover{PlaylistID: pl.ID, URL: url, AlbumIDs: albumIDs})
+}
+
+func (s *PlaylistCoverService) GetCoverURLs(playlistIDs []primitive.ObjectID) (map[primitive.ObjectID]string, error) {
+	return s.repo.GetCoverURLs(playlistIDs)
+}
+
+func (s *PlaylistCoverService) DeleteCover(playlistID primitive.ObjectID) error {
+	return s.repo.DeleteByPlaylist(playlistID)
+}
+
+// coverAlbums returns up to four distinct albums with artwork, in the order
+// their tracks first appear.
+func (s *PlaylistCoverService) coverAlbums(trackIDs []primitive.ObjectID) ([]*models.Album, error) {
+	tracks, err := s.trackService.GetTracksByIDs(trackIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*models.Track, len(tracks))
+	for _, t := range tracks {
+		byID[t.ID] = t
+	}
+
+	var albums []*models.Album
+	seen := make(map[primitive.ObjectID]bool)
+	for _, id := range trackIDs {
+		t := byID[id]
+		if t == nil || t.AlbumID == nil || seen[*t.AlbumID] {
+			continue
+		}
+		seen[*t.AlbumID] = true
+
+		album, err := s.albumService.GetAlbumByID(t.AlbumID.Hex())
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if album.Cover == "" {
+			continue
+		}
+		if albums = append(albums, album); len(albums) == 4 {
+			break
+		}
+	}
+	return albums, nil
+}
+
+func (s *PlaylistCoverService) uploadMosaic(playlistID primitive.ObjectID, albums []*models.Album) (string, error) {
+	if s.uploader == nil {
+		return "", fmt.Errorf("image uploader is not configured")
+	}
+
+	var images [4]image.Image
+	for i, a := range albums {
+		img, err := s.fetchImage(a.Cover)
+		if err != nil {
+			return "", fmt.Errorf("failed to load the cover of album %s: %w", a.ID.Hex(), err)
+		}
+		images[i] = img
+	}
+
+	data, err := utils.ComposeMosaic(images, mosaicSize)
+	if err != nil {
+		return "", err
+	}
+	// One image per playlist, replaced on every regeneration
+	return s.uploader.UploadImageData(data, playlistID.Hex(), "playlist_covers")
+}
+
+func (s *PlaylistCoverService) fetchImage(url string) (image.Image, error) {
+	resp, err := s.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArtworkBytes))
+	if err != nil {
+		return nil, err
+	}
+	return decodeArtwork(data)
+}
+
+// decodeArtwork checks the declared dimensions before decoding: a small file
+// can declare a huge image, and decoding allocates all of its pixels.
+func decodeArtwork(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width > maxArtworkSide || cfg.Height > maxArtworkSide {
+		return nil, fmt.Errorf("artwork of %dx%d pixels is larger than %dx%d", cfg.Width, cfg.Height, maxArtworkSide, maxArtworkSide)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type fakeCoverRepo struct {
+	repositories.IPlaylistCoverRepository
+	covers map[primitive.ObjectID]*models.PlaylistCover
+}
+
+func (r *fakeCoverRepo) GetCover(playlistID primitive.ObjectID) (*models.PlaylistCover, error) {
+	return r.covers[playlistID], nil
+}
+
+func (r *fakeCoverRepo) GetCoverURLs(playlistIDs []primitive.ObjectID) (map[primitive.ObjectID]string, error) {
+	urls := map[primitive.ObjectID]string{}
+	for _, id := range playlistIDs {
+		if c := r.covers[id]; c != nil && c.URL != "" {
+			urls[id] = c.URL
+		}
+	}
+	return urls, nil
+}
+
+func (r *fakeCoverRepo) SaveCover(cover *models.PlaylistCover) error {
+	r.covers[cover.PlaylistID] = cover
+	return nil
+}
+
+type fakePlaylistRepo struct {
+	repositories.IPlaylistRepository
+	playlist *models.Playlist
+}
+
+func (r *fakePlaylistRepo) GetPlaylistByID(id string) (*models.Playlist, error) {
+	if r.playlist.ID.Hex() != id {
+		return nil, mongo.ErrNoDocuments
+	}
+	return r.playlist, nil
+}
+
+type fakeTrackService struct {
+	ITrackService
+	tracks map[primitive.ObjectID]*models.Track
+}
+
+func (s *fakeTrackService) GetTracksByIDs(ids []primitive.ObjectID) ([]*models.Track, error) {
+	var tracks []*models.Track
+	for _, id := range ids {
+		if t := s.tracks[id]; t != nil {
+			tracks = append(tracks, t)
+		}
+	}
+	return tracks, nil
+}
+
+type fakeAlbumService struct {
+	IAlbumService
+	albums map[string]*models.Album
+}
+
+func (s *fakeAlbumService) GetAlbumByID(id string) (*models.Album, error) {
+	if a := s.albums[id]; a != nil {
+		return a, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+type fakeUploader struct {
+	uploads []string
+	err     error
+}
+
+func (u *fakeUploader) UploadImageData(data []byte, publicID, folder string) (string, error) {
+	if u.err != nil {
+		return "", u.err
+	}
+	u.uploads = append(u.uploads, folder+"/"+publicID)
+	return "https://img.example/" + folder + "/" + publicID + ".jpg", nil
+}
+
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	img.Set(0, 0, color.Black)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPlaylistCoverRegenerate(t *testing.T) {
+	artwork := pngBytes(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(artwork)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		covered []bool // one album per track; whether the album has artwork
+		mosaic  bool
+		want    func(albums []*models.Album, playlistID primitive.ObjectID) string
+	}{
+		{
+			name:    "single covered album uses its artwork",
+			covered: []bool{true},
+			want: func(albums []*models.Album, _ primitive.ObjectID) string {
+				return albums[0].Cover
+			},
+		},
+		{
+			name:    "albums without artwork are skipped",
+			covered: []bool{false, true, false},
+			want: func(albums []*models.Album, _ primitive.ObjectID) string {
+				return albums[1].Cover
+			},
+		},
+		{
+			name:    "four covered albums build a mosaic",
+			covered: []bool{true, true, false, true, true},
+			mosaic:  true,
+			want: func(_ []*models.Album, playlistID primitive.ObjectID) string {
+				return "https://img.example/playlist_covers/" + playlistID.Hex() + ".jpg"
+			},
+		},
+		{
+			name:    "no covered album",
+			covered: []bool{false, false},
+			want: func([]*models.Album, primitive.ObjectID) string {
+				return ""
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := &models.Playlist{}
+			pl.ID = primitive.NewObjectID()
+			tracks := &fakeTrackService{tracks: map[primitive.ObjectID]*models.Track{}}
+			albumService := &fakeAlbumService{albums: map[string]*models.Album{}}
+
+			var albums []*models.Album
+			for i, covered := range tt.covered {
+				album := &models.Album{}
+				album.ID = primitive.NewObjectID()
+				if covered {
+					album.Cover = server.URL + "/" + album.ID.Hex()
+				}
+				albums = append(albums, album)
+				albumService.albums[album.ID.Hex()] = album
+
+				track := &models.Track{AlbumID: &album.ID}
+				track.ID = primitive.NewObjectID()
+				tracks.tracks[track.ID] = track
+				pl.TrackIDs = append(pl.TrackIDs, track.ID)
+				if i == 0 {
+					// A second track of the same album must not count twice
+					again := &models.Track{AlbumID: &album.ID}
+					again.ID = primitive.NewObjectID()
+					tracks.tracks[again.ID] = again
+					pl.TrackIDs = append(pl.TrackIDs, again.ID)
+				}
+			}
+
+			repo := &fakeCoverRepo{covers: map[primitive.ObjectID]*models.PlaylistCover{}}
+			uploader := &fakeUploader{}
+			svc := NewPlaylistCoverService(repo, &fakePlaylistRepo{playlist: pl}, nil, tracks, albumService, uploader)
+
+			if err := svc.Regenerate(pl.ID); err != nil {
+				t.Fatalf("Regenerate() error = %v", err)
+			}
+			if got := len(uploader.uploads) > 0; got != tt.mosaic {
+				t.Errorf("uploaded a mosaic = %v, want %v", got, tt.mosaic)
+			}
+
+			loaded := &models.Playlist{}
+			loaded.ID = pl.ID
+			if err := (&PlaylistService{coverService: svc}).loadCovers(loaded); err != nil {
+				t.Fatalf("loadCovers() error = %v", err)
+			}
+			if want := tt.want(albums, pl.ID); loaded.GeneratedCover != want {
+				t.Errorf("GeneratedCover = %q, want %q", loaded.GeneratedCover, want)
+			}
+
+			// The same albums again upload nothing new
+			uploads := len(uploader.uploads)
+			if err := svc.Regenerate(pl.ID); err != nil {
+				t.Fatalf("second Regenerate() error = %v", err)
+			}
+			if len(uploader.uploads) != uploads {
+				t.Errorf("unchanged albums uploaded %d more mosaics", len(uploader.uploads)-uploads)
+			}
+		})
+	}
+}
+
+func TestDecodeArtwork(t *testing.T) {
+	encode := func(w, h int) []byte {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"small", encode(8, 8), false},
+		{"largest allowed", encode(maxArtworkSide, 1), false},
+		{"too wide", encode(maxArtworkSide+1, 1), true},
+		{"too tall", encode(1, maxArtworkSide+1), true},
+		{"not an image", []byte("<html>"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := decodeArtwork(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeArtwork() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && img == nil {
+				t.Errorf("decodeArtwork() returned no image")
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"errors"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IPlaylistFolderService interface {
+	CreateFolder(actor Actor, req *dto.CreatePlaylistFolderRequest) (*models.PlaylistFolder, error)
+	RenameFolder(actor Actor, id, name string) (*models.PlaylistFolder, error)
+	MoveFolder(actor Actor, id string, req *dto.MovePlaylistFolderRequest) (*models.PlaylistFolder, error)
+	DeleteFolder(actor Actor, id string) error
+	MovePlaylist(actor Actor, pl *models.Playlist, req *dto.MovePlaylistToFolderRequest) error
+	GetTree(actor Actor) (*dto.PlaylistFolderTreeResponse, error)
+}
+
+var (
+	ErrPlaylistFolderNotFound = errors.New("playlist folder not found")
+	ErrInvalidFolderMove      = errors.New("a folder cannot be moved into itself or one of its subfolders")
+)
+
+type PlaylistFolderService struct {
+	repo            repositories.IPlaylistFolderRepository
+	playlistService IPlaylistService
+	trackService    ITrackService
+	authz           IAuthorizationService
+}
+
+func NewPlaylistFolderService(repo repositories.IPlaylistFolderRepository, playlistService IPlaylistService, trackService ITrackService, authz IAuthorizationService) IPlaylistFolderService {
+	return &PlaylistFolderService{
+		repo:            repo,
+		playlistService: playlistService,
+		trackService:    trackService,
+		authz:           authz,
+	}
+}
+
+func (s *PlaylistFolderService) CreateFolder(actor Actor, req *dto.CreatePlaylistFolderRequest) (*models.PlaylistFolder, error) {
+	folders, err := s.repo.GetFoldersByUser(actor.ObjectID())
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := parentFolderID(folders, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := &models.PlaylistFolder{UserID: actor.ObjectID(), Name: req.Name, ParentID: parentID}
+	if err := s.repo.CreateFolder(folder); err != nil {
+		return nil, err
+	}
+	return folder, s.place(folders, folder, req.Position)
+}
+
+func (s *PlaylistFolderService) RenameFolder(actor Actor, id, name string) (*models.PlaylistFolder, error) {
+	folders, err := s.repo.GetFoldersByUser(actor.ObjectID())
+	if err != nil {
+		return nil, err
+	}
+	folder, err := findFolder(folders, id)
+	if err != nil {
+		return nil, err
+	}
+
+	folder.Name = name
+	return folder, s.repo.UpdateFolder(folder)
+}
+
+// MoveFolder moves a folder, with its content, under another parent and/or
+// to another position among its siblings.
+func (s *PlaylistFolderService) MoveFolder(actor Actor, id string, req *dto.MovePlaylistFolderRequest) (*models.PlaylistFolder, error) {
+	folders, err := s.repo.GetFoldersByUser(actor.ObjectID())
+	if err != nil {
+		return nil, err
+	}
+	folder, err := findFolder(folders, id)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := parentFolderID(folders, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk up from the new parent: meeting the folder means a cycle
+	for p := parentID; p != nil; {
+		if *p == folder.ID {
+			return nil, ErrInvalidFolderMove
+		}
+		parent, _ := findFolder(folders, p.Hex())
+		if parent == nil {
+			break
+		}
+		p = parent.ParentID
+	}
+
+	folder.ParentID = parentID
+	return folder, s.place(folders, folder, req.Position)
+}
+
+// DeleteFolder deletes a folder but not its content: subfolders and
+// playlists move up to the parent folder, or to the top level.
+func (s *PlaylistFolderService) DeleteFolder(actor Actor, id string) error {
+	folders, err := s.repo.GetFoldersByUser(actor.ObjectID())
+	if err != nil {
+		return err
+	}
+	folder, err := findFolder(folders, id)
+	if err != nil {
+		return err
+	}
+
+	var siblings []primitive.ObjectID
+	for _, f := range folders {
+		if f.ID != folder.ID && sameFolder(f.ParentID, folder.ParentID) {
+			siblings = append(siblings, f.ID)
+		}
+	}
+	for _, f := range folders {
+		if sameFolder(f.ParentID, &folder.ID) {
+			f.ParentID = folder.ParentID
+			if err := s.repo.UpdateFolder(f); err != nil {
+				return err
+			}
+			siblings = append(siblings, f.ID)
+		}
+	}
+	if err := s.repo.SetPositions(siblings); err != nil {
+		return err
+	}
+
+	if folder.ParentID != nil && len(folder.PlaylistIDs) > 0 {
+		parent, err := findFolder(folders, folder.ParentID.Hex())
+		if err == nil {
+			parent.PlaylistIDs = append(parent.PlaylistIDs, folder.PlaylistIDs...)
+			if err := s.repo.UpdateFolder(parent); err != nil {
+				return err
+			}
+		}
+	}
+	return s.repo.DeleteFolder(folder)
+}
+
+// MovePlaylist files a playlist into one of the actor's folders, or takes it
+// out of its folder when FolderID is empty.
+func (s *PlaylistFolderService) MovePlaylist(actor Actor, pl *models.Playlist, req *dto.MovePlaylistToFolderRequest) error {
+	folders, err := s.repo.GetFoldersByUser(actor.ObjectID())
+	if err != nil {
+		return err
+	}
+	var target *models.PlaylistFolder
+	if req.FolderID != "" {
+		if target, err = findFolder(folders, req.FolderID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.RemovePlaylist(actor.ObjectID(), pl.ID); err != nil {
+		return err
+	}
+	if target == nil {
+		return nil
+	}
+
+	target.PlaylistIDs = slices.DeleteFunc(target.PlaylistIDs, func(id primitive.ObjectID) bool { return id == pl.ID })
+	at := len(target.PlaylistIDs)
+	if req.Position != nil {
+		at = min(*req.Position, at)
+	}
+	target.PlaylistIDs = slices.Insert(target.PlaylistIDs, at, pl.ID)
+	return s.repo.UpdateFolder(target)
+}
+
+// GetTree returns the actor's folders with their playlists, followed by the
+// playlists the actor owns or collaborates on that are in no folder.
+func (s *PlaylistFolderService) GetTree(actor Actor) (*dto.PlaylistFolderTreeResponse, error) {
+	folders, err := s.repo.GetFoldersByUser(actor.ObjectID())
+	if err != nil {
+		return nil, err
+	}
+
+	// A limit of 0 lists every playlist
+	member, err := s.playlistService.GetPlaylists(1, 0, models.PlaylistFilter{MemberID: actor.UserID})
+	if err != nil {
+		return nil, err
+	}
+	playlists := make(map[primitive.ObjectID]*models.Playlist, len(member))
+	for _, pl := range member {
+		playlists[pl.ID] = pl
+	}
+
+	// Folders can also hold playlists the actor only follows
+	var others []primitive.ObjectID
+	for _, f := range folders {
+		for _, id := range f.PlaylistIDs {
+			if playlists[id] == nil {
+				others = append(others, id)
+			}
+		}
+	}
+	if len(others) > 0 {
+		followed, err := s.playlistService.GetPlaylistsByIDs(others)
+		if err != nil {
+			return nil, err
+		}
+		for _, pl := range followed {
+			if s.authz.PlaylistAccess(pl, Actor{UserID: actor.UserID, Role: actor.Role}) != "" {
+				playlists[pl.ID] = pl
+			}
+		}
+	}
+
+	durations, err := s.playlistDurations(playlists)
+	if err != nil {
+		return nil, err
+	}
+
+	placed := make(map[primitive.ObjectID]bool)
+	var build func(parentID *primitive.ObjectID) []dto.PlaylistFolderNode
+	build = func(parentID *primitive.ObjectID) []dto.PlaylistFolderNode {
+		nodes := []dto.PlaylistFolderNode{}
+		for _, f := range folders {
+			if !sameFolder(f.ParentID, parentID) {
+				continue
+			}
+			node := dto.PlaylistFolderNode{ID: f.ID.Hex(), Name: f.Name, Playlists: []dto.PlaylistTreeItem{}}
+			for _, id := range f.PlaylistIDs {
+				pl := playlists[id]
+				if pl == nil || placed[id] {
+					continue
+				}
+				placed[id] = true
+				node.Playlists = append(node.Playlists, mappers.ToPlaylistTreeItem(pl, durations[id]))
+				node.PlaylistCount++
+				node.TotalDuration += durations[id]
+			}
+			node.Folders = build(&f.ID)
+			for _, child := range node.Folders {
+				node.PlaylistCount += child.PlaylistCount
+				node.TotalDuration += child.TotalDuration
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+
+	tree := &dto.PlaylistFolderTreeResponse{Folders: build(nil), Playlists: []dto.PlaylistTreeItem{}}
+	for _, node := range tree.Folders {
+		tree.PlaylistCount += node.PlaylistCount
+		tree.TotalDuration += node.TotalDuration
+	}
+	for _, pl := range member {
+		if placed[pl.ID] {
+			continue
+		}
+		tree.Playlists = append(tree.Playlists, mappers.ToPlaylistTreeItem(pl, durations[pl.ID]))
+		tree.PlaylistCount++
+		tree.TotalDuration += durations[pl.ID]
+	}
+	return tree, nil
+}
+
+// playlistDurations sums the track durations of each playlist, loading every
+// track once.
+func (s *PlaylistFolderService) playlistDurations(playlists map[primitive.ObjectID]*models.Playlist) (map[primitive.ObjectID]int, error) {
+	seen := make(map[primitive.ObjectID]bool)
+	var trackIDs []primitive.ObjectID
+	for _, pl := range playlists {
+		for _, e := range pl.Entries {
+			if !seen[e.TrackID] {
+				seen[e.TrackID] = true
+				trackIDs = append(trackIDs, e.TrackID)
+			}
+		}
+	}
+
+	tracks, err := s.trackService.GetTracksByIDs(trackIDs)
+	if err != nil {
+		return nil, err
+	}
+	trackDurations := make(map[primitive.ObjectID]int, len(tracks))
+	for _, t := range tracks {
+		trackDurations[t.ID] = t.Duration
+	}
+
+	durations := make(map[primitive.ObjectID]int, len(playlists))
+	for id, pl := range playlists {
+		for _, e := range pl.Entries {
+			durations[id] += trackDurations[e.TrackID]
+		}
+	}
+	return durations, nil
+}
+
+// place puts folder at position among its siblings (last by default) and
+// saves it with the renumbered siblings.
+func (s *PlaylistFolderService) place(folders []*models.PlaylistFolder, folder *models.PlaylistFolder, position *int) error {
+	var siblings []primitive.ObjectID
+	for _, f := range folders {
+		if f.ID != folder.ID && sameFolder(f.ParentID, folder.ParentID) {
+			siblings = append(siblings, f.ID)
+		}
+	}
+	at := len(siblings)
+	if position != nil {
+		at = min(*position, at)
+	}
+	siblings = slices.Insert(siblings, at, folder.ID)
+
+	folder.Position = at
+	if err := s.repo.UpdateFolder(folder); err != nil {
+		return err
+	}
+	return s.repo.SetPositions(siblings)
+}
+
+func findFolder(folders []*models.PlaylistFolder, id string) (*models.PlaylistFolder, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrPlaylistFolderNotFound
+	}
+	for _, f := range folders {
+		if f.ID == objID {
+			return f, nil
+		}
+	}
+	return nil, ErrPlaylistFolderNotFound
+}
+
+// parentFolderID resolves a parent folder ID from a request; empty is the top level.
+func parentFolderID(folders []*models.PlaylistFolder, id string) (*primitive.ObjectID, error) {
+	if id == "" {
+		return nil, nil
+	}
+	parent, err := findFolder(folders, id)
+	if err != nil {
+		return nil, err
+	}
+	return &parent.ID, nil
+}
+
+func sameFolder(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
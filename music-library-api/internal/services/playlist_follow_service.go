@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IPlaylistFollowService interface {
+	Follow(pl *models.Playlist, actor Actor) (*dto.FollowPlaylistResponse, error)
+	Unfollow(pl *models.Playlist, actor Actor) (*dto.FollowPlaylistResponse, error)
+	GetFollowedPlaylists(actor Actor, page, limit int) ([]*models.Playlist, int64, error)
+}
+
+var ErrFollowOwnPlaylist = errors.New("you cannot follow your own playlist")
+
+type PlaylistFollowService struct {
+	repo            repositories.IPlaylistFollowRepository
+	playlistService IPlaylistService
+	authz           IAuthorizationService
+}
+
+func NewPlaylistFollowService(repo repositories.IPlaylistFollowRepository, playlistService IPlaylistService, authz IAuthorizationService) IPlaylistFollowService {
+	return &PlaylistFollowService{
+		repo:            repo,
+		playlistService: playlistService,
+		authz:           authz,
+	}
+}
+
+func (s *PlaylistFollowService) Follow(pl *models.Playlist, actor Actor) (*dto.FollowPlaylistResponse, error) {
+	if pl.UserID == actor.ObjectID() {
+		return nil, ErrFollowOwnPlaylist
+	}
+	if err := s.repo.Follow(pl.ID, actor.ObjectID()); err != nil {
+		return nil, err
+	}
+	return s.followResponse(pl, true)
+}
+
+func (s *PlaylistFollowService) Unfollow(pl *models.Playlist, actor Actor) (*dto.FollowPlaylistResponse, error) {
+	if err := s.repo.Unfollow(pl.ID, actor.ObjectID()); err != nil {
+		return nil, err
+	}
+	return s.followResponse(pl, false)
+}
+
+// GetFollowedPlaylists lists the playlists the actor follows, most recently
+// followed first. Playlists that were deleted or made private since are
+// skipped, so a page can be shorter than limit.
+func (s *PlaylistFollowService) GetFollowedPlaylists(actor Actor, page, limit int) ([]*models.Playlist, int64, error) {
+	ids, err := s.repo.GetFollowedPlaylistIDs(actor.ObjectID(), page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.repo.CountFollowed(actor.ObjectID())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	playlists, err := s.playlistService.GetPlaylistsByIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	visible := playlists[:0]
+	for _, pl := range playlists {
+		// Without a share token now, the follow alone does not grant access
+		if s.authz.PlaylistAccess(pl, Actor{UserID: actor.UserID, Role: actor.Role}) != "" {
+			visible = append(visible, pl)
+		}
+	}
+	return visible, total, nil
+}
+
+func (s *PlaylistFollowService) followResponse(pl *models.Playlist, following bool) (*dto.FollowPlaylistResponse, error) {
+	counts, err := s.repo.CountFollowers([]primitive.ObjectID{pl.ID})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.FollowPlaylistResponse{Following: following, FollowerCount: counts[pl.ID]}, nil
+}
@@ -0,0 +1,443 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IPlaylistImportService interface {
+	ImportPlaylist(userID, fileName string, content []byte, title string) (*dto.PlaylistImportResponse, error)
+	GetImport(id, userID string) (*dto.PlaylistImportResponse, error)
+	ResolveImport(id, userID string, resolutions []dto.PlaylistImportResolution) (*dto.PlaylistImportResponse, error)
+}
+
+var (
+	ErrPlaylistImportNotFound  = errors.New("playlist import not found")
+	ErrEmptyPlaylistImport     = errors.New("the playlist file has no entries")
+	ErrTooManyImportEntries    = fmt.Errorf("a playlist file may have at most %d entries", maxImportEntries)
+	ErrInvalidImportResolution = errors.New("invalid resolution")
+)
+
+const (
+	maxImportEntries        = 5000
+	maxImportCandidates     = 5
+	importDurationTolerance = 3 // seconds
+)
+
+// importTrackURL finds our own track URLs, e.g. in a re-imported export
+var importTrackURL = regexp.MustCompile(`/api/tracks/([0-9a-fA-F]{24})(?:/|$|\?)`)
+
+type PlaylistImportService struct {
+	repo         repositories.IPlaylistImportRepository
+	playlistRepo repositories.IPlaylistRepository
+	versionRepo  repositories.IPlaylistVersionRepository
+	trackService ITrackService
+	coverService IPlaylistCoverService
+}
+
+func NewPlaylistImportService(repo repositories.IPlaylistImportRepository, playlistRepo repositories.IPlaylistRepository, versionRepo repositories.IPlaylistVersionRepository, trackService ITrackService, coverService IPlaylistCoverService) IPlaylistImportService {
+	return &PlaylistImportService{
+		repo:         repo,
+		playlistRepo: playlistRepo,
+		versionRepo:  versionRepo,
+		trackService: trackService,
+		coverService: coverService,
+	}
+}
+
+// ImportPlaylist parses a playlist file, matches its entries against the
+// library and creates a playlist from the matched ones, in file order.
+func (s *PlaylistImportService) ImportPlaylist(userID, fileName string, content []byte, title string) (*dto.PlaylistImportResponse, error) {
+	parsed, err := utils.ParsePlaylistFile(fileName, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.Entries) == 0 {
+		return nil, ErrEmptyPlaylistImport
+	}
+	if len(parsed.Entries) > maxImportEntries {
+		return nil, ErrTooManyImportEntries
+	}
+
+	entries, err := s.match(parsed.Entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match playlist entries: %w", err)
+	}
+
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if title = strings.TrimSpace(title); title == "" {
+		title = parsed.Title
+	}
+	if title == "" {
+		title = strings.TrimSuffix(fileName, path.Ext(fileName))
+	}
+
+	playlist := &models.Playlist{UserID: userIDObj, Title: title, Entries: []models.PlaylistEntry{}}
+	now := time.Now()
+	for i := range entries {
+		if entries[i].TrackID == nil {
+			continue
+		}
+		entry := models.PlaylistEntry{ID: primitive.NewObjectID(), TrackID: *entries[i].TrackID, AddedAt: now, AddedBy: &userIDObj}
+		playlist.Entries = append(playlist.Entries, entry)
+		entries[i].EntryID = &entry.ID
+	}
+	if _, err := s.playlistRepo.CreatePlaylist(playlist); err != nil {
+		return nil, err
+	}
+	recordPlaylistVersion(s.versionRepo, playlist, userID, nil)
+	s.coverService.Enqueue(playlist.ID)
+
+	report := &models.PlaylistImport{
+		UserID:     userIDObj,
+		PlaylistID: playlist.ID,
+		FileName:   fileName,
+		Format:     parsed.Format,
+		Entries:    entries,
+	}
+	if err := s.repo.CreateImport(report); err != nil {
+		return nil, err
+	}
+	return s.buildResponse(report)
+}
+
+func (s *PlaylistImportService) GetImport(id, userID string) (*dto.PlaylistImportResponse, error) {
+	report, err := s.getOwnImport(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildResponse(report)
+}
+
+// ResolveImport applies the user's choices for ambiguous or unmatched entries.
+// Each chosen track is inserted next to its neighbours from the file, so the
+// playlist keeps the file order even if it was edited in between.
+func (s *PlaylistImportService) ResolveImport(id, userID string, resolutions []dto.PlaylistImportResolution) (*dto.PlaylistImportResponse, error) {
+	report, err := s.getOwnImport(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolutions = slices.Clone(resolutions)
+	sort.SliceStable(resolutions, func(i, j int) bool { return *resolutions[i].Index < *resolutions[j].Index })
+
+	trackIDs := make([]primitive.ObjectID, len(resolutions))
+	var picked []primitive.ObjectID
+	for i, r := range resolutions {
+		index := *r.Index
+		if index >= len(report.Entries) {
+			return nil, fmt.Errorf("%w: entry %d does not exist", ErrInvalidImportResolution, index)
+		}
+		if i > 0 && *resolutions[i-1].Index == index {
+			return nil, fmt.Errorf("%w: entry %d is resolved twice", ErrInvalidImportResolution, index)
+		}
+		if report.Entries[index].Status == models.ImportMatched {
+			return nil, fmt.Errorf("%w: entry %d is already matched", ErrInvalidImportResolution, index)
+		}
+		if r.TrackID == "" {
+			continue
+		}
+		trackIDs[i], err = primitive.ObjectIDFromHex(r.TrackID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid track ID %q", ErrInvalidImportResolution, r.TrackID)
+		}
+		picked = append(picked, trackIDs[i])
+	}
+
+	missing, err := s.trackService.FindMissingIDs(picked)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: these track IDs do not exist: %v", ErrInvalidImportResolution, missing)
+	}
+
+	playlist, err := s.playlistRepo.GetPlaylistByID(report.PlaylistID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	version := playlist.Version
+
+	now := time.Now()
+	for i, r := range resolutions {
+		entry := &report.Entries[*r.Index]
+		if r.TrackID == "" {
+			entry.Status = models.ImportUnmatched
+			entry.Candidates = nil
+			continue
+		}
+
+		placed := models.PlaylistEntry{ID: primitive.NewObjectID(), TrackID: trackIDs[i], AddedAt: now, AddedBy: &report.UserID}
+		at := importPosition(report, playlist, *r.Index)
+		playlist.Entries = slices.Insert(playlist.Entries, at, placed)
+
+		entry.Status = models.ImportMatched
+		entry.Method = models.ImportMatchManual
+		entry.TrackID = &placed.TrackID
+		entry.EntryID = &placed.ID
+		entry.Candidates = nil
+	}
+
+	ok, err := s.playlistRepo.UpdatePlaylistVersion(playlist, version)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPlaylistVersionConflict
+	}
+	recordPlaylistVersion(s.versionRepo, playlist, userID, nil)
+	s.coverService.Enqueue(playlist.ID)
+	if err := s.repo.UpdateImport(report); err != nil {
+		return nil, err
+	}
+	return s.buildResponse(report)
+}
+
+func (s *PlaylistImportService) getOwnImport(id, userID string) (*models.PlaylistImport, error) {
+	report, err := s.repo.GetImportByID(id)
+	if err != nil || report.UserID.Hex() != userID {
+		return nil, ErrPlaylistImportNotFound
+	}
+	return report, nil
+}
+
+// importPosition finds where the entry at index goes: after the closest
+// earlier file entry still in the playlist, else before the closest later one,
+// else at the end.
+func importPosition(report *models.PlaylistImport, playlist *models.Playlist, index int) int {
+	positions := make(map[primitive.ObjectID]int, len(playlist.Entries))
+	for i, e := range playlist.Entries {
+		positions[e.ID] = i
+	}
+
+	for i := index - 1; i >= 0; i-- {
+		if id := report.Entries[i].EntryID; id != nil {
+			if at, ok := positions[*id]; ok {
+				return at + 1
+			}
+		}
+	}
+	for i := index + 1; i < len(report.Entries); i++ {
+		if id := report.Entries[i].EntryID; id != nil {
+			if at, ok := positions[*id]; ok {
+				return at
+			}
+		}
+	}
+	return len(playlist.Entries)
+}
+
+// match resolves file entries against the library. Each entry is tried by, in
+// order of confidence: one of our track URLs, the uploaded file name, artist
+// and title, then title with a duration within tolerance.
+func (s *PlaylistImportService) match(entries []utils.ImportEntry) ([]models.PlaylistImportEntry, error) {
+	var urlIDs []primitive.ObjectID
+	var names, titles []string
+	for _, e := range entries {
+		if m := importTrackURL.FindStringSubmatch(e.Location); m != nil {
+			id, _ := primitive.ObjectIDFromHex(m[1])
+			urlIDs = append(urlIDs, id)
+		}
+		if name := utils.LocationBaseName(e.Location); name != "" {
+			names = append(names, name)
+		}
+		if title := importTitle(e); title != "" {
+			titles = append(titles, title)
+		}
+	}
+
+	byURL, err := s.trackService.GetTracksByIDs(urlIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*models.Track, len(byURL))
+	for _, t := range byURL {
+		byID[t.ID] = t
+	}
+
+	byName, err := s.trackService.FindTracksByFileNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	titleTracks, err := s.trackService.FindTracksByTitles(titles)
+	if err != nil {
+		return nil, err
+	}
+	byTitle := map[string][]*models.Track{}
+	for _, t := range titleTracks {
+		key := utils.TrimAudioExtension(t.Search.Title)
+		byTitle[key] = append(byTitle[key], t)
+	}
+
+	result := make([]models.PlaylistImportEntry, len(entries))
+	for i, e := range entries {
+		r := models.PlaylistImportEntry{
+			Index:    i,
+			Location: e.Location,
+			Title:    e.Title,
+			Artist:   e.Artist,
+			Album:    e.Album,
+			Duration: e.Duration,
+			Status:   models.ImportUnmatched,
+		}
+
+		if m := importTrackURL.FindStringSubmatch(e.Location); m != nil {
+			id, _ := primitive.ObjectIDFromHex(m[1])
+			if t, ok := byID[id]; ok {
+				setImportMatch(&r, []*models.Track{t}, models.ImportMatchPath)
+				result[i] = r
+				continue
+			}
+		}
+
+		artist := importArtist(e)
+		if name := utils.LocationBaseName(e.Location); name != "" {
+			candidates := narrowImportCandidates(byName[utils.FoldDiacritics(name)], artist, e.Duration)
+			if setImportMatch(&r, candidates, models.ImportMatchBasename) {
+				result[i] = r
+				continue
+			}
+		}
+
+		candidates := byTitle[utils.FoldDiacritics(importTitle(e))]
+		if artist != "" {
+			withArtist := filterTracks(candidates, func(t *models.Track) bool { return t.Search.Artist == artist })
+			if setImportMatch(&r, narrowImportCandidates(withArtist, "", e.Duration), models.ImportMatchArtistTitle) {
+				result[i] = r
+				continue
+			}
+		}
+		if e.Duration > 0 {
+			setImportMatch(&r, filterTracks(candidates, durationWithin(e.Duration)), models.ImportMatchDuration)
+		} else if len(candidates) > 0 {
+			// A bare title match is too weak to pick a track on its own
+			r.Status = models.ImportAmbiguous
+			r.Method = models.ImportMatchDuration
+			r.Candidates = candidateIDs(candidates)
+		}
+		result[i] = r
+	}
+	return result, nil
+}
+
+// setImportMatch records the outcome for the candidates left after narrowing
+// and reports whether anything was found.
+func setImportMatch(r *models.PlaylistImportEntry, candidates []*models.Track, method string) bool {
+	switch len(candidates) {
+	case 0:
+		return false
+	case 1:
+		r.Status = models.ImportMatched
+		r.TrackID = &candidates[0].ID
+	default:
+		r.Status = models.ImportAmbiguous
+		r.Candidates = candidateIDs(candidates)
+	}
+	r.Method = method
+	return true
+}
+
+// narrowImportCandidates keeps the candidates with the same artist, then those
+// within the duration tolerance, whenever that still leaves at least one.
+func narrowImportCandidates(candidates []*models.Track, artist string, duration int) []*models.Track {
+	if len(candidates) > 1 && artist != "" {
+		if narrowed := filterTracks(candidates, func(t *models.Track) bool { return t.Search.Artist == artist }); len(narrowed) > 0 {
+			candidates = narrowed
+		}
+	}
+	if len(candidates) > 1 && duration > 0 {
+		if narrowed := filterTracks(candidates, durationWithin(duration)); len(narrowed) > 0 {
+			candidates = narrowed
+		}
+	}
+	return candidates
+}
+
+func durationWithin(duration int) func(t *models.Track) bool {
+	return func(t *models.Track) bool {
+		diff := t.Duration - duration
+		return diff >= -importDurationTolerance && diff <= importDurationTolerance
+	}
+}
+
+func filterTracks(tracks []*models.Track, keep func(t *models.Track) bool) []*models.Track {
+	var out []*models.Track
+	seen := map[primitive.ObjectID]bool{}
+	for _, t := range tracks {
+		if !seen[t.ID] && keep(t) {
+			seen[t.ID] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func candidateIDs(tracks []*models.Track) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, min(len(tracks), maxImportCandidates))
+	for _, t := range tracks[:min(len(tracks), maxImportCandidates)] {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+// importTitle is the entry's title, or the title part of an
+// "Artist - Title.mp3" file name when the file carries no metadata.
+func importTitle(e utils.ImportEntry) string {
+	if e.Title != "" {
+		return e.Title
+	}
+	stem := utils.TrimAudioExtension(utils.LocationBaseName(e.Location))
+	if _, title, ok := strings.Cut(stem, " - "); ok {
+		return strings.TrimSpace(title)
+	}
+	return stem
+}
+
+// importArtist is the accent-folded artist, taken from the file name like importTitle.
+func importArtist(e utils.ImportEntry) string {
+	if e.Artist != "" || e.Title != "" {
+		return utils.FoldDiacritics(e.Artist)
+	}
+	stem := utils.TrimAudioExtension(utils.LocationBaseName(e.Location))
+	if artist, _, ok := strings.Cut(stem, " - "); ok {
+		return utils.FoldDiacritics(strings.TrimSpace(artist))
+	}
+	return ""
+}
+
+func (s *PlaylistImportService) buildResponse(report *models.PlaylistImport) (*dto.PlaylistImportResponse, error) {
+	var ids []primitive.ObjectID
+	for _, e := range report.Entries {
+		ids = append(ids, e.Candidates...)
+	}
+
+	tracks, err := s.trackService.GetTracksByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve candidate tracks: %w", err)
+	}
+	byID := make(map[primitive.ObjectID]*models.Track, len(tracks))
+	for _, t := range tracks {
+		byID[t.ID] = t
+	}
+
+	resp := mappers.ToPlaylistImportResponse(report, byID)
+	return &resp, nil
+}
@@ -1,99 +1,236 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
 	"music-library-api/internal/models"
 	"music-library-api/internal/repositories"
 	"music-library-api/pkg/utils"
+	"slices"
 	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type IPlaylistService interface {
 	GetPlaylistByID(id string) (*models.Playlist, error)
-	GetPlaylists(page, limit int, userID string) ([]*models.Playlist, error)
-	CountPlaylists(userID string) (int64, error)
+	GetExpandedPlaylist(id string) (*models.ExpandedPlaylist, error)
+	GetPlaylistsByIDs(ids []primitive.ObjectID) ([]*models.Playlist, error)
+	GetPlaylists(page, limit int, filter models.PlaylistFilter) ([]*models.Playlist, error)
+	GetPlaylistsByCursor(cursor *utils.Cursor, limit int, filter models.PlaylistFilter) ([]*models.Playlist, utils.CursorPage, error)
+	CountPlaylists(filter models.PlaylistFilter) (int64, error)
 	CreatePlaylist(playlist *models.Playlist) (*models.Playlist, error)
-	UpdatePlaylist(playlist *models.Playlist) (*models.Playlist, error)
 	DeletePlaylist(id string) error
-	StreamPlaylistM3U(id string, trackStreamBaseURL string) (string, error)
+	ExportPlaylist(id, format, baseURL string) (string, error)
 	CreatePlaylistFormData(userID string, req *dto.CreatePlaylistRequest) (*models.Playlist, error)
-	UpdatePlaylistFormData(id string, req *dto.UpdatePlaylistRequest) (*models.Playlist, error)
+	UpdatePlaylistFormData(id, userID string, expected *int64, req *dto.UpdatePlaylistRequest) (*models.Playlist, error)
+	InsertEntries(id, userID string, version int64, trackIDs []string, position *int) (*models.Playlist, error)
+	MoveEntry(id, userID string, version int64, entryID string, position int) (*models.Playlist, error)
+	RemoveEntry(id, userID string, version int64, entryID string) (*models.Playlist, error)
+	ReverseEntries(id, userID string, version int64) (*models.Playlist, error)
+	SortEntries(id, userID string, version int64, field string, desc bool) (*models.Playlist, error)
+	BackfillPlaylistEntries() (int64, error)
+	BackfillPlaylistVisibility() (int64, error)
+	EnsureIndexes() error
+	CreateSmartPlaylist(userID string, req *dto.CreateSmartPlaylistRequest) (*models.Playlist, error)
+	UpdateSmartPlaylist(id, userID string, version int64, req *dto.UpdateSmartPlaylistRequest) (*models.Playlist, error)
+	CreateShareLink(id, userID string, expiresAt *time.Time) (*models.Playlist, *models.PlaylistShareLink, error)
+	RevokeShareLink(id, linkID string) error
+	ForkPlaylist(source *models.Playlist, userID string, req *dto.ForkPlaylistRequest) (*models.Playlist, error)
+	PullFork(id, userID string, version int64, source *models.Playlist) (*models.Playlist, []primitive.ObjectID, []primitive.ObjectID, error)
+	RestoreVersion(id, userID string, version, number int64) (*models.Playlist, error)
+	GetLikedSongs(userID string) (*models.Playlist, error)
+	RefreshLikedSongs(userID primitive.ObjectID) error
 }
 
+var (
+	ErrPlaylistVersionConflict = errors.New("playlist was modified by someone else, reload it and try again")
+	ErrPlaylistEntryNotFound   = errors.New("playlist entry not found")
+	ErrInvalidPlaylistPosition = errors.New("position is out of range")
+	ErrInvalidTrackIDs         = errors.New("invalid track IDs")
+
+	ErrUnsupportedPlaylistFormat = errors.New("unsupported playlist format")
+
+	ErrInvalidSmartRules     = errors.New("invalid smart playlist rules")
+	ErrSmartPlaylistReadOnly = errors.New("the tracks of a smart playlist come from its rules and cannot be edited")
+	ErrNotSmartPlaylist      = errors.New("playlist is not a smart playlist")
+
+	ErrNotForked = errors.New("playlist is not a fork")
+
+	ErrLikedSongsReadOnly = errors.New("the tracks of Liked Songs come from your likes and cannot be edited, and it cannot be deleted")
+
+	ErrShareLinkNotFound = errors.New("share link not found")
+	ErrInvalidShareLink  = errors.New("invalid share link")
+)
+
 type PlaylistService struct {
 	repo           repositories.IPlaylistRepository
+	followRepo     repositories.IPlaylistFollowRepository
+	versionRepo    repositories.IPlaylistVersionRepository
+	likeRepo       repositories.ILikeRepository
 	trackService   ITrackService
+	coverService   IPlaylistCoverService
 	CloudinaryUtil *utils.CloudinaryUtil
 }
 
-func NewPlaylistService(repo repositories.IPlaylistRepository, trackService ITrackService, cloudinaryUtil *utils.CloudinaryUtil) IPlaylistService {
+func NewPlaylistService(repo repositories.IPlaylistRepository, followRepo repositories.IPlaylistFollowRepository, versionRepo repositories.IPlaylistVersionRepository, likeRepo repositories.ILikeRepository, trackService ITrackService, coverService IPlaylistCoverService, cloudinaryUtil *utils.CloudinaryUtil) IPlaylistService {
 	return &PlaylistService{
 		repo:           repo,
+		followRepo:     followRepo,
+		versionRepo:    versionRepo,
+		likeRepo:       likeRepo,
 		trackService:   trackService,
+		coverService:   coverService,
 		CloudinaryUtil: cloudinaryUtil,
 	}
 }
 
+// GetPlaylistByID returns a playlist; smart playlists come with their rules
+// evaluated into entries, and Liked Songs with the liked tracks.
 func (s *PlaylistService) GetPlaylistByID(id string) (*models.Playlist, error) {
-	return s.repo.GetPlaylistByID(id)
+	pl, err := s.repo.GetPlaylistByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return pl, s.prepare(pl)
 }
 
-func (s *PlaylistService) GetPlaylists(page, limit int, userID string) ([]*models.Playlist, error) {
-	return s.repo.GetPlaylists(page, limit, userID)
+func (s *PlaylistService) GetExpandedPlaylist(id string) (*models.ExpandedPlaylist, error) {
+	pl, err := s.repo.GetPlaylistByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prepare(pl); err != nil {
+		return nil, err
+	}
+
+	var entries []models.PlaylistEntry
+	if pl.Smart != nil || pl.IsLikedSongs() {
+		entries = pl.Entries
+	}
+	expanded, err := s.repo.GetExpandedPlaylist(id, entries)
+	if err != nil {
+		return nil, err
+	}
+	expanded.FollowerCount = pl.FollowerCount
+	expanded.LikeCount = pl.LikeCount
+	expanded.GeneratedCover = pl.GeneratedCover
+	return expanded, nil
 }
 
-func (s *PlaylistService) CountPlaylists(userID string) (int64, error) {
-	return s.repo.CountPlaylists(userID)
+// GetPlaylistsByIDs returns playlists in the order of ids, skipping missing ones.
+func (s *PlaylistService) GetPlaylistsByIDs(ids []primitive.ObjectID) ([]*models.Playlist, error) {
+	playlists, err := s.repo.GetPlaylistsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	return playlists, s.prepare(playlists...)
 }
 
-func (s *PlaylistService) CreatePlaylist(playlist *models.Playlist) (*models.Playlist, error) {
-	return s.repo.CreatePlaylist(playlist)
+func (s *PlaylistService) GetPlaylists(page, limit int, filter models.PlaylistFilter) ([]*models.Playlist, error) {
+	playlists, err := s.repo.GetPlaylists(page, limit, filter)
+	if err != nil {
+		return nil, err
+	}
+	return playlists, s.prepare(playlists...)
 }
 
-func (s *PlaylistService) UpdatePlaylist(playlist *models.Playlist) (*models.Playlist, error) {
-	return s.repo.UpdatePlaylist(playlist)
+func (s *PlaylistService) GetPlaylistsByCursor(cursor *utils.Cursor, limit int, filter models.PlaylistFilter) ([]*models.Playlist, utils.CursorPage, error) {
+	playlists, cursors, err := s.repo.GetPlaylistsByCursor(cursor, limit, filter)
+	if err != nil {
+		return nil, utils.CursorPage{}, err
+	}
+	return playlists, cursors, s.prepare(playlists...)
 }
 
-func (s *PlaylistService) DeletePlaylist(id string) error {
-	return s.repo.DeletePlaylist(id)
+func (s *PlaylistService) CountPlaylists(filter models.PlaylistFilter) (int64, error) {
+	return s.repo.CountPlaylists(filter)
 }
 
-// StreamPlaylistM3U generates the M3U content for a playlist using Extended M3U format.
-func (s *PlaylistService) StreamPlaylistM3U(id string, trackStreamBaseURL string) (string, error) {
-	id = strings.TrimSuffix(id, ".m3u")
+func (s *PlaylistService) CreatePlaylist(playlist *models.Playlist) (*models.Playlist, error) {
+	return s.repo.CreatePlaylist(playlist)
+}
 
-	playlist, err := s.repo.GetPlaylistByID(id)
+func (s *PlaylistService) DeletePlaylist(id string) error {
+	pl, err := s.repo.GetPlaylistByID(id)
 	if err != nil {
-		return "", err
+		return err
 	}
+	if pl.IsLikedSongs() {
+		return ErrLikedSongsReadOnly
+	}
+	if err := s.repo.DeletePlaylist(id); err != nil {
+		return err
+	}
+	if err := s.followRepo.DeleteByPlaylist(pl.ID); err != nil {
+		return err
+	}
+	if err := s.likeRepo.DeleteByTarget(models.LikeTargetPlaylist, pl.ID); err != nil {
+		return err
+	}
+	if err := s.coverService.DeleteCover(pl.ID); err != nil {
+		return err
+	}
+	return s.versionRepo.DeleteByPlaylist(pl.ID)
+}
 
-	tracks, err := s.trackService.GetTracksByIDs(playlist.TrackIDs)
+// ExportPlaylist renders a playlist in one of the dto.PlaylistFormat* formats.
+// baseURL is the public origin of the API (e.g. "https://music.example.com"),
+// so every track URL in the file is absolute. Deleted tracks are left out.
+func (s *PlaylistService) ExportPlaylist(id, format, baseURL string) (string, error) {
+	playlist, err := s.GetExpandedPlaylist(id)
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve tracks for playlist %s: %w", id, err)
+		return "", err
 	}
 
-	var m3uContent strings.Builder
-	m3uContent.WriteString("#EXTM3U\n")
-
-	baseURL := strings.TrimRight(trackStreamBaseURL, "/")
-
-	for _, track := range tracks {
-
-		// EXTINF
-		m3uContent.WriteString(
-			fmt.Sprintf("#EXTINF:%d,%s - %s\n", track.Duration, track.Artist, track.Title),
-		)
+	baseURL = strings.TrimRight(baseURL, "/")
+	export := utils.ExportPlaylist{
+		Title:    playlist.Title,
+		Location: fmt.Sprintf("%s/api/playlists/%s", baseURL, playlist.ID.Hex()),
+		Image:    playlist.Cover(),
+	}
 
-		trackURL := fmt.Sprintf("%s/%s/stream", baseURL, track.ID.Hex())
+	for _, item := range playlist.Items {
+		track := item.Track
+		if track == nil {
+			continue
+		}
 
-		m3uContent.WriteString(trackURL + "\n")
+		image := ""
+		if track.AlbumInfo != nil {
+			image = track.AlbumInfo.Cover
+		}
+		trackURL := fmt.Sprintf("%s/api/tracks/%s", baseURL, track.ID.Hex())
+		export.Tracks = append(export.Tracks, utils.ExportTrack{
+			Location:   trackURL + "/stream",
+			Identifier: trackURL,
+			Title:      track.Title,
+			Artist:     track.Artist,
+			Album:      track.Album,
+			Duration:   track.Duration,
+			Image:      image,
+		})
 	}
 
-	return m3uContent.String(), nil
+	switch format {
+	case dto.PlaylistFormatM3U8, dto.PlaylistFormatM3U:
+		return utils.FormatM3U8(export), nil
+	case dto.PlaylistFormatPLS:
+		return utils.FormatPLS(export), nil
+	case dto.PlaylistFormatXSPF:
+		return utils.FormatXSPF(export), nil
+	case dto.PlaylistFormatJSPF:
+		return utils.FormatJSPF(export), nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedPlaylistFormat, format)
+	}
 }
 
 func (s *PlaylistService) uploadAlbumCover(fileHeader *multipart.FileHeader) (string, error) {
@@ -124,11 +261,17 @@ func (s *PlaylistService) validateTrackIDs(trackIDs []string) ([]primitive.Objec
 		trackIDs = strings.Split(trackIDs[0], ",")
 	}
 
-	trackIDs = utils.UniqueStrings(trackIDs)
+	// Duplicates are kept: a playlist may repeat a track
+	cleaned := make([]string, 0, len(trackIDs))
+	for _, id := range trackIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			cleaned = append(cleaned, id)
+		}
+	}
 
-	objIDs, err := utils.ConvertToObjectIDs(trackIDs)
+	objIDs, err := utils.ConvertToObjectIDs(cleaned)
 	if err != nil {
-		return nil, fmt.Errorf("invalid track IDs: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrackIDs, err)
 	}
 
 	missing, err := s.trackService.FindMissingIDs(objIDs)
@@ -136,7 +279,7 @@ func (s *PlaylistService) validateTrackIDs(trackIDs []string) ([]primitive.Objec
 		return nil, fmt.Errorf("failed to check track IDs: %w", err)
 	}
 	if len(missing) > 0 {
-		return nil, fmt.Errorf("these track IDs do not exist: %v", missing)
+		return nil, fmt.Errorf("%w: these track IDs do not exist: %v", ErrInvalidTrackIDs, missing)
 	}
 
 	return objIDs, nil
@@ -163,21 +306,43 @@ func (s *PlaylistService) CreatePlaylistFormData(userID string, playlist *dto.Cr
 		UserID:     userIDObj,
 		Title:      playlist.Title,
 		AlbumCover: albumCoverURL,
-		TrackIDs:   objIDs,
+		Entries:    models.NewPlaylistEntries(objIDs, time.Now(), &userIDObj),
+		Visibility: playlist.Visibility,
 	}
 
-	return s.repo.CreatePlaylist(p)
+	return s.create(p, userID)
 }
 
-func (s *PlaylistService) UpdatePlaylistFormData(id string, req *dto.UpdatePlaylistRequest) (*models.Playlist, error) {
+// UpdatePlaylistFormData applies a form update by userID. When expected is
+// set, the update is rejected if the playlist is no longer at that version.
+func (s *PlaylistService) UpdatePlaylistFormData(id, userID string, expected *int64, req *dto.UpdatePlaylistRequest) (*models.Playlist, error) {
 	pl, err := s.repo.GetPlaylistByID(id)
 	if err != nil {
 		return nil, err
 	}
+	version := pl.Version
+	if expected != nil {
+		if *expected != pl.Version {
+			return nil, ErrPlaylistVersionConflict
+		}
+		version = *expected
+	}
+
+	if req.Mode == dto.ModeOverwrite || len(req.TrackIDs) > 0 {
+		if pl.Smart != nil {
+			return nil, ErrSmartPlaylistReadOnly
+		}
+		if pl.IsLikedSongs() {
+			return nil, ErrLikedSongsReadOnly
+		}
+	}
 
 	if req.Title != "" {
 		pl.Title = req.Title
 	}
+	if req.Visibility != "" {
+		pl.Visibility = req.Visibility
+	}
 
 	if req.AlbumCover != nil {
 		url, err := s.uploadAlbumCover(req.AlbumCover)
@@ -190,35 +355,649 @@ func (s *PlaylistService) UpdatePlaylistFormData(id string, req *dto.UpdatePlayl
 	// Handle track_ids update
 	if req.Mode == dto.ModeOverwrite {
 		// Overwrite mode: replace entirely (even with empty list)
-		if len(req.TrackIDs) == 0 {
-			pl.TrackIDs = nil
-		} else {
-			newIDs, err := s.validateTrackIDs(req.TrackIDs)
-			if err != nil {
-				return nil, err
-			}
-			pl.TrackIDs = newIDs
+		newIDs, err := s.validateTrackIDs(req.TrackIDs)
+		if err != nil {
+			return nil, err
 		}
+		pl.Entries = models.NewPlaylistEntries(newIDs, time.Now(), addedBy(userID))
 	} else if len(req.TrackIDs) > 0 {
-		// Append mode: merge new IDs with existing
+		// Append mode: add after the existing entries
 		newIDs, err := s.validateTrackIDs(req.TrackIDs)
 		if err != nil {
 			return nil, err
 		}
-		var existingIDs []string
-		for _, id := range pl.TrackIDs {
-			existingIDs = append(existingIDs, id.Hex())
+		pl.Entries = append(pl.Entries, models.NewPlaylistEntries(newIDs, time.Now(), addedBy(userID))...)
+	}
+
+	return s.save(pl, version, userID)
+}
+
+// InsertEntries adds tracks for userID at position (0 = first); nil or past
+// the end appends.
+func (s *PlaylistService) InsertEntries(id, userID string, version int64, trackIDs []string, position *int) (*models.Playlist, error) {
+	newIDs, err := s.validateTrackIDs(trackIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(newIDs) == 0 {
+		return nil, fmt.Errorf("%w: at least one track ID is required", ErrInvalidTrackIDs)
+	}
+
+	return s.edit(id, userID, version, func(pl *models.Playlist) error {
+		at := len(pl.Entries)
+		if position != nil {
+			if *position < 0 {
+				return ErrInvalidPlaylistPosition
+			}
+			at = min(*position, len(pl.Entries))
 		}
-		var combined []string
-		for _, id := range newIDs {
-			combined = append(combined, id.Hex())
+		pl.Entries = slices.Insert(pl.Entries, at, models.NewPlaylistEntries(newIDs, time.Now(), addedBy(userID))...)
+		return nil
+	})
+}
+
+// MoveEntry moves an entry so that it ends up at position.
+func (s *PlaylistService) MoveEntry(id, userID string, version int64, entryID string, position int) (*models.Playlist, error) {
+	return s.edit(id, userID, version, func(pl *models.Playlist) error {
+		from, err := entryIndex(pl, entryID)
+		if err != nil {
+			return err
+		}
+		if position < 0 || position >= len(pl.Entries) {
+			return ErrInvalidPlaylistPosition
 		}
-		combined = utils.UniqueStrings(append(existingIDs, combined...))
-		pl.TrackIDs, err = utils.ConvertToObjectIDs(combined)
+
+		entry := pl.Entries[from]
+		pl.Entries = slices.Delete(pl.Entries, from, from+1)
+		pl.Entries = slices.Insert(pl.Entries, position, entry)
+		return nil
+	})
+}
+
+func (s *PlaylistService) RemoveEntry(id, userID string, version int64, entryID string) (*models.Playlist, error) {
+	return s.edit(id, userID, version, func(pl *models.Playlist) error {
+		i, err := entryIndex(pl, entryID)
 		if err != nil {
-			return nil, fmt.Errorf("invalid track IDs after append: %w", err)
+			return err
 		}
+		pl.Entries = slices.Delete(pl.Entries, i, i+1)
+		return nil
+	})
+}
+
+func (s *PlaylistService) ReverseEntries(id, userID string, version int64) (*models.Playlist, error) {
+	return s.edit(id, userID, version, func(pl *models.Playlist) error {
+		slices.Reverse(pl.Entries)
+		return nil
+	})
+}
+
+// SortEntries orders the entries by a track field or by added_at. The sort is
+// stable, and entries of deleted tracks go last.
+func (s *PlaylistService) SortEntries(id, userID string, version int64, field string, desc bool) (*models.Playlist, error) {
+	return s.edit(id, userID, version, func(pl *models.Playlist) error {
+		ids := make([]primitive.ObjectID, len(pl.Entries))
+		for i, e := range pl.Entries {
+			ids[i] = e.TrackID
+		}
+		tracks, err := s.trackService.GetTracksByIDs(ids)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve tracks: %w", err)
+		}
+		byID := make(map[primitive.ObjectID]*models.Track, len(tracks))
+		for _, t := range tracks {
+			byID[t.ID] = t
+		}
+
+		slices.SortStableFunc(pl.Entries, func(a, b models.PlaylistEntry) int {
+			ta, tb := byID[a.TrackID], byID[b.TrackID]
+			if field != dto.PlaylistSortAddedAt && (ta == nil || tb == nil) {
+				// Missing tracks last, whatever the direction
+				switch {
+				case ta == nil && tb == nil:
+					return 0
+				case ta == nil:
+					return 1
+				default:
+					return -1
+				}
+			}
+
+			var c int
+			switch field {
+			case dto.PlaylistSortAddedAt:
+				c = a.AddedAt.Compare(b.AddedAt)
+			case dto.PlaylistSortTitle:
+				c = strings.Compare(utils.FoldDiacritics(ta.Title), utils.FoldDiacritics(tb.Title))
+			case dto.PlaylistSortArtist:
+				c = strings.Compare(utils.FoldDiacritics(ta.Artist), utils.FoldDiacritics(tb.Artist))
+			case dto.PlaylistSortAlbum:
+				c = strings.Compare(utils.FoldDiacritics(ta.Album), utils.FoldDiacritics(tb.Album))
+			case dto.PlaylistSortDuration:
+				c = ta.Duration - tb.Duration
+			case dto.PlaylistSortReleaseYear:
+				c = ta.ReleaseYear - tb.ReleaseYear
+			}
+			if desc {
+				return -c
+			}
+			return c
+		})
+		return nil
+	})
+}
+
+func (s *PlaylistService) BackfillPlaylistEntries() (int64, error) {
+	return s.repo.BackfillPlaylistEntries()
+}
+
+func (s *PlaylistService) BackfillPlaylistVisibility() (int64, error) {
+	return s.repo.BackfillPlaylistVisibility()
+}
+
+func (s *PlaylistService) EnsureIndexes() error {
+	return s.repo.EnsureIndexes()
+}
+
+func (s *PlaylistService) CreateSmartPlaylist(userID string, req *dto.CreateSmartPlaylistRequest) (*models.Playlist, error) {
+	smart, err := newSmartPlaylist(req.Rules, req.Sort, req.Order, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDObj, _ := primitive.ObjectIDFromHex(userID)
+	pl := &models.Playlist{
+		UserID:     userIDObj,
+		Title:      req.Title,
+		Entries:    []models.PlaylistEntry{},
+		Smart:      smart,
+		Visibility: req.Visibility,
+	}
+	if _, err := s.create(pl, userID); err != nil {
+		return nil, err
+	}
+	return pl, s.evaluateSmart(pl)
+}
+
+// UpdateSmartPlaylist replaces the rules, sort and limit of a smart playlist.
+func (s *PlaylistService) UpdateSmartPlaylist(id, userID string, version int64, req *dto.UpdateSmartPlaylistRequest) (*models.Playlist, error) {
+	smart, err := newSmartPlaylist(req.Rules, req.Sort, req.Order, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	pl, err := s.repo.GetPlaylistByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if pl.Version != version {
+		return nil, ErrPlaylistVersionConflict
+	}
+	if pl.Smart == nil {
+		return nil, ErrNotSmartPlaylist
+	}
+
+	pl.Smart = smart
+	if _, err := s.save(pl, version, userID); err != nil {
+		return nil, err
+	}
+	return pl, s.evaluateSmart(pl)
+}
+
+// CreateShareLink adds a read-only share link, valid until expiresAt if set.
+func (s *PlaylistService) CreateShareLink(id, userID string, expiresAt *time.Time) (*models.Playlist, *models.PlaylistShareLink, error) {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidShareLink)
+	}
+
+	pl, err := s.repo.GetPlaylistByID(id)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return s.repo.UpdatePlaylist(pl)
+	token, err := newShareToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	createdBy, _ := primitive.ObjectIDFromHex(userID)
+	link := models.PlaylistShareLink{
+		ID:        primitive.NewObjectID(),
+		Token:     token,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.AddShareLink(pl.ID, link); err != nil {
+		return nil, nil, err
+	}
+	return pl, &link, nil
+}
+
+func (s *PlaylistService) RevokeShareLink(id, linkID string) error {
+	playlistID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrShareLinkNotFound
+	}
+	linkObjID, err := primitive.ObjectIDFromHex(linkID)
+	if err != nil {
+		return ErrShareLinkNotFound
+	}
+
+	removed, err := s.repo.RemoveShareLink(playlistID, linkObjID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrShareLinkNotFound
+	}
+	return nil
+}
+
+// ForkPlaylist copies source into a new playlist owned by userID. Smart
+// playlists are copied as their current tracks, so the fork can be edited.
+func (s *PlaylistService) ForkPlaylist(source *models.Playlist, userID string, req *dto.ForkPlaylistRequest) (*models.Playlist, error) {
+	userIDObj, _ := primitive.ObjectIDFromHex(userID)
+
+	title := req.Title
+	if title == "" {
+		title = source.Title
+	}
+	now := time.Now()
+	fork := &models.Playlist{
+		UserID:     userIDObj,
+		Title:      title,
+		AlbumCover: source.AlbumCover,
+		Entries:    models.NewPlaylistEntries(source.TrackIDs, now, &userIDObj),
+		Visibility: req.Visibility,
+		ForkedFrom: &models.PlaylistForkSource{
+			PlaylistID: source.ID,
+			Version:    source.Version,
+			TrackIDs:   slices.Clone(source.TrackIDs),
+			SyncedAt:   now,
+		},
+	}
+	return s.create(fork, userID)
+}
+
+// PullFork merges the changes made to the source since the last sync into
+// the fork. Tracks added to the source are appended and tracks removed from
+// it are removed from the fork, so the fork's own edits are kept. It returns
+// the added and removed track IDs.
+func (s *PlaylistService) PullFork(id, userID string, version int64, source *models.Playlist) (*models.Playlist, []primitive.ObjectID, []primitive.ObjectID, error) {
+	var added, removed []primitive.ObjectID
+	pl, err := s.edit(id, userID, version, func(pl *models.Playlist) error {
+		if pl.ForkedFrom == nil || pl.ForkedFrom.PlaylistID != source.ID {
+			return ErrNotForked
+		}
+
+		// Count-based three-way merge against the source at the last sync
+		delta := make(map[primitive.ObjectID]int)
+		for _, t := range source.TrackIDs {
+			delta[t]++
+		}
+		for _, t := range pl.ForkedFrom.TrackIDs {
+			delta[t]--
+		}
+
+		for _, t := range source.TrackIDs {
+			if delta[t] > 0 {
+				added = append(added, t)
+				delta[t]--
+			}
+		}
+		pl.Entries = slices.DeleteFunc(pl.Entries, func(e models.PlaylistEntry) bool {
+			if delta[e.TrackID] < 0 {
+				removed = append(removed, e.TrackID)
+				delta[e.TrackID]++
+				return true
+			}
+			return false
+		})
+		pl.Entries = append(pl.Entries, models.NewPlaylistEntries(added, time.Now(), addedBy(userID))...)
+
+		pl.ForkedFrom.Version = source.Version
+		pl.ForkedFrom.TrackIDs = slices.Clone(source.TrackIDs)
+		pl.ForkedFrom.SyncedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return pl, added, removed, nil
+}
+
+// RestoreVersion puts back the title, cover and entries (or smart rules) the
+// playlist had at version number. The restore is a new change on top of the
+// current version, so it can itself be undone.
+func (s *PlaylistService) RestoreVersion(id, userID string, version, number int64) (*models.Playlist, error) {
+	pl, err := s.repo.GetPlaylistByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if pl.Version != version {
+		return nil, ErrPlaylistVersionConflict
+	}
+	if pl.IsLikedSongs() {
+		return nil, ErrLikedSongsReadOnly
+	}
+
+	snapshot, err := s.versionRepo.GetVersion(pl.ID, number)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, ErrPlaylistVersionNotFound
+	}
+
+	pl.Title = snapshot.Title
+	pl.AlbumCover = snapshot.AlbumCover
+	pl.Smart = snapshot.Smart
+	pl.Entries = slices.Clone(snapshot.Entries)
+	if pl.Entries == nil {
+		pl.Entries = []models.PlaylistEntry{}
+	}
+
+	ok, err := s.repo.UpdatePlaylistVersion(pl, version)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPlaylistVersionConflict
+	}
+	recordPlaylistVersion(s.versionRepo, pl, userID, &number)
+	s.coverService.Enqueue(pl.ID)
+	return pl, s.prepare(pl)
+}
+
+// GetLikedSongs returns the Liked Songs of a user, creating it the first time.
+func (s *PlaylistService) GetLikedSongs(userID string) (*models.Playlist, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	pl, err := s.likedSongs(userIDObj)
+	if err != nil {
+		return nil, err
+	}
+	return pl, s.prepare(pl)
+}
+
+// RefreshLikedSongs is called when a user likes or unlikes a track. It makes
+// sure the user has a Liked Songs and schedules its cover.
+func (s *PlaylistService) RefreshLikedSongs(userID primitive.ObjectID) error {
+	pl, err := s.likedSongs(userID)
+	if err != nil {
+		return err
+	}
+	s.coverService.Enqueue(pl.ID)
+	return nil
+}
+
+// likedSongs returns the stored Liked Songs of a user, creating it if needed.
+// Its entries stay empty in the database: they are read from the likes.
+func (s *PlaylistService) likedSongs(userID primitive.ObjectID) (*models.Playlist, error) {
+	pl, err := s.repo.GetPlaylistByKind(userID, models.PlaylistKindLikedSongs)
+	if err != nil || pl != nil {
+		return pl, err
+	}
+
+	pl = &models.Playlist{
+		UserID:     userID,
+		Title:      "Liked Songs",
+		Entries:    []models.PlaylistEntry{},
+		Visibility: models.PlaylistVisibilityPrivate,
+		Kind:       models.PlaylistKindLikedSongs,
+	}
+	if _, err := s.create(pl, userID.Hex()); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// Created by a concurrent request
+			return s.repo.GetPlaylistByKind(userID, models.PlaylistKindLikedSongs)
+		}
+		return nil, err
+	}
+	return pl, nil
+}
+
+// newShareToken returns a random, URL-safe token of 192 bits.
+func newShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// newSmartPlaylist validates the rules by compiling them once.
+func newSmartPlaylist(rules dto.SmartRule, sort, order string, limit int) (*models.SmartPlaylist, error) {
+	if limit == 0 {
+		limit = models.SmartDefaultLimit
+	}
+	smart := &models.SmartPlaylist{
+		Rules: mappers.ToSmartRuleModel(rules),
+		Sort:  sort,
+		Desc:  order == "desc",
+		Limit: limit,
+	}
+	if _, err := repositories.CompileSmartRules(smart.Rules, repositories.SmartContext{}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSmartRules, err)
+	}
+	if _, err := repositories.SmartSortBSON(smart); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSmartRules, err)
+	}
+	return smart, nil
+}
+
+// evaluateSmart fills the entries of a smart playlist from its rules. Entry
+// IDs are the track IDs, so they stay stable between reads.
+func (s *PlaylistService) evaluateSmart(pl *models.Playlist) error {
+	if pl.Smart == nil {
+		return nil
+	}
+
+	ctx, err := smartContext(s.likeRepo, pl)
+	if err != nil {
+		return err
+	}
+	tracks, err := s.trackService.FindSmartTracks(pl.Smart, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate smart playlist %s: %w", pl.ID.Hex(), err)
+	}
+	pl.Entries = make([]models.PlaylistEntry, len(tracks))
+	for i, t := range tracks {
+		pl.Entries[i] = models.PlaylistEntry{ID: t.ID, TrackID: t.ID, AddedAt: t.CreatedAt}
+	}
+	pl.SyncTrackIDs()
+	return nil
+}
+
+// evaluateLikedSongs fills the entries of Liked Songs from the likes of its owner.
+func (s *PlaylistService) evaluateLikedSongs(pl *models.Playlist) error {
+	if !pl.IsLikedSongs() {
+		return nil
+	}
+
+	entries, err := likedSongsEntries(s.likeRepo, pl.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load the liked songs of %s: %w", pl.UserID.Hex(), err)
+	}
+	pl.Entries = entries
+	pl.SyncTrackIDs()
+	return nil
+}
+
+// smartContext loads what the rules of a smart playlist refer to besides the
+// tracks: the likes of its owner, only when a rule is on "liked".
+func smartContext(likeRepo repositories.ILikeRepository, pl *models.Playlist) (repositories.SmartContext, error) {
+	var ctx repositories.SmartContext
+	if !repositories.SmartRulesUseField(pl.Smart.Rules, "liked") {
+		return ctx, nil
+	}
+	likes, err := likeRepo.GetAllLikes(pl.UserID, models.LikeTargetTrack)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to load the likes of %s: %w", pl.UserID.Hex(), err)
+	}
+	ctx.LikedTrackIDs = make([]primitive.ObjectID, len(likes))
+	for i, l := range likes {
+		ctx.LikedTrackIDs[i] = l.TargetID
+	}
+	return ctx, nil
+}
+
+// likedSongsEntries returns the entries of a user's Liked Songs, most recently
+// liked first. Like those of smart playlists, entry IDs are the track IDs.
+func likedSongsEntries(likeRepo repositories.ILikeRepository, userID primitive.ObjectID) ([]models.PlaylistEntry, error) {
+	likes, err := likeRepo.GetAllLikes(userID, models.LikeTargetTrack)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]models.PlaylistEntry, len(likes))
+	for i, l := range likes {
+		entries[i] = models.PlaylistEntry{ID: l.TargetID, TrackID: l.TargetID, AddedAt: l.CreatedAt, AddedBy: &userID}
+	}
+	return entries, nil
+}
+
+// prepare completes playlists read from the database: smart playlists and
+// Liked Songs are evaluated, and follower counts, like counts and generated
+// covers filled in.
+func (s *PlaylistService) prepare(playlists ...*models.Playlist) error {
+	for _, pl := range playlists {
+		if err := s.evaluateSmart(pl); err != nil {
+			return err
+		}
+		if err := s.evaluateLikedSongs(pl); err != nil {
+			return err
+		}
+	}
+	if err := s.loadCovers(playlists...); err != nil {
+		return err
+	}
+	if err := s.countFollowers(playlists...); err != nil {
+		return err
+	}
+	return s.countLikes(playlists...)
+}
+
+func (s *PlaylistService) loadCovers(playlists ...*models.Playlist) error {
+	ids := make([]primitive.ObjectID, 0, len(playlists))
+	for _, pl := range playlists {
+		if pl.AlbumCover == "" {
+			ids = append(ids, pl.ID)
+		}
+	}
+	urls, err := s.coverService.GetCoverURLs(ids)
+	if err != nil {
+		return err
+	}
+	for _, pl := range playlists {
+		pl.GeneratedCover = urls[pl.ID]
+	}
+	return nil
+}
+
+func (s *PlaylistService) countFollowers(playlists ...*models.Playlist) error {
+	ids := make([]primitive.ObjectID, len(playlists))
+	for i, pl := range playlists {
+		ids[i] = pl.ID
+	}
+	counts, err := s.followRepo.CountFollowers(ids)
+	if err != nil {
+		return err
+	}
+	for _, pl := range playlists {
+		pl.FollowerCount = counts[pl.ID]
+	}
+	return nil
+}
+
+func (s *PlaylistService) countLikes(playlists ...*models.Playlist) error {
+	ids := make([]primitive.ObjectID, len(playlists))
+	for i, pl := range playlists {
+		ids[i] = pl.ID
+	}
+	counts, err := s.likeRepo.CountLikes(models.LikeTargetPlaylist, ids)
+	if err != nil {
+		return err
+	}
+	for _, pl := range playlists {
+		pl.LikeCount = counts[pl.ID]
+	}
+	return nil
+}
+
+// edit loads the playlist, checks the version the client saw, applies fn and
+// saves with optimistic concurrency.
+func (s *PlaylistService) edit(id, userID string, version int64, fn func(pl *models.Playlist) error) (*models.Playlist, error) {
+	pl, err := s.repo.GetPlaylistByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if pl.Version != version {
+		return nil, ErrPlaylistVersionConflict
+	}
+	if pl.Smart != nil {
+		return nil, ErrSmartPlaylistReadOnly
+	}
+	if pl.IsLikedSongs() {
+		return nil, ErrLikedSongsReadOnly
+	}
+	if err := fn(pl); err != nil {
+		return nil, err
+	}
+	return s.save(pl, version, userID)
+}
+
+// create inserts a new playlist, records it as its first version and
+// schedules its cover.
+func (s *PlaylistService) create(pl *models.Playlist, userID string) (*models.Playlist, error) {
+	if _, err := s.repo.CreatePlaylist(pl); err != nil {
+		return nil, err
+	}
+	recordPlaylistVersion(s.versionRepo, pl, userID, nil)
+	s.coverService.Enqueue(pl.ID)
+	return pl, nil
+}
+
+// save writes the playlist if it is still at version, records the change by
+// userID in the version history and schedules a new cover, which is only
+// rebuilt if the albums it shows changed.
+func (s *PlaylistService) save(pl *models.Playlist, version int64, userID string) (*models.Playlist, error) {
+	ok, err := s.repo.UpdatePlaylistVersion(pl, version)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPlaylistVersionConflict
+	}
+	recordPlaylistVersion(s.versionRepo, pl, userID, nil)
+	s.coverService.Enqueue(pl.ID)
+	if err := s.loadCovers(pl); err != nil {
+		return nil, err
+	}
+	if err := s.countFollowers(pl); err != nil {
+		return nil, err
+	}
+	return pl, s.countLikes(pl)
+}
+
+// addedBy is the attribution of new entries; nil if userID is not valid.
+func addedBy(userID string) *primitive.ObjectID {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+func entryIndex(pl *models.Playlist, entryID string) (int, error) {
+	id, err := primitive.ObjectIDFromHex(entryID)
+	if err != nil {
+		return -1, ErrPlaylistEntryNotFound
+	}
+	for i, e := range pl.Entries {
+		if e.ID == id {
+			return i, nil
+		}
+	}
+	return -1, ErrPlaylistEntryNotFound
 }
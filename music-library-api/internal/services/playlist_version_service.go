@@ -0,0 +1,222 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"reflect"
+	"slices"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IPlaylistVersionService interface {
+	GetVersions(pl *models.Playlist, page, limit int) ([]dto.PlaylistVersionSummaryResponse, int64, error)
+	GetVersion(pl *models.Playlist, version int64) (*dto.PlaylistVersionResponse, error)
+	DiffVersions(pl *models.Playlist, from int64, to *int64) (*dto.PlaylistVersionDiffResponse, error)
+}
+
+var ErrPlaylistVersionNotFound = errors.New("playlist version not found")
+
+// PlaylistVersionService reads the version history of playlists. Snapshots
+// are written by recordPlaylistVersion whenever a playlist is saved.
+type PlaylistVersionService struct {
+	repo repositories.IPlaylistVersionRepository
+}
+
+func NewPlaylistVersionService(repo repositories.IPlaylistVersionRepository) IPlaylistVersionService {
+	return &PlaylistVersionService{repo: repo}
+}
+
+func (s *PlaylistVersionService) GetVersions(pl *models.Playlist, page, limit int) ([]dto.PlaylistVersionSummaryResponse, int64, error) {
+	versions, err := s.repo.GetVersions(pl.ID, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.repo.CountVersions(pl.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := make([]dto.PlaylistVersionSummaryResponse, len(versions))
+	for i, v := range versions {
+		resp[i] = mappers.ToPlaylistVersionSummaryResponse(v)
+	}
+	return resp, total, nil
+}
+
+func (s *PlaylistVersionService) GetVersion(pl *models.Playlist, version int64) (*dto.PlaylistVersionResponse, error) {
+	v, err := s.getVersion(pl, version)
+	if err != nil {
+		return nil, err
+	}
+	resp := mappers.ToPlaylistVersionResponse(v)
+	return &resp, nil
+}
+
+// DiffVersions compares two snapshots, by default from against the latest.
+// Entries are matched by entry ID, so a moved entry shows up as moved rather
+// than as removed and added again.
+func (s *PlaylistVersionService) DiffVersions(pl *models.Playlist, from int64, to *int64) (*dto.PlaylistVersionDiffResponse, error) {
+	a, err := s.getVersion(pl, from)
+	if err != nil {
+		return nil, err
+	}
+	var b *models.PlaylistVersion
+	if to != nil {
+		b, err = s.getVersion(pl, *to)
+	} else {
+		b, err = s.repo.GetLatestVersion(pl.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, ErrPlaylistVersionNotFound
+	}
+
+	diff := &dto.PlaylistVersionDiffResponse{
+		From:         a.Version,
+		To:           b.Version,
+		Added:        []dto.PlaylistDiffEntry{},
+		Removed:      []dto.PlaylistDiffEntry{},
+		Moved:        []dto.PlaylistDiffMove{},
+		SmartChanged: !reflect.DeepEqual(a.Smart, b.Smart),
+	}
+	if a.Title != b.Title {
+		diff.Title = &dto.PlaylistFieldChange{From: a.Title, To: b.Title}
+	}
+	if a.AlbumCover != b.AlbumCover {
+		diff.AlbumCover = &dto.PlaylistFieldChange{From: a.AlbumCover, To: b.AlbumCover}
+	}
+
+	fromPos := make(map[primitive.ObjectID]int, len(a.Entries))
+	for i, e := range a.Entries {
+		fromPos[e.ID] = i
+	}
+	toPos := make(map[primitive.ObjectID]int, len(b.Entries))
+	for i, e := range b.Entries {
+		toPos[e.ID] = i
+	}
+
+	for i, e := range a.Entries {
+		if _, ok := toPos[e.ID]; !ok {
+			diff.Removed = append(diff.Removed, dto.PlaylistDiffEntry{EntryID: e.ID.Hex(), TrackID: e.TrackID.Hex(), Position: i})
+		}
+	}
+
+	// Entries kept in both versions stay in place if they are part of the
+	// longest run that kept its relative order; the others were moved.
+	var kept []models.PlaylistEntry
+	for i, e := range b.Entries {
+		if _, ok := fromPos[e.ID]; ok {
+			kept = append(kept, e)
+		} else {
+			diff.Added = append(diff.Added, dto.PlaylistDiffEntry{EntryID: e.ID.Hex(), TrackID: e.TrackID.Hex(), Position: i})
+		}
+	}
+	ranks := make([]int, len(kept))
+	for i, e := range kept {
+		ranks[i] = fromPos[e.ID]
+	}
+	inPlace := longestIncreasing(ranks)
+	for i, e := range kept {
+		if !inPlace[i] {
+			diff.Moved = append(diff.Moved, dto.PlaylistDiffMove{
+				EntryID: e.ID.Hex(),
+				TrackID: e.TrackID.Hex(),
+				From:    fromPos[e.ID],
+				To:      toPos[e.ID],
+			})
+		}
+	}
+
+	return diff, nil
+}
+
+func (s *PlaylistVersionService) getVersion(pl *models.Playlist, version int64) (*models.PlaylistVersion, error) {
+	v, err := s.repo.GetVersion(pl.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrPlaylistVersionNotFound
+	}
+	return v, nil
+}
+
+// longestIncreasing marks the elements of one longest strictly increasing
+// subsequence of values, in O(n log n).
+func longestIncreasing(values []int) []bool {
+	tails := []int{} // tails[k] = index of the smallest tail of a run of length k+1
+	prev := make([]int, len(values))
+	for i, v := range values {
+		k := sort.Search(len(tails), func(j int) bool { return values[tails[j]] >= v })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	marked := make([]bool, len(values))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			marked[i] = true
+		}
+	}
+	return marked
+}
+
+// recordPlaylistVersion snapshots a playlist after it was saved, unless its
+// title, cover and entries are the same as in the latest snapshot. The change
+// itself is already saved, so a failure is only logged.
+func recordPlaylistVersion(repo repositories.IPlaylistVersionRepository, pl *models.Playlist, userID string, restoredFrom *int64) {
+	if err := createPlaylistVersion(repo, pl, userID, restoredFrom); err != nil {
+		log.Printf("Failed to record version %d of playlist %s: %v", pl.Version, pl.ID.Hex(), err)
+	}
+}
+
+func createPlaylistVersion(repo repositories.IPlaylistVersionRepository, pl *models.Playlist, userID string, restoredFrom *int64) error {
+	snapshot := &models.PlaylistVersion{
+		PlaylistID:   pl.ID,
+		Version:      pl.Version,
+		Title:        pl.Title,
+		AlbumCover:   pl.AlbumCover,
+		Entries:      slices.Clone(pl.Entries),
+		Smart:        pl.Smart,
+		ChangedBy:    addedBy(userID),
+		RestoredFrom: restoredFrom,
+	}
+	if pl.Smart != nil {
+		// Smart entries are computed on read, the rules are what changed
+		snapshot.Entries = []models.PlaylistEntry{}
+	}
+
+	latest, err := repo.GetLatestVersion(pl.ID)
+	if err != nil {
+		return err
+	}
+	if latest != nil && restoredFrom == nil && sameSnapshot(latest, snapshot) {
+		return nil
+	}
+	return repo.CreateVersion(snapshot)
+}
+
+func sameSnapshot(a, b *models.PlaylistVersion) bool {
+	if a.Title != b.Title || a.AlbumCover != b.AlbumCover || !reflect.DeepEqual(a.Smart, b.Smart) {
+		return false
+	}
+	return slices.EqualFunc(a.Entries, b.Entries, func(x, y models.PlaylistEntry) bool {
+		return x.ID == y.ID && x.TrackID == y.TrackID
+	})
+}
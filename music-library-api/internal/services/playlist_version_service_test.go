@@ -0,0 +1,44 @@
+package services
+
+import "testing"
+
+func TestLongestIncreasing(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		length int
+	}{
+		{"empty", nil, 0},
+		{"single", []int{4}, 1},
+		{"already sorted", []int{0, 1, 2, 3}, 4},
+		{"reversed", []int{3, 2, 1, 0}, 1},
+		{"one moved to the front", []int{3, 0, 1, 2}, 3},
+		{"one moved to the back", []int{1, 2, 3, 0}, 3},
+		{"two swapped", []int{0, 2, 1, 3}, 3},
+		{"mixed", []int{5, 1, 6, 2, 7, 3, 8}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marked := longestIncreasing(tt.values)
+			if len(marked) != len(tt.values) {
+				t.Fatalf("len(marked) = %d, want %d", len(marked), len(tt.values))
+			}
+
+			length, last := 0, -1
+			for i, m := range marked {
+				if !m {
+					continue
+				}
+				if tt.values[i] <= last {
+					t.Errorf("marked values are not strictly increasing: %v / %v", tt.values, marked)
+				}
+				last = tt.values[i]
+				length++
+			}
+			if length != tt.length {
+				t.Errorf("marked %d values of %v, want %d", length, tt.values, tt.length)
+			}
+		})
+	}
+}
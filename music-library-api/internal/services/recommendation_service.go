@@ -0,0 +1,540 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IRecommendationService interface {
+	GetUserRecommendations(userID string, limit int) ([]dto.RecommendedTrackResponse, *time.Time, error)
+	GetSimilarTracks(trackID string, limit int) ([]dto.RecommendedTrackResponse, *time.Time, error)
+	Run() error
+	Start(interval time.Duration)
+	EnsureIndexes() error
+}
+
+var ErrRecommendationTrackNotFound = errors.New("track not found")
+
+// Tuning of the recommendation job.
+const (
+	recMaxProfile    = 500 // most played or liked tracks per user compared
+	recNeighbours    = 50  // similar tracks kept per track
+	recUserItems     = 100 // recommendations kept per user
+	recContentBucket = 200 // most popular tracks compared per artist, genre and tag
+	recShrinkage     = 5.0 // damps similarities backed by few listeners
+	recLikeWeight    = 2.0 // a like weighs as much as about six plays
+	recListenerShare = 0.7 // weight of co-listening against content similarity
+)
+
+// RecommendationService builds recommendations offline, without any outside
+// service. Tracks are similar when the same users play or like them
+// (item-to-item collaborative filtering) and when they share artist, genre or
+// tags. A user's feed is the tracks most similar to what they played and
+// liked. Missing or short lists are completed with popular tracks on read.
+type RecommendationService struct {
+	repo         repositories.IRecommendationRepository
+	playRepo     repositories.IPlayRepository
+	likeRepo     repositories.ILikeRepository
+	trackService ITrackService
+	running      atomic.Bool
+}
+
+func NewRecommendationService(repo repositories.IRecommendationRepository, playRepo repositories.IPlayRepository, likeRepo repositories.ILikeRepository, trackService ITrackService) IRecommendationService {
+	return &RecommendationService{
+		repo:         repo,
+		playRepo:     playRepo,
+		likeRepo:     likeRepo,
+		trackService: trackService,
+	}
+}
+
+// GetUserRecommendations returns the feed of a user and when it was
+// generated, nil for users the job has not seen yet, who get popular tracks.
+func (s *RecommendationService) GetUserRecommendations(userID string, limit int) ([]dto.RecommendedTrackResponse, *time.Time, error) {
+	userIDObj, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rec, err := s.repo.GetRecommendation(models.RecommendationKindUser, userIDObj)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.respond(rec, limit, nil, []models.TrackFilter{{}})
+}
+
+// GetSimilarTracks returns the tracks most similar to a track. New or rarely
+// played tracks fall back to popular tracks by the same artist, then in the
+// same genre, then overall.
+func (s *RecommendationService) GetSimilarTracks(trackID string, limit int) ([]dto.RecommendedTrackResponse, *time.Time, error) {
+	track, err := s.trackService.GetTrackByID(trackID)
+	if err != nil {
+		return nil, nil, ErrRecommendationTrackNotFound
+	}
+	rec, err := s.repo.GetRecommendation(models.RecommendationKindTrack, track.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fallbacks []models.TrackFilter
+	if track.Artist != "" {
+		fallbacks = append(fallbacks, models.TrackFilter{Artist: track.Artist})
+	}
+	if track.Genre != "" {
+		fallbacks = append(fallbacks, models.TrackFilter{Genre: track.Genre})
+	}
+	fallbacks = append(fallbacks, models.TrackFilter{})
+	return s.respond(rec, limit, []primitive.ObjectID{track.ID}, fallbacks)
+}
+
+func (s *RecommendationService) EnsureIndexes() error {
+	return s.repo.EnsureIndexes()
+}
+
+// respond resolves stored recommendations to tracks, skipping deleted ones,
+// and completes the list up to limit with the popular tracks of each
+// fallback filter in turn. Tracks in exclude are never returned.
+func (s *RecommendationService) respond(rec *models.Recommendation, limit int, exclude []primitive.ObjectID, fallbacks []models.TrackFilter) ([]dto.RecommendedTrackResponse, *time.Time, error) {
+	seen := make(map[primitive.ObjectID]bool, limit+len(exclude))
+	for _, id := range exclude {
+		seen[id] = true
+	}
+	resp := make([]dto.RecommendedTrackResponse, 0, limit)
+
+	var generatedAt *time.Time
+	if rec != nil {
+		generatedAt = &rec.GeneratedAt
+		items := rec.Items[:min(len(rec.Items), limit+len(exclude))]
+		ids := make([]primitive.ObjectID, len(items))
+		for i, item := range items {
+			ids[i] = item.TrackID
+		}
+		tracks, err := s.trackService.GetTracksByIDs(ids)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve recommended tracks: %w", err)
+		}
+		byID := make(map[primitive.ObjectID]*models.Track, len(tracks))
+		for _, t := range tracks {
+			byID[t.ID] = t
+		}
+		for _, item := range items {
+			if t := byID[item.TrackID]; t != nil && !seen[t.ID] && len(resp) < limit {
+				seen[t.ID] = true
+				resp = append(resp, mappers.ToRecommendedTrackResponse(item, t))
+			}
+		}
+	}
+
+	for _, filter := range fallbacks {
+		if len(resp) >= limit {
+			break
+		}
+		popular, err := s.trackService.GetPopularTracks(filter, limit+len(seen))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve popular tracks: %w", err)
+		}
+		for _, t := range popular {
+			if !seen[t.ID] && len(resp) < limit {
+				seen[t.ID] = true
+				item := models.RecommendedTrack{TrackID: t.ID, Reason: models.RecommendationReasonPopular}
+				resp = append(resp, mappers.ToRecommendedTrackResponse(item, t))
+			}
+		}
+	}
+	return resp, generatedAt, nil
+}
+
+// Start runs the job now in the background, then every interval.
+func (s *RecommendationService) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := s.Run(); err != nil {
+				log.Printf("Failed to generate recommendations: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// Run regenerates every recommendation from the play counts and likes.
+// Recommendations the run did not produce, such as those of deleted tracks,
+// are removed. A run already in progress makes this a no-op.
+func (s *RecommendationService) Run() error {
+	if !s.running.CompareAndSwap(false, true) {
+		return nil
+	}
+	defer s.running.Store(false)
+
+	began := time.Now()
+	// Mongo stores milliseconds, so the cleanup below compares equal times
+	generatedAt := began.UTC().Truncate(time.Millisecond)
+
+	tracks, err := s.trackService.GetTrackFeatures()
+	if err != nil {
+		return fmt.Errorf("failed to load tracks: %w", err)
+	}
+	plays, err := s.playRepo.GetAllUserTrackPlays()
+	if err != nil {
+		return fmt.Errorf("failed to load play counts: %w", err)
+	}
+	likes, err := s.likeRepo.GetLikesByType(models.LikeTargetTrack)
+	if err != nil {
+		return fmt.Errorf("failed to load likes: %w", err)
+	}
+
+	known := make(map[primitive.ObjectID]bool, len(tracks))
+	for _, t := range tracks {
+		known[t.ID] = true
+	}
+	profiles := listeningProfiles(plays, likes, known)
+	similar := combineSimilarities(listenerSimilarities(profiles), contentSimilarities(tracks))
+
+	recs := make([]models.Recommendation, 0, len(similar)+len(profiles))
+	for trackID, items := range similar {
+		recs = append(recs, models.Recommendation{
+			Kind:        models.RecommendationKindTrack,
+			SubjectID:   trackID,
+			Items:       items,
+			GeneratedAt: generatedAt,
+		})
+	}
+	users := 0
+	for userID, profile := range profiles {
+		items := recommendForUser(profile, similar)
+		if len(items) == 0 {
+			continue
+		}
+		users++
+		recs = append(recs, models.Recommendation{
+			Kind:        models.RecommendationKindUser,
+			SubjectID:   userID,
+			Items:       items,
+			GeneratedAt: generatedAt,
+		})
+	}
+
+	if err := s.repo.SaveRecommendations(recs); err != nil {
+		return fmt.Errorf("failed to save recommendations: %w", err)
+	}
+	if _, err := s.repo.DeleteGeneratedBefore(generatedAt); err != nil {
+		return fmt.Errorf("failed to remove stale recommendations: %w", err)
+	}
+	log.Printf("Generated recommendations for %d users and %d tracks in %s",
+		users, len(similar), time.Since(began).Round(time.Millisecond))
+	return nil
+}
+
+// weightedTrack is a track in a user's profile, or a neighbour of a track.
+type weightedTrack struct {
+	id     primitive.ObjectID
+	weight float64
+}
+
+// listeningProfiles weighs the tracks of each user by log(1 + plays), plus
+// recLikeWeight if liked, and keeps the recMaxProfile heaviest. Deleted
+// tracks are left out.
+func listeningProfiles(plays []models.UserTrackPlays, likes []models.Like, known map[primitive.ObjectID]bool) map[primitive.ObjectID][]weightedTrack {
+	weights := map[primitive.ObjectID]map[primitive.ObjectID]float64{}
+	add := func(userID, trackID primitive.ObjectID, w float64) {
+		if !known[trackID] || w <= 0 {
+			return
+		}
+		if weights[userID] == nil {
+			weights[userID] = map[primitive.ObjectID]float64{}
+		}
+		weights[userID][trackID] += w
+	}
+	for _, p := range plays {
+		add(p.UserID, p.TrackID, math.Log1p(float64(p.Count)))
+	}
+	for _, l := range likes {
+		add(l.UserID, l.TargetID, recLikeWeight)
+	}
+
+	profiles := make(map[primitive.ObjectID][]weightedTrack, len(weights))
+	for userID, byTrack := range weights {
+		profile := make([]weightedTrack, 0, len(byTrack))
+		for id, w := range byTrack {
+			profile = append(profile, weightedTrack{id, w})
+		}
+		profiles[userID] = topWeighted(profile, recMaxProfile)
+	}
+	return profiles
+}
+
+// listenerSimilarities is item-to-item collaborative filtering: the cosine
+// similarity of two tracks over the weights users gave them, shrunk towards
+// zero when few users share both.
+func listenerSimilarities(profiles map[primitive.ObjectID][]weightedTrack) map[primitive.ObjectID][]weightedTrack {
+	type listener struct {
+		profile []weightedTrack
+		weight  float64
+	}
+	listeners := map[primitive.ObjectID][]listener{}
+	norms := map[primitive.ObjectID]float64{}
+	for _, profile := range profiles {
+		for _, t := range profile {
+			listeners[t.id] = append(listeners[t.id], listener{profile, t.weight})
+			norms[t.id] += t.weight * t.weight
+		}
+	}
+
+	type pair struct {
+		dot    float64
+		shared int
+	}
+	similar := make(map[primitive.ObjectID][]weightedTrack, len(listeners))
+	for id, users := range listeners {
+		pairs := map[primitive.ObjectID]*pair{}
+		for _, u := range users {
+			for _, other := range u.profile {
+				if other.id == id {
+					continue
+				}
+				p := pairs[other.id]
+				if p == nil {
+					p = &pair{}
+					pairs[other.id] = p
+				}
+				p.dot += u.weight * other.weight
+				p.shared++
+			}
+		}
+
+		neighbours := make([]weightedTrack, 0, len(pairs))
+		for other, p := range pairs {
+			cosine := p.dot / math.Sqrt(norms[id]*norms[other])
+			shrink := float64(p.shared) / (float64(p.shared) + recShrinkage)
+			neighbours = append(neighbours, weightedTrack{other, cosine * shrink})
+		}
+		if len(neighbours) > 0 {
+			similar[id] = topWeighted(neighbours, recNeighbours)
+		}
+	}
+	return similar
+}
+
+// trackFeatures is what content similarity compares.
+type trackFeatures struct {
+	artist string
+	genre  string
+	tags   []string
+}
+
+// contentSimilarities scores tracks by shared artist, genre and tags. Only
+// the recContentBucket most popular tracks of each artist, genre and tag are
+// candidates, which bounds the work for large catalogues.
+func contentSimilarities(tracks []*models.Track) map[primitive.ObjectID][]weightedTrack {
+	features := make(map[primitive.ObjectID]trackFeatures, len(tracks))
+	popularity := make(map[primitive.ObjectID]float64, len(tracks))
+	buckets := map[string][]primitive.ObjectID{}
+	for _, t := range tracks {
+		f := trackFeatures{artist: t.Search.Artist, genre: t.Search.Genre, tags: t.Tags}
+		if t.GenreID != nil {
+			f.genre = t.GenreID.Hex()
+		}
+		features[t.ID] = f
+		popularity[t.ID] = float64(t.PlayCount) + recLikeWeight*float64(t.LikeCount)
+		for _, key := range f.bucketKeys() {
+			buckets[key] = append(buckets[key], t.ID)
+		}
+	}
+	for key, ids := range buckets {
+		slices.SortStableFunc(ids, func(a, b primitive.ObjectID) int {
+			return compareWeights(popularity[a], popularity[b], a, b)
+		})
+		buckets[key] = ids[:min(len(ids), recContentBucket)]
+	}
+
+	similar := make(map[primitive.ObjectID][]weightedTrack, len(tracks))
+	for id, f := range features {
+		scored := map[primitive.ObjectID]bool{id: true}
+		var neighbours []weightedTrack
+		for _, key := range f.bucketKeys() {
+			for _, other := range buckets[key] {
+				if scored[other] {
+					continue
+				}
+				scored[other] = true
+				neighbours = append(neighbours, weightedTrack{other, f.similarity(features[other])})
+			}
+		}
+		if len(neighbours) > 0 {
+			similar[id] = topWeighted(neighbours, recNeighbours)
+		}
+	}
+	return similar
+}
+
+func (f trackFeatures) bucketKeys() []string {
+	var keys []string
+	if f.artist != "" {
+		keys = append(keys, "artist:"+f.artist)
+	}
+	if f.genre != "" {
+		keys = append(keys, "genre:"+f.genre)
+	}
+	for _, tag := range f.tags {
+		keys = append(keys, "tag:"+tag)
+	}
+	return keys
+}
+
+// similarity is between 0 and 1: half for the same artist, 0.3 for the same
+// genre and 0.2 for the overlap of the tags.
+func (f trackFeatures) similarity(other trackFeatures) float64 {
+	score := 0.0
+	if f.artist != "" && f.artist == other.artist {
+		score += 0.5
+	}
+	if f.genre != "" && f.genre == other.genre {
+		score += 0.3
+	}
+	if len(f.tags) > 0 && len(other.tags) > 0 {
+		shared := 0
+		for _, tag := range f.tags {
+			if slices.Contains(other.tags, tag) {
+				shared++
+			}
+		}
+		union := len(f.tags) + len(other.tags) - shared
+		score += 0.2 * float64(shared) / float64(union)
+	}
+	return score
+}
+
+// combineSimilarities blends co-listening and content similarity into the
+// similar tracks of each track, each labelled with the larger contribution.
+func combineSimilarities(listeners, content map[primitive.ObjectID][]weightedTrack) map[primitive.ObjectID][]models.RecommendedTrack {
+	type parts struct{ listeners, content float64 }
+	similar := make(map[primitive.ObjectID][]models.RecommendedTrack, len(content))
+
+	ids := make(map[primitive.ObjectID]bool, len(content))
+	for id := range listeners {
+		ids[id] = true
+	}
+	for id := range content {
+		ids[id] = true
+	}
+	for id := range ids {
+		scores := map[primitive.ObjectID]*parts{}
+		get := func(other primitive.ObjectID) *parts {
+			if scores[other] == nil {
+				scores[other] = &parts{}
+			}
+			return scores[other]
+		}
+		for _, n := range listeners[id] {
+			get(n.id).listeners = recListenerShare * n.weight
+		}
+		for _, n := range content[id] {
+			get(n.id).content = (1 - recListenerShare) * n.weight
+		}
+
+		neighbours := make([]weightedTrack, 0, len(scores))
+		for other, p := range scores {
+			neighbours = append(neighbours, weightedTrack{other, p.listeners + p.content})
+		}
+		neighbours = topWeighted(neighbours, recNeighbours)
+
+		items := make([]models.RecommendedTrack, 0, len(neighbours))
+		for _, n := range neighbours {
+			if n.weight <= 0 {
+				continue
+			}
+			reason := models.RecommendationReasonListeners
+			if p := scores[n.id]; p.content > p.listeners {
+				reason = models.RecommendationReasonContent
+			}
+			items = append(items, models.RecommendedTrack{TrackID: n.id, Score: roundScore(n.weight), Reason: reason})
+		}
+		if len(items) > 0 {
+			similar[id] = items
+		}
+	}
+	return similar
+}
+
+// recommendForUser scores every track similar to the user's tracks by the
+// sum of similarity times the weight of the user's track, leaving out the
+// tracks they already know. BecauseOf is the track contributing the most.
+func recommendForUser(profile []weightedTrack, similar map[primitive.ObjectID][]models.RecommendedTrack) []models.RecommendedTrack {
+	known := make(map[primitive.ObjectID]bool, len(profile))
+	for _, t := range profile {
+		known[t.id] = true
+	}
+
+	type candidate struct {
+		score, best float64
+		because     primitive.ObjectID
+		reason      string
+	}
+	candidates := map[primitive.ObjectID]*candidate{}
+	for _, t := range profile {
+		for _, n := range similar[t.id] {
+			if known[n.TrackID] {
+				continue
+			}
+			c := candidates[n.TrackID]
+			if c == nil {
+				c = &candidate{}
+				candidates[n.TrackID] = c
+			}
+			contribution := t.weight * n.Score
+			c.score += contribution
+			if contribution > c.best {
+				c.best, c.because, c.reason = contribution, t.id, n.Reason
+			}
+		}
+	}
+
+	ranked := make([]weightedTrack, 0, len(candidates))
+	for id, c := range candidates {
+		ranked = append(ranked, weightedTrack{id, c.score})
+	}
+	ranked = topWeighted(ranked, recUserItems)
+
+	items := make([]models.RecommendedTrack, len(ranked))
+	for i, r := range ranked {
+		c := candidates[r.id]
+		because := c.because
+		items[i] = models.RecommendedTrack{TrackID: r.id, Score: roundScore(c.score), Reason: c.reason, BecauseOf: &because}
+	}
+	return items
+}
+
+// topWeighted sorts by weight, highest first, and keeps n. Ties are broken
+// by ID so that runs over the same data give the same lists.
+func topWeighted(tracks []weightedTrack, n int) []weightedTrack {
+	slices.SortFunc(tracks, func(a, b weightedTrack) int {
+		return compareWeights(a.weight, b.weight, a.id, b.id)
+	})
+	return tracks[:min(len(tracks), n)]
+}
+
+func compareWeights(a, b float64, idA, idB primitive.ObjectID) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return bytes.Compare(idA[:], idB[:])
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*1e4) / 1e4
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"math"
+	"music-library-api/internal/models"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// oid returns a fixed ObjectID, so that ties between tracks sort predictably.
+func oid(n byte) primitive.ObjectID {
+	return primitive.ObjectID{11: n}
+}
+
+func checkWeighted(t *testing.T, got, want []weightedTrack) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d tracks %v, want %d %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if got[i].id != want[i].id || math.Abs(got[i].weight-want[i].weight) > 1e-4 {
+			t.Errorf("[%d] = {%x %.4f}, want {%x %.4f}", i, got[i].id[11], got[i].weight, want[i].id[11], want[i].weight)
+		}
+	}
+}
+
+func TestListeningProfiles(t *testing.T) {
+	u1, u2 := oid(101), oid(102)
+	known := map[primitive.ObjectID]bool{oid(1): true, oid(2): true, oid(3): true}
+
+	tests := []struct {
+		name  string
+		plays []models.UserTrackPlays
+		likes []models.Like
+		want  map[primitive.ObjectID][]weightedTrack
+	}{
+		{
+			name:  "plays weigh log(1 + count)",
+			plays: []models.UserTrackPlays{{UserID: u1, TrackID: oid(1), Count: 3}, {UserID: u1, TrackID: oid(2), Count: 10}},
+			want:  map[primitive.ObjectID][]weightedTrack{u1: {{oid(2), math.Log1p(10)}, {oid(1), math.Log1p(3)}}},
+		},
+		{
+			name:  "a like adds to the plays",
+			plays: []models.UserTrackPlays{{UserID: u1, TrackID: oid(1), Count: 3}, {UserID: u1, TrackID: oid(2), Count: 10}},
+			likes: []models.Like{{UserID: u1, TargetID: oid(1)}},
+			want:  map[primitive.ObjectID][]weightedTrack{u1: {{oid(1), math.Log1p(3) + recLikeWeight}, {oid(2), math.Log1p(10)}}},
+		},
+		{
+			name:  "unknown tracks and empty rollups are left out",
+			plays: []models.UserTrackPlays{{UserID: u1, TrackID: oid(9), Count: 50}, {UserID: u2, TrackID: oid(3), Count: 0}},
+			likes: []models.Like{{UserID: u2, TargetID: oid(9)}},
+			want:  map[primitive.ObjectID][]weightedTrack{},
+		},
+		{
+			name:  "users are kept apart",
+			plays: []models.UserTrackPlays{{UserID: u1, TrackID: oid(1), Count: 1}},
+			likes: []models.Like{{UserID: u2, TargetID: oid(1)}},
+			want: map[primitive.ObjectID][]weightedTrack{
+				u1: {{oid(1), math.Log1p(1)}},
+				u2: {{oid(1), recLikeWeight}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := listeningProfiles(tt.plays, tt.likes, known)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d profiles, want %d", len(got), len(tt.want))
+			}
+			for user, want := range tt.want {
+				checkWeighted(t, got[user], want)
+			}
+		})
+	}
+}
+
+func TestListenerSimilarities(t *testing.T) {
+	// shrink(n) is the damping applied when n users share both tracks
+	shrink := func(n float64) float64 { return n / (n + recShrinkage) }
+
+	tests := []struct {
+		name     string
+		profiles map[primitive.ObjectID][]weightedTrack
+		want     map[primitive.ObjectID][]weightedTrack
+	}{
+		{
+			name: "co-listened tracks, more shared listeners rank higher",
+			profiles: map[primitive.ObjectID][]weightedTrack{
+				oid(101): {{oid(1), 1}, {oid(2), 1}},
+				oid(102): {{oid(1), 1}, {oid(2), 1}},
+				oid(103): {{oid(1), 1}, {oid(3), 1}},
+			},
+			want: map[primitive.ObjectID][]weightedTrack{
+				oid(1): {{oid(2), 2 / math.Sqrt(3*2) * shrink(2)}, {oid(3), 1 / math.Sqrt(3*1) * shrink(1)}},
+				oid(2): {{oid(1), 2 / math.Sqrt(2*3) * shrink(2)}},
+				oid(3): {{oid(1), 1 / math.Sqrt(1*3) * shrink(1)}},
+			},
+		},
+		{
+			name: "weights enter the cosine",
+			profiles: map[primitive.ObjectID][]weightedTrack{
+				oid(101): {{oid(1), 2}, {oid(2), 1}},
+			},
+			want: map[primitive.ObjectID][]weightedTrack{
+				oid(1): {{oid(2), 2 / math.Sqrt(4*1) * shrink(1)}},
+				oid(2): {{oid(1), 2 / math.Sqrt(1*4) * shrink(1)}},
+			},
+		},
+		{
+			name: "a track nobody else played has no neighbours",
+			profiles: map[primitive.ObjectID][]weightedTrack{
+				oid(101): {{oid(1), 1}},
+			},
+			want: map[primitive.ObjectID][]weightedTrack{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := listenerSimilarities(tt.profiles)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got neighbours for %d tracks, want %d", len(got), len(tt.want))
+			}
+			for id, want := range tt.want {
+				checkWeighted(t, got[id], want)
+			}
+		})
+	}
+}
+
+func TestContentSimilarities(t *testing.T) {
+	track := func(n byte, artist, genre string, tags ...string) *models.Track {
+		tr := &models.Track{Tags: tags, Search: models.TrackSearchFields{Artist: artist, Genre: genre}}
+		tr.ID = oid(n)
+		return tr
+	}
+	genreID := primitive.NewObjectID()
+	withGenreID := track(5, "", "renamed genre")
+	withGenreID.GenreID = &genreID
+	alsoGenreID := track(6, "", "old genre name")
+	alsoGenreID.GenreID = &genreID
+
+	got := contentSimilarities([]*models.Track{
+		track(1, "den", "rap", "chill", "night"),
+		track(2, "den", "rap", "night"),
+		track(3, "mono", "rap"),
+		track(4, "", "", "chill"),
+		withGenreID,
+		alsoGenreID,
+		track(7, "lonely", "folk"),
+	})
+
+	tests := []struct {
+		name string
+		id   primitive.ObjectID
+		want []weightedTrack
+	}{
+		{"artist, genre and half of the tags", oid(1), []weightedTrack{{oid(2), 0.5 + 0.3 + 0.2*1/2}, {oid(3), 0.3}, {oid(4), 0.2 * 1 / 2}}},
+		{"genre only", oid(3), []weightedTrack{{oid(1), 0.3}, {oid(2), 0.3}}},
+		{"tags only", oid(4), []weightedTrack{{oid(1), 0.2 * 1 / 2}}},
+		{"genre ID takes precedence over the name", oid(5), []weightedTrack{{oid(6), 0.3}}},
+		{"nothing in common", oid(7), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkWeighted(t, got[tt.id], tt.want)
+		})
+	}
+}
+
+func TestCombineSimilarities(t *testing.T) {
+	tests := []struct {
+		name      string
+		listeners map[primitive.ObjectID][]weightedTrack
+		content   map[primitive.ObjectID][]weightedTrack
+		want      []models.RecommendedTrack
+	}{
+		{
+			name:      "scores add up, labelled with the larger part",
+			listeners: map[primitive.ObjectID][]weightedTrack{oid(1): {{oid(2), 0.5}}},
+			content:   map[primitive.ObjectID][]weightedTrack{oid(1): {{oid(2), 0.2}, {oid(3), 0.9}}},
+			want: []models.RecommendedTrack{
+				{TrackID: oid(2), Score: roundScore(recListenerShare*0.5 + (1-recListenerShare)*0.2), Reason: models.RecommendationReasonListeners},
+				{TrackID: oid(3), Score: roundScore((1 - recListenerShare) * 0.9), Reason: models.RecommendationReasonContent},
+			},
+		},
+		{
+			name:      "listeners only",
+			listeners: map[primitive.ObjectID][]weightedTrack{oid(1): {{oid(2), 0.4}}},
+			want:      []models.RecommendedTrack{{TrackID: oid(2), Score: roundScore(recListenerShare * 0.4), Reason: models.RecommendationReasonListeners}},
+		},
+		{
+			name:    "zero scores are dropped",
+			content: map[primitive.ObjectID][]weightedTrack{oid(1): {{oid(2), 0}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := combineSimilarities(tt.listeners, tt.content)[oid(1)]
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecommendForUser(t *testing.T) {
+	listeners, content := models.RecommendationReasonListeners, models.RecommendationReasonContent
+	similar := map[primitive.ObjectID][]models.RecommendedTrack{
+		oid(1): {{TrackID: oid(3), Score: 0.5, Reason: listeners}, {TrackID: oid(2), Score: 0.9, Reason: content}},
+		oid(2): {{TrackID: oid(3), Score: 0.4, Reason: content}, {TrackID: oid(4), Score: 0.1, Reason: content}},
+	}
+
+	type want struct {
+		id        primitive.ObjectID
+		score     float64
+		reason    string
+		becauseOf primitive.ObjectID
+	}
+	tests := []struct {
+		name    string
+		profile []weightedTrack
+		want    []want
+	}{
+		{
+			name:    "contributions add up and known tracks are left out",
+			profile: []weightedTrack{{oid(1), 2}, {oid(2), 1}},
+			want: []want{
+				{oid(3), 2*0.5 + 1*0.4, listeners, oid(1)},
+				{oid(4), 0.1, content, oid(2)},
+			},
+		},
+		{
+			name:    "because of follows the largest contribution",
+			profile: []weightedTrack{{oid(1), 0.5}, {oid(2), 1}},
+			want: []want{
+				{oid(3), 0.5*0.5 + 1*0.4, content, oid(2)},
+				{oid(4), 0.1, content, oid(2)},
+			},
+		},
+		{
+			name:    "nothing similar",
+			profile: []weightedTrack{{oid(9), 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := recommendForUser(tt.profile, similar)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d recommendations, want %d", len(got), len(tt.want))
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.TrackID != w.id || g.Score != roundScore(w.score) || g.Reason != w.reason || g.BecauseOf == nil || *g.BecauseOf != w.becauseOf {
+					t.Errorf("[%d] = {%x %v %s %v}, want {%x %v %s %x}", i, g.TrackID[11], g.Score, g.Reason, g.BecauseOf, w.id[11], roundScore(w.score), w.reason, w.becauseOf[11])
+				}
+			}
+		})
+	}
+}
+
+func TestTopWeighted(t *testing.T) {
+	tests := []struct {
+		name   string
+		tracks []weightedTrack
+		n      int
+		want   []weightedTrack
+	}{
+		{"highest first", []weightedTrack{{oid(1), 0.1}, {oid(2), 0.9}, {oid(3), 0.5}}, 10, []weightedTrack{{oid(2), 0.9}, {oid(3), 0.5}, {oid(1), 0.1}}},
+		{"ties by ID", []weightedTrack{{oid(3), 1}, {oid(1), 1}, {oid(2), 1}}, 10, []weightedTrack{{oid(1), 1}, {oid(2), 1}, {oid(3), 1}}},
+		{"keeps n", []weightedTrack{{oid(1), 0.1}, {oid(2), 0.9}, {oid(3), 0.5}}, 2, []weightedTrack{{oid(2), 0.9}, {oid(3), 0.5}}},
+		{"empty", nil, 5, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkWeighted(t, topWeighted(tt.tracks, tt.n), tt.want)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"log"
+	"music-library-api/internal/dto"
+	"music-library-api/internal/mappers"
+	"music-library-api/internal/models"
+	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
+	"sync/atomic"
+	"time"
+)
+
+type ISuggestService interface {
+	Suggest(query string, limit int) dto.SuggestResponse
+	Refresh() error
+	Start(interval time.Duration)
+}
+
+// suggestSnapshot is an immutable index per suggestion kind. Refresh builds a
+// new one and swaps it in, so readers never lock.
+type suggestSnapshot struct {
+	indexes map[string]*utils.PrefixIndex
+	entries map[string][]models.SuggestEntry
+}
+
+type SuggestService struct {
+	repo     repositories.ISuggestRepository
+	snapshot atomic.Pointer[suggestSnapshot]
+}
+
+func NewSuggestService(repo repositories.ISuggestRepository) ISuggestService {
+	return &SuggestService{repo: repo}
+}
+
+// Suggest returns up to limit prefix matches per group. It only reads the
+// in-memory index; an empty response is returned until the first load.
+func (s *SuggestService) Suggest(query string, limit int) dto.SuggestResponse {
+	resp := dto.SuggestResponse{
+		Query:     query,
+		Tracks:    []dto.SuggestionResponse{},
+		Artists:   []dto.SuggestionResponse{},
+		Albums:    []dto.SuggestionResponse{},
+		Playlists: []dto.SuggestionResponse{},
+		Tags:      []dto.SuggestionResponse{},
+	}
+
+	snap := s.snapshot.Load()
+	if snap == nil {
+		return resp
+	}
+
+	terms := utils.SearchTerms(query)
+	groups := map[string]*[]dto.SuggestionResponse{
+		models.SuggestTrack:    &resp.Tracks,
+		models.SuggestArtist:   &resp.Artists,
+		models.SuggestAlbum:    &resp.Albums,
+		models.SuggestPlaylist: &resp.Playlists,
+		models.SuggestTag:      &resp.Tags,
+	}
+	for kind, group := range groups {
+		index := snap.indexes[kind]
+		if index == nil {
+			continue
+		}
+		for _, m := range index.Search(query, limit) {
+			*group = append(*group, mappers.ToSuggestionResponse(snap.entries[kind][m.Doc], terms))
+		}
+	}
+	return resp
+}
+
+// Refresh rebuilds the index from MongoDB.
+func (s *SuggestService) Refresh() error {
+	entries, err := s.repo.LoadSuggestEntries()
+	if err != nil {
+		return err
+	}
+
+	snap := &suggestSnapshot{
+		indexes: map[string]*utils.PrefixIndex{},
+		entries: map[string][]models.SuggestEntry{},
+	}
+	for _, e := range entries {
+		index := snap.indexes[e.Kind]
+		if index == nil {
+			index = utils.NewPrefixIndex()
+			snap.indexes[e.Kind] = index
+		}
+		// Document numbers follow insertion order, matching the entries slice
+		index.Add(e.Text, e.Weight)
+		snap.entries[e.Kind] = append(snap.entries[e.Kind], e)
+	}
+
+	s.snapshot.Store(snap)
+	return nil
+}
+
+// Start loads the index and keeps it warm by rebuilding it every interval.
+func (s *SuggestService) Start(interval time.Duration) {
+	if err := s.Refresh(); err != nil {
+		log.Printf("Failed to build suggestion index: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := s.Refresh(); err != nil {
+				log.Printf("Failed to refresh suggestion index: %v", err)
+			}
+		}
+	}()
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"music-library-api/internal/models"
 	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
 	"strconv"
 	"strings"
 
@@ -15,20 +16,40 @@ import (
 
 type ITrackService interface {
 	GetTrackByID(id string) (*models.Track, error)
-	GetTracks(page, limit int, userID string) ([]*models.Track, error)
+	GetTracks(page, limit int, filter models.TrackFilter) ([]*models.Track, error)
+	GetTracksByCursor(cursor *utils.Cursor, limit int, filter models.TrackFilter) ([]*models.Track, utils.CursorPage, error)
 	CreateTrack(track *models.Track) error
 	UpdateTrack(track *models.Track) error
 	DeleteTrack(id string) error
-	SearchTracks(query string, page, limit int, userID string) ([]*models.Track, error)
-	CountTracks(userID string) (int64, error)
-	CountSearchTracks(query string, userID string) (int64, error)
+	SearchTracks(query string, page, limit int, filter models.TrackFilter, sort models.TrackSort) ([]*models.TrackSearchHit, error)
+	SearchFacets(query string, filter models.TrackFilter) (*models.TrackFacets, error)
+	CountTracks(filter models.TrackFilter) (int64, error)
+	CountSearchTracks(query string, filter models.TrackFilter) (int64, error)
 	UploadMP3ToGridFS(filename string, r io.Reader) (primitive.ObjectID, error)
+	DeleteMP3FromGridFS(fileID primitive.ObjectID) error
 	FindMissingIDs(ids []primitive.ObjectID) ([]primitive.ObjectID, error)
 	ExistAllByIDs(ids []primitive.ObjectID) (bool, error)
 
 	// stream
 	OpenTrackStream(fileID primitive.ObjectID, rangeHeader string) (*TrackStream, error)
 	GetTracksByIDs(ids []primitive.ObjectID) ([]*models.Track, error)
+	FindTracksByFileNames(names []string) (map[string][]*models.Track, error)
+	FindTracksByTitles(titles []string) ([]*models.Track, error)
+	FindSmartTracks(smart *models.SmartPlaylist, ctx repositories.SmartContext) ([]*models.Track, error)
+	GetTracksByUser(userID primitive.ObjectID) ([]*models.Track, error)
+	GetUserTrackStats(userID primitive.ObjectID) (*repositories.TrackStats, error)
+	GetTracksByGenreIDs(genreIDs []primitive.ObjectID, page, limit int) ([]*models.Track, error)
+	CountTracksByGenreIDs(genreIDs []primitive.ObjectID) (int64, error)
+	ReassignGenre(fromIDs []primitive.ObjectID, toID primitive.ObjectID, name string) (int64, error)
+	LinkUnassignedGenre(names []string, genreID primitive.ObjectID, name string) (int64, error)
+	SetLyricsText(id primitive.ObjectID, text string) error
+	IncrementPlayCount(id primitive.ObjectID) (int64, error)
+	IncrementLikeCount(id primitive.ObjectID, delta int64) (int64, error)
+	GetPopularTracks(filter models.TrackFilter, limit int) ([]*models.Track, error)
+	GetTrackFeatures() ([]*models.Track, error)
+	BackfillSearchFields() (int64, error)
+	BackfillPlayCounts() (int64, error)
+	EnsureSearchIndex() error
 }
 
 type TrackService struct {
@@ -55,8 +76,12 @@ func (s *TrackService) GetTrackByID(id string) (*models.Track, error) {
 	return s.repo.GetTrackByID(id)
 }
 
-func (s *TrackService) GetTracks(page, limit int, userID string) ([]*models.Track, error) {
-	return s.repo.GetTracks(page, limit, userID)
+func (s *TrackService) GetTracks(page, limit int, filter models.TrackFilter) ([]*models.Track, error) {
+	return s.repo.GetTracks(page, limit, filter)
+}
+
+func (s *TrackService) GetTracksByCursor(cursor *utils.Cursor, limit int, filter models.TrackFilter) ([]*models.Track, utils.CursorPage, error) {
+	return s.repo.GetTracksByCursor(cursor, limit, filter)
 }
 
 func (s *TrackService) CreateTrack(track *models.Track) error {
@@ -71,16 +96,20 @@ func (s *TrackService) DeleteTrack(id string) error {
 	return s.repo.DeleteTrack(id)
 }
 
-func (s *TrackService) SearchTracks(query string, page, limit int, userID string) ([]*models.Track, error) {
-	return s.repo.SearchTracks(query, page, limit, userID)
+func (s *TrackService) SearchTracks(query string, page, limit int, filter models.TrackFilter, sort models.TrackSort) ([]*models.TrackSearchHit, error) {
+	return s.repo.SearchTracks(query, page, limit, filter, sort)
+}
+
+func (s *TrackService) SearchFacets(query string, filter models.TrackFilter) (*models.TrackFacets, error) {
+	return s.repo.SearchFacets(query, filter)
 }
 
-func (s *TrackService) CountTracks(userID string) (int64, error) {
-	return s.repo.CountTracks(userID)
+func (s *TrackService) CountTracks(filter models.TrackFilter) (int64, error) {
+	return s.repo.CountTracks(filter)
 }
 
-func (s *TrackService) CountSearchTracks(query string, userID string) (int64, error) {
-	return s.repo.CountSearchTracks(query, userID)
+func (s *TrackService) CountSearchTracks(query string, filter models.TrackFilter) (int64, error) {
+	return s.repo.CountSearchTracks(query, filter)
 }
 
 // Upload MP3 file to GridFS
@@ -92,6 +121,15 @@ func (s *TrackService) UploadMP3ToGridFS(filename string, r io.Reader) (primitiv
 	return bucket.UploadFromStream(filename, r)
 }
 
+// DeleteMP3FromGridFS removes an uploaded file and its chunks.
+func (s *TrackService) DeleteMP3FromGridFS(fileID primitive.ObjectID) error {
+	bucket, err := gridfs.NewBucket(s.mongodb)
+	if err != nil {
+		return err
+	}
+	return bucket.Delete(fileID)
+}
+
 // OpenTrackStream open GridFS file stream, and parse Range header
 func (s *TrackService) OpenTrackStream(fileID primitive.ObjectID, rangeHeader string) (*TrackStream, error) {
 
@@ -165,6 +203,18 @@ func (s *TrackService) GetTracksByIDs(ids []primitive.ObjectID) ([]*models.Track
 	return s.repo.GetTracksByIDs(ids)
 }
 
+func (s *TrackService) FindTracksByFileNames(names []string) (map[string][]*models.Track, error) {
+	return s.repo.FindTracksByFileNames(names)
+}
+
+func (s *TrackService) FindTracksByTitles(titles []string) ([]*models.Track, error) {
+	return s.repo.FindTracksByTitles(titles)
+}
+
+func (s *TrackService) FindSmartTracks(smart *models.SmartPlaylist, ctx repositories.SmartContext) ([]*models.Track, error) {
+	return s.repo.FindSmartTracks(smart, ctx)
+}
+
 func (s *TrackService) FindMissingIDs(ids []primitive.ObjectID) ([]primitive.ObjectID, error) {
 	return s.repo.FindMissingIDs(ids)
 }
@@ -172,3 +222,59 @@ func (s *TrackService) FindMissingIDs(ids []primitive.ObjectID) ([]primitive.Obj
 func (s *TrackService) ExistAllByIDs(ids []primitive.ObjectID) (bool, error) {
 	return s.repo.ExistAllByIDs(ids)
 }
+
+func (s *TrackService) GetTracksByUser(userID primitive.ObjectID) ([]*models.Track, error) {
+	return s.repo.GetTracksByUser(userID)
+}
+
+func (s *TrackService) GetUserTrackStats(userID primitive.ObjectID) (*repositories.TrackStats, error) {
+	return s.repo.GetUserTrackStats(userID)
+}
+
+func (s *TrackService) GetTracksByGenreIDs(genreIDs []primitive.ObjectID, page, limit int) ([]*models.Track, error) {
+	return s.repo.GetTracksByGenreIDs(genreIDs, page, limit)
+}
+
+func (s *TrackService) CountTracksByGenreIDs(genreIDs []primitive.ObjectID) (int64, error) {
+	return s.repo.CountTracksByGenreIDs(genreIDs)
+}
+
+func (s *TrackService) ReassignGenre(fromIDs []primitive.ObjectID, toID primitive.ObjectID, name string) (int64, error) {
+	return s.repo.ReassignGenre(fromIDs, toID, name)
+}
+
+func (s *TrackService) LinkUnassignedGenre(names []string, genreID primitive.ObjectID, name string) (int64, error) {
+	return s.repo.LinkUnassignedGenre(names, genreID, name)
+}
+
+func (s *TrackService) SetLyricsText(id primitive.ObjectID, text string) error {
+	return s.repo.SetLyricsText(id, text)
+}
+
+func (s *TrackService) IncrementPlayCount(id primitive.ObjectID) (int64, error) {
+	return s.repo.IncrementPlayCount(id)
+}
+
+func (s *TrackService) IncrementLikeCount(id primitive.ObjectID, delta int64) (int64, error) {
+	return s.repo.IncrementLikeCount(id, delta)
+}
+
+func (s *TrackService) GetPopularTracks(filter models.TrackFilter, limit int) ([]*models.Track, error) {
+	return s.repo.GetPopularTracks(filter, limit)
+}
+
+func (s *TrackService) GetTrackFeatures() ([]*models.Track, error) {
+	return s.repo.GetTrackFeatures()
+}
+
+func (s *TrackService) BackfillSearchFields() (int64, error) {
+	return s.repo.BackfillSearchFields()
+}
+
+func (s *TrackService) BackfillPlayCounts() (int64, error) {
+	return s.repo.BackfillPlayCounts()
+}
+
+func (s *TrackService) EnsureSearchIndex() error {
+	return s.repo.EnsureSearchIndex()
+}
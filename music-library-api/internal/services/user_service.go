@@ -6,12 +6,14 @@ import (
 	"music-library-api/internal/mappers"
 	"music-library-api/internal/models"
 	"music-library-api/internal/repositories"
+	"music-library-api/pkg/utils"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type IUserService interface {
-	GetAllUsers(page, limit int) ([]dto.UserResponse, int64, error)
+	GetAllUsers(page, limit int) ([]dto.UserResponse, int64, utils.CursorPage, error)
+	GetUsersByCursor(cursor *utils.Cursor, limit int) ([]dto.UserResponse, int64, utils.CursorPage, error)
 	GetUserByID(userID string) (*dto.UserResponse, error)
 	UpdateUserRole(userID string, req *dto.UpdateRoleRequest) (*dto.UserResponse, error)
 	UpdateUserInfo(userID string, req *dto.UpdateUserInfoRequest) (*dto.UserResponse, error)
@@ -26,22 +28,42 @@ func NewUserService(userRepo repositories.IUserRepository) IUserService {
 	return &userService{userRepo: userRepo}
 }
 
-func (s *userService) GetAllUsers(page, limit int) ([]dto.UserResponse, int64, error) {
+func (s *userService) GetAllUsers(page, limit int) ([]dto.UserResponse, int64, utils.CursorPage, error) {
 	users, err := s.userRepo.GetAllUsers(page, limit)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, utils.CursorPage{}, err
 	}
 
 	total, err := s.userRepo.CountUsers()
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, utils.CursorPage{}, err
 	}
 
+	// Cursors let clients switch from page numbers to keyset pagination
+	hasNext := int64((page-1)*limit+len(users)) < total
+	cursors := utils.NewCursorPage(users, page > 1, hasNext)
+	return toUserResponses(users), total, cursors, nil
+}
+
+func (s *userService) GetUsersByCursor(cursor *utils.Cursor, limit int) ([]dto.UserResponse, int64, utils.CursorPage, error) {
+	users, cursors, err := s.userRepo.GetUsersByCursor(cursor, limit)
+	if err != nil {
+		return nil, 0, utils.CursorPage{}, err
+	}
+
+	total, err := s.userRepo.CountUsers()
+	if err != nil {
+		return nil, 0, utils.CursorPage{}, err
+	}
+	return toUserResponses(users), total, cursors, nil
+}
+
+func toUserResponses(users []*models.User) []dto.UserResponse {
 	resp := make([]dto.UserResponse, len(users))
 	for i, u := range users {
 		resp[i] = mappers.ToUserResponse(u)
 	}
-	return resp, total, nil
+	return resp
 }
 
 func (s *userService) GetUserByID(userID string) (*dto.UserResponse, error) {
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"mime/multipart"
@@ -10,6 +11,12 @@ import (
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 )
 
+// ImageUploader stores generated or extracted images; CloudinaryUtil is the
+// implementation used outside tests.
+type ImageUploader interface {
+	UploadImageData(data []byte, publicID, folder string) (string, error)
+}
+
 type CloudinaryUtil struct {
 	cld *cloudinary.Cloudinary
 }
@@ -33,3 +40,20 @@ func (c *CloudinaryUtil) UploadImage(file multipart.File, fileHeader *multipart.
 	}
 	return result.SecureURL, nil
 }
+
+// UploadImageData uploads an image held in memory as publicID, replacing the
+// previous image with that ID.
+func (c *CloudinaryUtil) UploadImageData(data []byte, publicID, folder string) (string, error) {
+	ctx := context.Background()
+	overwrite := true
+	result, err := c.cld.Upload.Upload(ctx, bytes.NewReader(data), uploader.UploadParams{
+		PublicID:   publicID,
+		Folder:     folder,
+		Overwrite:  &overwrite,
+		Invalidate: &overwrite,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cloudinary upload failed: %w", err)
+	}
+	return result.SecureURL, nil
+}
@@ -14,3 +14,12 @@ func ConvertToObjectIDs(ids []string) ([]primitive.ObjectID, error) {
 	}
 	return objs, nil
 }
+
+// Convert ObjectIDs to hex IDs
+func ConvertToHexIDs(ids []primitive.ObjectID) []string {
+	hexes := make([]string, len(ids))
+	for i, id := range ids {
+		hexes[i] = id.Hex()
+	}
+	return hexes
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cursor marks a position in a list sorted by _id. Before selects the items
+// preceding ID (a "previous page") instead of those following it.
+type Cursor struct {
+	ID     primitive.ObjectID `json:"id"`
+	Before bool               `json:"before,omitempty"`
+}
+
+// CursorPage holds the opaque tokens of the neighbouring pages; empty when there is none.
+type CursorPage struct {
+	NextCursor string
+	PrevCursor string
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor returns an opaque, URL-safe token for c.
+func EncodeCursor(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor.
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	c := &Cursor{}
+	if err := json.Unmarshal(data, c); err != nil || c.ID.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// NewCursorPage builds the neighbouring page tokens from the first and last item.
+func NewCursorPage[T mgm.Model](items []T, hasPrev, hasNext bool) CursorPage {
+	page := CursorPage{}
+	if len(items) == 0 {
+		return page
+	}
+	if hasPrev {
+		page.PrevCursor = EncodeCursor(Cursor{ID: modelID(items[0]), Before: true})
+	}
+	if hasNext {
+		page.NextCursor = EncodeCursor(Cursor{ID: modelID(items[len(items)-1])})
+	}
+	return page
+}
+
+func modelID(m mgm.Model) primitive.ObjectID {
+	id, _ := m.GetID().(primitive.ObjectID)
+	return id
+}
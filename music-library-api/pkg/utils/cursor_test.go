@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"after", Cursor{ID: id}},
+		{"before", Cursor{ID: id, Before: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(EncodeCursor(tt.cursor))
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if *got != tt.cursor {
+				t.Errorf("DecodeCursor() = %+v, want %+v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!"},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("nope"))},
+		{"bad object id", base64.RawURLEncoding.EncodeToString([]byte(`{"id":"xyz"}`))},
+		{"zero object id", base64.RawURLEncoding.EncodeToString([]byte(`{"id":"000000000000000000000000"}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", tt.token, err)
+			}
+		})
+	}
+}
+
+type cursorItem struct {
+	mgm.DefaultModel `bson:",inline"`
+}
+
+func TestNewCursorPage(t *testing.T) {
+	first, last := &cursorItem{}, &cursorItem{}
+	first.ID = primitive.NewObjectID()
+	last.ID = primitive.NewObjectID()
+	items := []*cursorItem{first, last}
+
+	tests := []struct {
+		name     string
+		items    []*cursorItem
+		hasPrev  bool
+		hasNext  bool
+		wantPrev *Cursor
+		wantNext *Cursor
+	}{
+		{"empty page", nil, true, true, nil, nil},
+		{"first page", items, false, true, nil, &Cursor{ID: last.ID}},
+		{"middle page", items, true, true, &Cursor{ID: first.ID, Before: true}, &Cursor{ID: last.ID}},
+		{"last page", items, true, false, &Cursor{ID: first.ID, Before: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewCursorPage(tt.items, tt.hasPrev, tt.hasNext)
+			checkCursor(t, "PrevCursor", page.PrevCursor, tt.wantPrev)
+			checkCursor(t, "NextCursor", page.NextCursor, tt.wantNext)
+		})
+	}
+}
+
+func checkCursor(t *testing.T, field, token string, want *Cursor) {
+	t.Helper()
+	if want == nil {
+		if token != "" {
+			t.Errorf("%s = %q, want none", field, token)
+		}
+		return
+	}
+	got, err := DecodeCursor(token)
+	if err != nil {
+		t.Fatalf("%s: DecodeCursor() error = %v", field, err)
+	}
+	if *got != *want {
+		t.Errorf("%s = %+v, want %+v", field, *got, *want)
+	}
+}
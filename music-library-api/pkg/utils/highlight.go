@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SearchTerms splits a free-text query into accent-folded words.
+// Operators and punctuation are dropped, so the result is safe to hand to a
+// search engine or to match literally.
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(FoldDiacritics(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return UniqueStrings(words)
+}
+
+// Highlight wraps every word of text starting with one of terms in pre/post,
+// ignoring case and accents. It reports whether anything matched.
+func Highlight(text string, terms []string, pre, post string) (string, bool) {
+	spans := matchSpans([]rune(text), terms)
+	if len(spans) == 0 {
+		return text, false
+	}
+
+	original := []rune(text)
+	var b strings.Builder
+	last := 0
+	for _, sp := range spans {
+		b.WriteString(string(original[last:sp[0]]))
+		b.WriteString(pre)
+		b.WriteString(string(original[sp[0]:sp[1]]))
+		b.WriteString(post)
+		last = sp[1]
+	}
+	b.WriteString(string(original[last:]))
+	return b.String(), true
+}
+
+// Snippet returns a highlighted window of about width runes around the first
+// match in a long text such as lyrics, or "" when nothing matches.
+func Snippet(text string, terms []string, width int, pre, post string) string {
+	original := []rune(text)
+	spans := matchSpans(original, terms)
+	if len(spans) == 0 {
+		return ""
+	}
+
+	start := spans[0][0] - width/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + width
+	if end > len(original) {
+		end = len(original)
+	}
+	// Don't cut words in half
+	for start > 0 && !unicode.IsSpace(original[start-1]) {
+		start--
+	}
+	for end < len(original) && !unicode.IsSpace(original[end]) {
+		end++
+	}
+
+	window := strings.Join(strings.Fields(string(original[start:end])), " ")
+	highlighted, _ := Highlight(window, terms, pre, post)
+	if start > 0 {
+		highlighted = "…" + highlighted
+	}
+	if end < len(original) {
+		highlighted += "…"
+	}
+	return highlighted
+}
+
+// matchSpans returns the [start, end) rune ranges of words in text that
+// start with one of terms, compared after folding.
+func matchSpans(text []rune, terms []string) [][2]int {
+	// Fold rune by rune so folded positions map back to the original text
+	folded := make([]rune, len(text))
+	for i, r := range text {
+		f := []rune(FoldDiacritics(string(r)))
+		if len(f) == 1 {
+			folded[i] = f[0]
+		} else {
+			folded[i] = unicode.ToLower(r)
+		}
+	}
+
+	var spans [][2]int
+	for i := 0; i < len(folded); {
+		if !isWordRune(folded[i]) {
+			i++
+			continue
+		}
+		end := i
+		for end < len(folded) && isWordRune(folded[end]) {
+			end++
+		}
+		word := string(folded[i:end])
+		for _, term := range terms {
+			if term != "" && strings.HasPrefix(word, term) {
+				spans = append(spans, [2]int{i, end})
+				break
+			}
+		}
+		i = end
+	}
+	return spans
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
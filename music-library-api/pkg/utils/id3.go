@@ -0,0 +1,392 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// MaxID3TagSize bounds the tag body ReadID3 accepts. The syncsafe size field
+// allows up to 256 MiB, far beyond any real tag with embedded artwork.
+const MaxID3TagSize = 16 << 20
+
+// ErrID3TagTooLarge is returned when the tag header declares a body larger than MaxID3TagSize.
+var ErrID3TagTooLarge = errors.New("id3 tag too large")
+
+// ID3Tag holds the raw frames of an ID3v2.3 / ID3v2.4 tag.
+// Frames are kept as raw bytes so callers can decode the ones they need.
+type ID3Tag struct {
+	Version byte
+	frames  map[string][][]byte
+}
+
+// ReadID3 reads the ID3v2 tag at the start of r.
+// A file without a (supported) tag yields an empty tag, not an error.
+func ReadID3(r io.Reader) (*ID3Tag, error) {
+	tag := &ID3Tag{frames: map[string][][]byte{}}
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return tag, nil
+		}
+		return nil, err
+	}
+	if string(header[:3]) != "ID3" {
+		return tag, nil
+	}
+
+	tag.Version = header[3]
+	if tag.Version != 3 && tag.Version != 4 {
+		return tag, nil
+	}
+
+	flags := header[5]
+	size := syncsafeInt(header[6:10])
+	if size > MaxID3TagSize {
+		return nil, ErrID3TagTooLarge
+	}
+
+	// Read through a LimitReader so a size larger than the file never
+	// allocates more than the bytes actually present
+	body, err := io.ReadAll(io.LimitReader(r, int64(size)))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < size {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	// Tag-level unsynchronisation (v2.3 only, v2.4 does it per frame)
+	if flags&0x80 != 0 && tag.Version == 3 {
+		body = removeUnsync(body)
+	}
+
+	// Skip extended header
+	if flags&0x40 != 0 && len(body) >= 4 {
+		extSize := int(binary.BigEndian.Uint32(body[:4]))
+		if tag.Version == 4 {
+			extSize = syncsafeInt(body[:4])
+		} else {
+			extSize += 4
+		}
+		if extSize > len(body) {
+			return tag, nil
+		}
+		body = body[extSize:]
+	}
+
+	for len(body) >= 10 {
+		id := string(body[:4])
+		if body[0] == 0 {
+			break // padding
+		}
+
+		var frameSize int
+		if tag.Version == 4 {
+			frameSize = syncsafeInt(body[4:8])
+		} else {
+			frameSize = int(binary.BigEndian.Uint32(body[4:8]))
+		}
+		frameFlags := body[9]
+		body = body[10:]
+
+		if frameSize <= 0 || frameSize > len(body) {
+			break
+		}
+
+		data := body[:frameSize]
+		body = body[frameSize:]
+
+		if tag.Version == 4 {
+			// Data length indicator
+			if frameFlags&0x01 != 0 && len(data) >= 4 {
+				data = data[4:]
+			}
+			if frameFlags&0x02 != 0 {
+				data = removeUnsync(data)
+			}
+		}
+
+		tag.frames[id] = append(tag.frames[id], data)
+	}
+
+	return tag, nil
+}
+
+// Frames returns every raw frame body stored under the given frame ID.
+func (t *ID3Tag) Frames(id string) [][]byte {
+	if t == nil {
+		return nil
+	}
+	return t.frames[id]
+}
+
+// Text returns the decoded value of the first text frame (e.g. TIT2, TRCK).
+func (t *ID3Tag) Text(id string) string {
+	frames := t.Frames(id)
+	if len(frames) == 0 || len(frames[0]) == 0 {
+		return ""
+	}
+	data := frames[0]
+	text := DecodeID3Text(data[0], data[1:])
+	// Multiple values are separated by NUL, keep the first one
+	if i := strings.IndexRune(text, 0); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
+
+// Texts returns every value of a multi-valued text frame (e.g. TCOM), split on
+// NUL (v2.4) or "/" (common v2.3 convention).
+func (t *ID3Tag) Texts(id string) []string {
+	frames := t.Frames(id)
+	if len(frames) == 0 || len(frames[0]) == 0 {
+		return nil
+	}
+	data := frames[0]
+	text := DecodeID3Text(data[0], data[1:])
+
+	var values []string
+	for _, v := range strings.FieldsFunc(text, func(r rune) bool { return r == 0 || r == '/' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func (t *ID3Tag) Title() string  { return t.Text("TIT2") }
+func (t *ID3Tag) Artist() string { return t.Text("TPE1") }
+func (t *ID3Tag) Album() string  { return t.Text("TALB") }
+
+// Genre returns TCON with ID3v1 style references like "(13)" stripped.
+func (t *ID3Tag) Genre() string {
+	genre := t.Text("TCON")
+	for strings.HasPrefix(genre, "(") {
+		end := strings.Index(genre, ")")
+		if end < 0 {
+			break
+		}
+		genre = strings.TrimSpace(genre[end+1:])
+	}
+	return genre
+}
+
+// Year reads TDRC (v2.4) or TYER (v2.3).
+func (t *ID3Tag) Year() int {
+	value := t.Text("TDRC")
+	if value == "" {
+		value = t.Text("TYER")
+	}
+	if len(value) < 4 {
+		return 0
+	}
+	year, _ := strconv.Atoi(value[:4])
+	return year
+}
+
+// TrackNumber parses TRCK, e.g. "3/12" -> (3, 12).
+func (t *ID3Tag) TrackNumber() (int, int) {
+	return ParseNumberPair(t.Text("TRCK"))
+}
+
+// DiscNumber parses TPOS, e.g. "1/2" -> (1, 2).
+func (t *ID3Tag) DiscNumber() (int, int) {
+	return ParseNumberPair(t.Text("TPOS"))
+}
+
+// ParseNumberPair parses "n" or "n/total" values used by TRCK and TPOS.
+func ParseNumberPair(s string) (int, int) {
+	parts := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	n, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
+	total := 0
+	if len(parts) == 2 {
+		total, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+	}
+	return n, total
+}
+
+// DecodeID3Text decodes a string using the ID3 text encoding byte.
+// 0 = ISO-8859-1, 1 = UTF-16 with BOM, 2 = UTF-16BE, 3 = UTF-8.
+func DecodeID3Text(encoding byte, data []byte) string {
+	switch encoding {
+	case 1, 2:
+		return decodeUTF16(data, encoding == 2)
+	case 3:
+		return string(bytes.TrimRight(data, "\x00"))
+	default:
+		runes := make([]rune, 0, len(data))
+		for _, b := range data {
+			runes = append(runes, rune(b))
+		}
+		return strings.TrimRight(string(runes), "\x00")
+	}
+}
+
+// SplitID3Text splits data at the first NUL terminator of the given encoding,
+// returning the decoded string and the remaining bytes.
+func SplitID3Text(encoding byte, data []byte) (string, []byte) {
+	if encoding == 1 || encoding == 2 {
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return DecodeID3Text(encoding, data[:i]), data[i+2:]
+			}
+		}
+		return DecodeID3Text(encoding, data), nil
+	}
+
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return DecodeID3Text(encoding, data[:i]), data[i+1:]
+	}
+	return DecodeID3Text(encoding, data), nil
+}
+
+func decodeUTF16(data []byte, bigEndian bool) string {
+	if len(data) >= 2 {
+		switch {
+		case data[0] == 0xFF && data[1] == 0xFE:
+			bigEndian = false
+			data = data[2:]
+		case data[0] == 0xFE && data[1] == 0xFF:
+			bigEndian = true
+			data = data[2:]
+		}
+	}
+
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		var u uint16
+		if bigEndian {
+			u = uint16(data[i])<<8 | uint16(data[i+1])
+		} else {
+			u = uint16(data[i+1])<<8 | uint16(data[i])
+		}
+		if u == 0 {
+			break
+		}
+		units = append(units, u)
+	}
+	return string(utf16.Decode(units))
+}
+
+func syncsafeInt(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
+
+func removeUnsync(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte{0xFF, 0x00}, []byte{0xFF})
+}
+
+// ID3Lyrics is an unsynchronised lyrics frame (USLT).
+type ID3Lyrics struct {
+	Language    string
+	Description string
+	Text        string
+}
+
+// ID3SyncedLyrics is a synchronised lyrics frame (SYLT) with millisecond timestamps.
+type ID3SyncedLyrics struct {
+	Language    string
+	Description string
+	Lines       []LyricLine
+}
+
+// UnsyncedLyrics decodes every USLT frame.
+func (t *ID3Tag) UnsyncedLyrics() []ID3Lyrics {
+	var out []ID3Lyrics
+	for _, data := range t.Frames("USLT") {
+		if len(data) < 5 {
+			continue
+		}
+		encoding := data[0]
+		lang := string(data[1:4])
+		desc, rest := SplitID3Text(encoding, data[4:])
+		out = append(out, ID3Lyrics{
+			Language:    lang,
+			Description: desc,
+			Text:        strings.TrimSpace(DecodeID3Text(encoding, rest)),
+		})
+	}
+	return out
+}
+
+// SyncedLyrics decodes every SYLT frame that holds lyrics (content type 1)
+// with millisecond timestamps. Frames timed in MPEG frames are skipped.
+func (t *ID3Tag) SyncedLyrics() []ID3SyncedLyrics {
+	var out []ID3SyncedLyrics
+	for _, data := range t.Frames("SYLT") {
+		if len(data) < 6 {
+			continue
+		}
+		encoding := data[0]
+		lang := string(data[1:4])
+		timestampFormat := data[4]
+		contentType := data[5]
+		if timestampFormat != 2 || (contentType != 1 && contentType != 0) {
+			continue
+		}
+
+		desc, rest := SplitID3Text(encoding, data[6:])
+		lyrics := ID3SyncedLyrics{Language: lang, Description: desc}
+
+		for len(rest) > 0 {
+			var text string
+			text, rest = SplitID3Text(encoding, rest)
+			if len(rest) < 4 {
+				break
+			}
+			ms := int64(binary.BigEndian.Uint32(rest[:4]))
+			rest = rest[4:]
+			// Lines conventionally start with "\n"
+			lyrics.Lines = append(lyrics.Lines, LyricLine{TimeMs: ms, Text: strings.TrimSpace(text)})
+		}
+
+		if len(lyrics.Lines) > 0 {
+			out = append(out, lyrics)
+		}
+	}
+	return out
+}
+
+// ID3Picture is an attached picture frame (APIC), e.g. the album artwork.
+type ID3Picture struct {
+	MIMEType    string
+	Type        byte // 3 = front cover
+	Description string
+	Data        []byte
+}
+
+// Picture returns the front cover, or the first attached picture when no
+// frame is marked as the front cover. It returns nil when there is none.
+func (t *ID3Tag) Picture() *ID3Picture {
+	var first *ID3Picture
+	for _, data := range t.Frames("APIC") {
+		if len(data) < 4 {
+			continue
+		}
+		encoding := data[0]
+		mime, rest := SplitID3Text(0, data[1:])
+		if len(rest) < 2 {
+			continue
+		}
+		pictureType := rest[0]
+		desc, picture := SplitID3Text(encoding, rest[1:])
+		if len(picture) == 0 {
+			continue
+		}
+
+		pic := &ID3Picture{MIMEType: strings.ToLower(mime), Type: pictureType, Description: desc, Data: picture}
+		if pictureType == 3 {
+			return pic
+		}
+		if first == nil {
+			first = pic
+		}
+	}
+	return first
+}
@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func syncsafe(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+// id3Frame encodes a single frame; v2.4 sizes are syncsafe, v2.3 plain big endian.
+func id3Frame(version byte, id string, data []byte) []byte {
+	frame := []byte(id)
+	if version == 4 {
+		frame = append(frame, syncsafe(len(data))...)
+	} else {
+		size := make([]byte, 4)
+		binary.BigEndian.PutUint32(size, uint32(len(data)))
+		frame = append(frame, size...)
+	}
+	frame = append(frame, 0, 0)
+	return append(frame, data...)
+}
+
+func id3Tag(version byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	body = append(body, make([]byte, 16)...) // padding
+	tag := append([]byte{'I', 'D', '3', version, 0, 0}, syncsafe(len(body))...)
+	return append(tag, body...)
+}
+
+func textFrame(version byte, id, value string) []byte {
+	return id3Frame(version, id, append([]byte{3}, value...))
+}
+
+func TestReadID3(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		version byte
+		album   string
+		track   [2]int
+		disc    [2]int
+		genre   string
+		year    int
+	}{
+		{
+			name:    "v2.4 frames",
+			input:   id3Tag(4, textFrame(4, "TALB", "Abbey Road"), textFrame(4, "TRCK", "3/17"), textFrame(4, "TPOS", "1/1"), textFrame(4, "TDRC", "1969-09-26")),
+			version: 4,
+			album:   "Abbey Road",
+			track:   [2]int{3, 17},
+			disc:    [2]int{1, 1},
+			year:    1969,
+		},
+		{
+			name:    "v2.3 frames with numeric genre reference",
+			input:   id3Tag(3, textFrame(3, "TCON", "(17)Rock"), textFrame(3, "TYER", "1994"), textFrame(3, "TRCK", "7")),
+			version: 3,
+			track:   [2]int{7, 0},
+			genre:   "Rock",
+			year:    1994,
+		},
+		{
+			name:  "no tag",
+			input: []byte("not an mp3 header at all"),
+		},
+		{
+			name:  "unsupported version",
+			input: append([]byte{'I', 'D', '3', 2, 0, 0}, syncsafe(0)...),
+		},
+		{
+			name:  "short input",
+			input: []byte("ID3"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := ReadID3(bytes.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadID3() error = %v", err)
+			}
+			if tt.version != 0 && tag.Version != tt.version {
+				t.Errorf("Version = %d, want %d", tag.Version, tt.version)
+			}
+			if got := tag.Album(); got != tt.album {
+				t.Errorf("Album() = %q, want %q", got, tt.album)
+			}
+			if n, total := tag.TrackNumber(); [2]int{n, total} != tt.track {
+				t.Errorf("TrackNumber() = %d/%d, want %v", n, total, tt.track)
+			}
+			if n, total := tag.DiscNumber(); [2]int{n, total} != tt.disc {
+				t.Errorf("DiscNumber() = %d/%d, want %v", n, total, tt.disc)
+			}
+			if got := tag.Genre(); got != tt.genre {
+				t.Errorf("Genre() = %q, want %q", got, tt.genre)
+			}
+			if got := tag.Year(); got != tt.year {
+				t.Errorf("Year() = %d, want %d", got, tt.year)
+			}
+		})
+	}
+}
+
+func TestReadID3RejectsBadSizes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  error
+	}{
+		{
+			name:  "declared size above the cap",
+			input: append([]byte{'I', 'D', '3', 4, 0, 0}, 0x7F, 0x7F, 0x7F, 0x7F),
+			want:  ErrID3TagTooLarge,
+		},
+		{
+			name:  "declared size larger than the file",
+			input: append(append([]byte{'I', 'D', '3', 4, 0, 0}, syncsafe(1<<20)...), "short"...),
+			want:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadID3(bytes.NewReader(tt.input)); !errors.Is(err, tt.want) {
+				t.Errorf("ReadID3() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestID3TextEncodings(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"latin1", append([]byte{0}, 'C', 'a', 'f', 0xE9), "Café"},
+		{"utf16 with BOM", []byte{1, 0xFF, 0xFE, 'H', 0, 'i', 0}, "Hi"},
+		{"utf16be", []byte{2, 0, 'H', 0, 'i'}, "Hi"},
+		{"utf8 first of several values", append([]byte{3}, "Sơn Tùng\x00Other"...), "Sơn Tùng"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := ReadID3(bytes.NewReader(id3Tag(4, id3Frame(4, "TPE1", tt.data))))
+			if err != nil {
+				t.Fatalf("ReadID3() error = %v", err)
+			}
+			if got := tag.Artist(); got != tt.want {
+				t.Errorf("Artist() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumberPair(t *testing.T) {
+	tests := []struct {
+		input    string
+		n, total int
+	}{
+		{"", 0, 0},
+		{"3", 3, 0},
+		{"3/12", 3, 12},
+		{" 4 / 10 ", 4, 10},
+		{"A/2", 0, 2},
+		{"05/", 5, 0},
+	}
+
+	for _, tt := range tests {
+		n, total := ParseNumberPair(tt.input)
+		if n != tt.n || total != tt.total {
+			t.Errorf("ParseNumberPair(%q) = %d, %d, want %d, %d", tt.input, n, total, tt.n, tt.total)
+		}
+	}
+}
+
+func apicFrame(encoding byte, mime string, pictureType byte, desc string, data []byte) []byte {
+	frame := append([]byte{encoding}, mime...)
+	frame = append(frame, 0, pictureType)
+	frame = append(frame, desc...)
+	frame = append(frame, 0)
+	if encoding == 1 {
+		frame = append(frame, 0) // UTF-16 terminator is two bytes
+	}
+	return id3Frame(4, "APIC", append(frame, data...))
+}
+
+func TestID3Picture(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames [][]byte
+		want   *ID3Picture
+	}{
+		{
+			name: "no picture",
+		},
+		{
+			name:   "front cover preferred over earlier pictures",
+			frames: [][]byte{apicFrame(0, "image/png", 4, "back", []byte{1}), apicFrame(3, "IMAGE/JPEG", 3, "Bìa", []byte{0xFF, 0xD8, 0x00, 0x01})},
+			want:   &ID3Picture{MIMEType: "image/jpeg", Type: 3, Description: "Bìa", Data: []byte{0xFF, 0xD8, 0x00, 0x01}},
+		},
+		{
+			name:   "first picture without a front cover",
+			frames: [][]byte{apicFrame(0, "image/png", 0, "", []byte{7, 8}), apicFrame(0, "image/png", 4, "", []byte{9})},
+			want:   &ID3Picture{MIMEType: "image/png", Type: 0, Data: []byte{7, 8}},
+		},
+		{
+			name:   "empty picture data is skipped",
+			frames: [][]byte{apicFrame(0, "image/png", 3, "x", nil)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := ReadID3(bytes.NewReader(id3Tag(4, tt.frames...)))
+			if err != nil {
+				t.Fatalf("ReadID3() error = %v", err)
+			}
+			got := tag.Picture()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Picture() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ISRC: 2-letter country, 3-char registrant, 2-digit year, 5-digit designation.
+var isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+
+// NormalizeISRC accepts both "VN-A0S-23-00001" and "vna0s2300001" and returns
+// the compact uppercase form. An empty input stays empty.
+func NormalizeISRC(s string) (string, error) {
+	isrc := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), "-", ""))
+	if isrc == "" {
+		return "", nil
+	}
+	if !isrcPattern.MatchString(isrc) {
+		return "", fmt.Errorf("invalid ISRC: %s", s)
+	}
+	return isrc, nil
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LyricLine is one line of lyrics starting at TimeMs (milliseconds from the start).
+type LyricLine struct {
+	TimeMs int64
+	Text   string
+}
+
+// LRC is a parsed .lrc file.
+type LRC struct {
+	Tags  map[string]string // ID tags such as ti, ar, al, la
+	Lines []LyricLine       // sorted by time
+}
+
+var (
+	lrcTimeTag = regexp.MustCompile(`^\[(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	lrcIDTag   = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+)
+
+// ParseLRC parses LRC content. Lines may carry several time tags
+// ("[00:12.00][01:30.50]chorus") and the [offset:] tag is applied to all times.
+// Lines without any time tag are ignored.
+func ParseLRC(content string) *LRC {
+	lrc := &LRC{Tags: map[string]string{}}
+	var offset int64
+
+	content = strings.TrimPrefix(content, "\ufeff")
+	for _, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(strings.TrimRight(raw, "\r"))
+		if line == "" {
+			continue
+		}
+
+		var times []int64
+		for {
+			m := lrcTimeTag.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+			times = append(times, lrcTimestamp(m[1], m[2], m[3]))
+			line = line[len(m[0]):]
+		}
+
+		if len(times) == 0 {
+			if m := lrcIDTag.FindStringSubmatch(line); m != nil {
+				key := strings.ToLower(m[1])
+				value := strings.TrimSpace(m[2])
+				lrc.Tags[key] = value
+				if key == "offset" {
+					offset, _ = strconv.ParseInt(value, 10, 64)
+				}
+			}
+			continue
+		}
+
+		text := strings.TrimSpace(line)
+		for _, t := range times {
+			lrc.Lines = append(lrc.Lines, LyricLine{TimeMs: t, Text: text})
+		}
+	}
+
+	// A positive offset shifts lyrics earlier
+	for i := range lrc.Lines {
+		lrc.Lines[i].TimeMs -= offset
+		if lrc.Lines[i].TimeMs < 0 {
+			lrc.Lines[i].TimeMs = 0
+		}
+	}
+
+	sort.SliceStable(lrc.Lines, func(i, j int) bool {
+		return lrc.Lines[i].TimeMs < lrc.Lines[j].TimeMs
+	})
+	return lrc
+}
+
+// FormatLRC renders synced lines as LRC, e.g. "[01:02.35]text".
+func FormatLRC(tags map[string]string, lines []LyricLine) string {
+	var b strings.Builder
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(fmt.Sprintf("[%s:%s]\n", k, tags[k]))
+	}
+
+	for _, l := range lines {
+		minutes := l.TimeMs / 60000
+		seconds := (l.TimeMs % 60000) / 1000
+		hundredths := (l.TimeMs % 1000) / 10
+		b.WriteString(fmt.Sprintf("[%02d:%02d.%02d]%s\n", minutes, seconds, hundredths, l.Text))
+	}
+	return b.String()
+}
+
+func lrcTimestamp(min, sec, frac string) int64 {
+	m, _ := strconv.ParseInt(min, 10, 64)
+	s, _ := strconv.ParseInt(sec, 10, 64)
+	ms := int64(0)
+	if frac != "" {
+		ms, _ = strconv.ParseInt(frac, 10, 64)
+		// "5" = 500ms, "05" = 50ms, "005" = 5ms
+		for i := len(frac); i < 3; i++ {
+			ms *= 10
+		}
+	}
+	return m*60000 + s*1000 + ms
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		tags    map[string]string
+		lines   []LyricLine
+	}{
+		{
+			name:    "id tags and one line per time tag",
+			content: "[ti:Song]\n[AR:Artist]\n[00:01.50]first\n[00:03.25]second",
+			tags:    map[string]string{"ti": "Song", "ar": "Artist"},
+			lines:   []LyricLine{{1500, "first"}, {3250, "second"}},
+		},
+		{
+			name:    "several time tags on one line are sorted",
+			content: "[00:10.00][00:02.00]chorus\n[00:05.00]verse",
+			tags:    map[string]string{},
+			lines:   []LyricLine{{2000, "chorus"}, {5000, "verse"}, {10000, "chorus"}},
+		},
+		{
+			name:    "fraction digits scale to milliseconds",
+			content: "[00:01.5]a\n[00:01.05]b\n[00:01.005]c\n[01:02]d",
+			tags:    map[string]string{},
+			lines:   []LyricLine{{1005, "c"}, {1050, "b"}, {1500, "a"}, {62000, "d"}},
+		},
+		{
+			name:    "positive offset shifts earlier and clamps at zero",
+			content: "[offset:+500]\n[00:00.20]intro\n[00:02.00]line",
+			tags:    map[string]string{"offset": "+500"},
+			lines:   []LyricLine{{0, "intro"}, {1500, "line"}},
+		},
+		{
+			name:    "BOM, CRLF, blank and untimed lines",
+			content: "\ufeff[00:01.00]one\r\n\r\nplain text\r\n[00:02.00]  two  \r\n",
+			tags:    map[string]string{},
+			lines:   []LyricLine{{1000, "one"}, {2000, "two"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lrc := ParseLRC(tt.content)
+			if !reflect.DeepEqual(lrc.Tags, tt.tags) {
+				t.Errorf("Tags = %v, want %v", lrc.Tags, tt.tags)
+			}
+			if !reflect.DeepEqual(lrc.Lines, tt.lines) {
+				t.Errorf("Lines = %v, want %v", lrc.Lines, tt.lines)
+			}
+		})
+	}
+}
+
+func TestFormatLRC(t *testing.T) {
+	tests := []struct {
+		name  string
+		tags  map[string]string
+		lines []LyricLine
+		want  string
+	}{
+		{
+			name: "empty",
+			want: "",
+		},
+		{
+			name:  "tags sorted before lines",
+			tags:  map[string]string{"ti": "Song", "ar": "Artist"},
+			lines: []LyricLine{{0, "start"}, {62357, "later"}},
+			want:  "[ar:Artist]\n[ti:Song]\n[00:00.00]start\n[01:02.35]later\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatLRC(tt.tags, tt.lines); got != tt.want {
+				t.Errorf("FormatLRC() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLRCRoundTrip(t *testing.T) {
+	lines := []LyricLine{{1230, "một"}, {45670, "hai"}, {185000, "ba"}}
+	if got := ParseLRC(FormatLRC(nil, lines)).Lines; !reflect.DeepEqual(got, lines) {
+		t.Errorf("round trip = %v, want %v", got, lines)
+	}
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// Decoders for the album artwork
+	_ "image/gif"
+	_ "image/png"
+)
+
+// ComposeMosaic draws four images as a 2x2 grid, in reading order, into a
+// size x size JPEG. Each image is center-cropped to a square first.
+func ComposeMosaic(images [4]image.Image, size int) ([]byte, error) {
+	half := size / 2
+	dst := image.NewRGBA(image.Rect(0, 0, half*2, half*2))
+	for i, img := range images {
+		x, y := (i%2)*half, (i/2)*half
+		drawScaled(dst, image.Rect(x, y, x+half, y+half), squareCrop(img.Bounds()), img)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// squareCrop returns the largest square centered in b.
+func squareCrop(b image.Rectangle) image.Rectangle {
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
+
+// drawScaled scales the sr part of src into r of dst, averaging the source
+// pixels under each destination pixel so downscaled artwork stays smooth.
+func drawScaled(dst *image.RGBA, r, sr image.Rectangle, src image.Image) {
+	for y := 0; y < r.Dy(); y++ {
+		sy0 := sr.Min.Y + y*sr.Dy()/r.Dy()
+		sy1 := max(sr.Min.Y+(y+1)*sr.Dy()/r.Dy(), sy0+1)
+		for x := 0; x < r.Dx(); x++ {
+			sx0 := sr.Min.X + x*sr.Dx()/r.Dx()
+			sx1 := max(sr.Min.X+(x+1)*sr.Dx()/r.Dx(), sx0+1)
+
+			var rs, gs, bs, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, _ := src.At(sx, sy).RGBA()
+					rs, gs, bs = rs+uint64(cr), gs+uint64(cg), bs+uint64(cb)
+					n++
+				}
+			}
+			dst.SetRGBA(r.Min.X+x, r.Min.Y+y, color.RGBA{
+				R: uint8(rs / n >> 8),
+				G: uint8(gs / n >> 8),
+				B: uint8(bs / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestComposeMosaic(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	green := color.RGBA{G: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	// A wide image whose center is blue and whose sides are black: the
+	// center crop must only keep the blue part
+	wide := image.NewRGBA(image.Rect(0, 0, 300, 100))
+	for y := 0; y < 100; y++ {
+		for x := 100; x < 200; x++ {
+			wide.Set(x, y, blue)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		images [4]image.Image
+		size   int
+		want   [4]color.RGBA // center of each quadrant, in reading order
+	}{
+		{
+			name:   "solid squares",
+			images: [4]image.Image{solid(50, 50, red), solid(50, 50, green), solid(50, 50, blue), solid(50, 50, white)},
+			size:   200,
+			want:   [4]color.RGBA{red, green, blue, white},
+		},
+		{
+			name:   "non-square artwork is center-cropped",
+			images: [4]image.Image{wide, solid(10, 900, red), solid(1000, 1000, green), solid(3, 3, white)},
+			size:   101, // odd sizes round down to an even grid
+			want:   [4]color.RGBA{blue, red, green, white},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ComposeMosaic(tt.images, tt.size)
+			if err != nil {
+				t.Fatalf("ComposeMosaic() error = %v", err)
+			}
+			img, err := jpeg.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("output is not a JPEG: %v", err)
+			}
+
+			half := tt.size / 2
+			if b := img.Bounds(); b.Dx() != half*2 || b.Dy() != half*2 {
+				t.Fatalf("bounds = %v, want %dx%d", b, half*2, half*2)
+			}
+			for i, want := range tt.want {
+				x, y := (i%2)*half+half/2, (i/2)*half+half/2
+				if got := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA); !near(got, want) {
+					t.Errorf("quadrant %d = %v, want about %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+// near allows for JPEG compression artefacts.
+func near(a, b color.RGBA) bool {
+	diff := func(x, y uint8) int {
+		if x > y {
+			return int(x - y)
+		}
+		return int(y - x)
+	}
+	return diff(a.R, b.R) < 24 && diff(a.G, b.G) < 24 && diff(a.B, b.B) < 24
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// ExportPlaylist is the format-neutral content of an exported playlist. All
+// URLs are absolute so that external players can open the file on its own.
+type ExportPlaylist struct {
+	Title    string
+	Creator  string
+	Location string // URL of the playlist itself
+	Image    string
+	Tracks   []ExportTrack
+}
+
+type ExportTrack struct {
+	Location   string // stream URL
+	Identifier string // canonical track URL
+	Title      string
+	Artist     string
+	Album      string
+	Duration   int // in seconds
+	Image      string
+}
+
+// FormatM3U8 renders an extended M3U playlist. The content is UTF-8, which is
+// what the .m3u8 extension tells players to expect.
+func FormatM3U8(pl ExportPlaylist) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	if pl.Title != "" {
+		b.WriteString(fmt.Sprintf("#PLAYLIST:%s\n", playlistLine(pl.Title)))
+	}
+
+	for _, t := range pl.Tracks {
+		b.WriteString(fmt.Sprintf("#EXTINF:%d,%s\n", t.Duration, playlistLine(trackDisplayName(t))))
+		if t.Album != "" {
+			b.WriteString(fmt.Sprintf("#EXTALB:%s\n", playlistLine(t.Album)))
+		}
+		if t.Artist != "" {
+			b.WriteString(fmt.Sprintf("#EXTART:%s\n", playlistLine(t.Artist)))
+		}
+		b.WriteString(t.Location + "\n")
+	}
+	return b.String()
+}
+
+// FormatPLS renders a PLS (version 2) playlist. PLS has no album, artist or
+// artwork fields.
+func FormatPLS(pl ExportPlaylist) string {
+	var b strings.Builder
+	b.WriteString("[playlist]\n")
+	for i, t := range pl.Tracks {
+		n := i + 1
+		b.WriteString(fmt.Sprintf("File%d=%s\n", n, t.Location))
+		b.WriteString(fmt.Sprintf("Title%d=%s\n", n, playlistLine(trackDisplayName(t))))
+		b.WriteString(fmt.Sprintf("Length%d=%d\n", n, t.Duration))
+	}
+	b.WriteString(fmt.Sprintf("NumberOfEntries=%d\n", len(pl.Tracks)))
+	b.WriteString("Version=2\n")
+	return b.String()
+}
+
+type xspfPlaylist struct {
+	XMLName  xml.Name    `xml:"http://xspf.org/ns/0/ playlist"`
+	Version  string      `xml:"version,attr"`
+	Title    string      `xml:"title,omitempty"`
+	Creator  string      `xml:"creator,omitempty"`
+	Location string      `xml:"location,omitempty"`
+	Image    string      `xml:"image,omitempty"`
+	Tracks   []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location   string `xml:"location"`
+	Identifier string `xml:"identifier,omitempty"`
+	Title      string `xml:"title,omitempty"`
+	Creator    string `xml:"creator,omitempty"`
+	Album      string `xml:"album,omitempty"`
+	Duration   int64  `xml:"duration,omitempty"` // in milliseconds
+	Image      string `xml:"image,omitempty"`
+}
+
+// FormatXSPF renders an XSPF ("spiff") playlist.
+func FormatXSPF(pl ExportPlaylist) string {
+	doc := xspfPlaylist{
+		Version:  "1",
+		Title:    pl.Title,
+		Creator:  pl.Creator,
+		Location: pl.Location,
+		Image:    pl.Image,
+		Tracks:   make([]xspfTrack, len(pl.Tracks)),
+	}
+	for i, t := range pl.Tracks {
+		doc.Tracks[i] = xspfTrack{
+			Location:   t.Location,
+			Identifier: t.Identifier,
+			Title:      t.Title,
+			Creator:    t.Artist,
+			Album:      t.Album,
+			Duration:   int64(t.Duration) * 1000,
+			Image:      t.Image,
+		}
+	}
+
+	// Marshalling cannot fail: the document only holds strings and numbers
+	out, _ := xml.MarshalIndent(doc, "", "  ")
+	return xml.Header + string(out) + "\n"
+}
+
+type jspfDocument struct {
+	Playlist jspfPlaylist `json:"playlist"`
+}
+
+type jspfPlaylist struct {
+	Title    string      `json:"title,omitempty"`
+	Creator  string      `json:"creator,omitempty"`
+	Location []string    `json:"location,omitempty"`
+	Image    string      `json:"image,omitempty"`
+	Track    []jspfTrack `json:"track"`
+}
+
+type jspfTrack struct {
+	Location   []string `json:"location"`
+	Identifier []string `json:"identifier,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Creator    string   `json:"creator,omitempty"`
+	Album      string   `json:"album,omitempty"`
+	Duration   int64    `json:"duration,omitempty"` // in milliseconds
+	Image      string   `json:"image,omitempty"`
+}
+
+// FormatJSPF renders the JSON flavour of XSPF.
+func FormatJSPF(pl ExportPlaylist) string {
+	doc := jspfDocument{Playlist: jspfPlaylist{
+		Title:   pl.Title,
+		Creator: pl.Creator,
+		Image:   pl.Image,
+		Track:   make([]jspfTrack, len(pl.Tracks)),
+	}}
+	if pl.Location != "" {
+		doc.Playlist.Location = []string{pl.Location}
+	}
+	for i, t := range pl.Tracks {
+		track := jspfTrack{
+			Location: []string{t.Location},
+			Title:    t.Title,
+			Creator:  t.Artist,
+			Album:    t.Album,
+			Duration: int64(t.Duration) * 1000,
+			Image:    t.Image,
+		}
+		if t.Identifier != "" {
+			track.Identifier = []string{t.Identifier}
+		}
+		doc.Playlist.Track[i] = track
+	}
+
+	// Marshalling cannot fail: the document only holds strings and numbers
+	out, _ := json.MarshalIndent(doc, "", "  ")
+	return string(out) + "\n"
+}
+
+func trackDisplayName(t ExportTrack) string {
+	if t.Artist == "" {
+		return t.Title
+	}
+	return t.Artist + " - " + t.Title
+}
+
+// playlistLine keeps a value on one line in line-based formats.
+func playlistLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+var exportFixture = ExportPlaylist{
+	Title:    "Road\ntrip",
+	Creator:  "tuan",
+	Location: "https://music.example.com/api/playlists/p1",
+	Image:    "https://img.example.com/p1.jpg",
+	Tracks: []ExportTrack{
+		{
+			Location:   "https://music.example.com/api/tracks/t1/stream",
+			Identifier: "https://music.example.com/api/tracks/t1",
+			Title:      "Nơi Này Có Anh",
+			Artist:     "Sơn Tùng M-TP",
+			Album:      "m-tp M-TP",
+			Duration:   260,
+			Image:      "https://img.example.com/a1.jpg",
+		},
+		{
+			Location: "https://music.example.com/api/tracks/t2/stream",
+			Title:    "Untitled  demo",
+			Duration: 61,
+		},
+	},
+}
+
+func TestFormatLineBasedPlaylists(t *testing.T) {
+	tests := []struct {
+		name   string
+		format func(ExportPlaylist) string
+		pl     ExportPlaylist
+		want   string
+	}{
+		{
+			name:   "m3u8",
+			format: FormatM3U8,
+			pl:     exportFixture,
+			want: "#EXTM3U\n" +
+				"#PLAYLIST:Road trip\n" +
+				"#EXTINF:260,Sơn Tùng M-TP - Nơi Này Có Anh\n" +
+				"#EXTALB:m-tp M-TP\n" +
+				"#EXTART:Sơn Tùng M-TP\n" +
+				"https://music.example.com/api/tracks/t1/stream\n" +
+				"#EXTINF:61,Untitled demo\n" +
+				"https://music.example.com/api/tracks/t2/stream\n",
+		},
+		{
+			name:   "m3u8 empty",
+			format: FormatM3U8,
+			want:   "#EXTM3U\n",
+		},
+		{
+			name:   "pls",
+			format: FormatPLS,
+			pl:     exportFixture,
+			want: "[playlist]\n" +
+				"File1=https://music.example.com/api/tracks/t1/stream\n" +
+				"Title1=Sơn Tùng M-TP - Nơi Này Có Anh\n" +
+				"Length1=260\n" +
+				"File2=https://music.example.com/api/tracks/t2/stream\n" +
+				"Title2=Untitled demo\n" +
+				"Length2=61\n" +
+				"NumberOfEntries=2\n" +
+				"Version=2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format(tt.pl); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatXSPF(t *testing.T) {
+	var doc xspfPlaylist
+	if err := xml.Unmarshal([]byte(FormatXSPF(exportFixture)), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if doc.Version != "1" || doc.Title != exportFixture.Title || doc.Image != exportFixture.Image {
+		t.Errorf("playlist = %+v", doc)
+	}
+	if len(doc.Tracks) != 2 {
+		t.Fatalf("len(tracks) = %d, want 2", len(doc.Tracks))
+	}
+	want := xspfTrack{
+		Location:   "https://music.example.com/api/tracks/t1/stream",
+		Identifier: "https://music.example.com/api/tracks/t1",
+		Title:      "Nơi Này Có Anh",
+		Creator:    "Sơn Tùng M-TP",
+		Album:      "m-tp M-TP",
+		Duration:   260000,
+		Image:      "https://img.example.com/a1.jpg",
+	}
+	if doc.Tracks[0] != want {
+		t.Errorf("track 1 = %+v, want %+v", doc.Tracks[0], want)
+	}
+}
+
+func TestFormatJSPF(t *testing.T) {
+	tests := []struct {
+		name     string
+		pl       ExportPlaylist
+		location []string
+		tracks   int
+	}{
+		{"full", exportFixture, []string{exportFixture.Location}, 2},
+		{"empty playlist still has a track array", ExportPlaylist{Title: "x"}, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc struct {
+				Playlist struct {
+					Location []string          `json:"location"`
+					Track    []json.RawMessage `json:"track"`
+				} `json:"playlist"`
+			}
+			out := FormatJSPF(tt.pl)
+			if err := json.Unmarshal([]byte(out), &doc); err != nil {
+				t.Fatalf("output is not valid JSON: %v", err)
+			}
+			if len(doc.Playlist.Location) != len(tt.location) {
+				t.Errorf("location = %v, want %v", doc.Playlist.Location, tt.location)
+			}
+			if doc.Playlist.Track == nil || len(doc.Playlist.Track) != tt.tracks {
+				t.Errorf("track = %v, want %d items", doc.Playlist.Track, tt.tracks)
+			}
+		})
+	}
+
+	var doc jspfDocument
+	if err := json.Unmarshal([]byte(FormatJSPF(exportFixture)), &doc); err != nil {
+		t.Fatal(err)
+	}
+	second := doc.Playlist.Track[1]
+	if second.Identifier != nil || second.Duration != 61000 || second.Location[0] != exportFixture.Tracks[1].Location {
+		t.Errorf("track 2 = %+v", second)
+	}
+}
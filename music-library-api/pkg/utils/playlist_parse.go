@@ -0,0 +1,334 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportEntry is one line of an imported playlist file. Any field may be
+// empty; Duration is 0 when unknown.
+type ImportEntry struct {
+	Location string
+	Title    string
+	Artist   string
+	Album    string
+	Duration int // in seconds
+}
+
+// ImportedPlaylist is the parsed content of a playlist file.
+type ImportedPlaylist struct {
+	Format  string // m3u, pls, xspf or csv
+	Title   string
+	Entries []ImportEntry
+}
+
+var ErrUnknownPlaylistFormat = errors.New("unrecognised playlist format, expected .m3u, .m3u8, .pls, .xspf or .csv")
+
+// ParsePlaylistFile parses an M3U/M3U8, PLS, XSPF or CSV playlist. The format
+// comes from the file extension, or is sniffed from the content.
+func ParsePlaylistFile(filename string, content []byte) (*ImportedPlaylist, error) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+
+	format := strings.TrimPrefix(strings.ToLower(path.Ext(filename)), ".")
+	if format == "m3u8" {
+		format = "m3u"
+	}
+	if format != "m3u" && format != "pls" && format != "xspf" && format != "csv" {
+		format = sniffPlaylistFormat(content)
+	}
+
+	var (
+		pl  *ImportedPlaylist
+		err error
+	)
+	switch format {
+	case "m3u":
+		pl = parseM3U(string(content))
+	case "pls":
+		pl = parsePLS(string(content))
+	case "xspf":
+		pl, err = parseXSPF(content)
+	case "csv":
+		pl, err = parseCSV(content)
+	default:
+		return nil, ErrUnknownPlaylistFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	pl.Format = format
+	return pl, nil
+}
+
+func sniffPlaylistFormat(content []byte) string {
+	head := strings.ToLower(strings.TrimSpace(string(content[:min(len(content), 512)])))
+	switch {
+	case strings.HasPrefix(head, "#extm3u"):
+		return "m3u"
+	case strings.HasPrefix(head, "[playlist]"):
+		return "pls"
+	case strings.HasPrefix(head, "<?xml"), strings.HasPrefix(head, "<playlist"):
+		return "xspf"
+	}
+	return ""
+}
+
+func parseM3U(content string) *ImportedPlaylist {
+	pl := &ImportedPlaylist{}
+	var pending ImportEntry
+
+	for _, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(strings.TrimRight(raw, "\r"))
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#EXTINF:"):
+			// #EXTINF:<seconds> [attributes],<Artist> - <Title>
+			info := strings.TrimPrefix(line, "#EXTINF:")
+			display := ""
+			if i := strings.Index(info, ","); i >= 0 {
+				info, display = info[:i], info[i+1:]
+			}
+			if fields := strings.Fields(info); len(fields) > 0 {
+				if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil && seconds > 0 {
+					pending.Duration = int(seconds + 0.5)
+				}
+			}
+			pending.Artist, pending.Title = splitDisplayName(display)
+		case strings.HasPrefix(line, "#EXTALB:"):
+			pending.Album = strings.TrimSpace(strings.TrimPrefix(line, "#EXTALB:"))
+		case strings.HasPrefix(line, "#EXTART:"):
+			pending.Artist = strings.TrimSpace(strings.TrimPrefix(line, "#EXTART:"))
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			pl.Title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#"):
+			// Other directives and comments
+		default:
+			pending.Location = line
+			pl.Entries = append(pl.Entries, pending)
+			pending = ImportEntry{}
+		}
+	}
+	return pl
+}
+
+func parsePLS(content string) *ImportedPlaylist {
+	pl := &ImportedPlaylist{}
+	byNumber := map[int]*ImportEntry{}
+	var order []int
+
+	for _, raw := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(strings.TrimRight(raw, "\r")), "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		var field string
+		for _, prefix := range []string{"file", "title", "length"} {
+			if strings.HasPrefix(key, prefix) {
+				field = prefix
+				break
+			}
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(key, field))
+		if field == "" || err != nil {
+			continue
+		}
+
+		entry := byNumber[n]
+		if entry == nil {
+			entry = &ImportEntry{}
+			byNumber[n] = entry
+			order = append(order, n)
+		}
+		switch field {
+		case "file":
+			entry.Location = value
+		case "title":
+			entry.Artist, entry.Title = splitDisplayName(value)
+		case "length":
+			if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+				entry.Duration = seconds
+			}
+		}
+	}
+
+	// Entries are numbered; the numbers, not the line order, give the position
+	sort.Ints(order)
+	for _, n := range order {
+		if byNumber[n].Location != "" {
+			pl.Entries = append(pl.Entries, *byNumber[n])
+		}
+	}
+	return pl
+}
+
+type xspfImport struct {
+	Title  string `xml:"title"`
+	Tracks []struct {
+		Location []string `xml:"location"`
+		Title    string   `xml:"title"`
+		Creator  string   `xml:"creator"`
+		Album    string   `xml:"album"`
+		Duration int64    `xml:"duration"` // in milliseconds
+	} `xml:"trackList>track"`
+}
+
+func parseXSPF(content []byte) (*ImportedPlaylist, error) {
+	var doc xspfImport
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("invalid XSPF: %w", err)
+	}
+
+	pl := &ImportedPlaylist{Title: strings.TrimSpace(doc.Title)}
+	for _, t := range doc.Tracks {
+		entry := ImportEntry{
+			Title:    strings.TrimSpace(t.Title),
+			Artist:   strings.TrimSpace(t.Creator),
+			Album:    strings.TrimSpace(t.Album),
+			Duration: int((t.Duration + 500) / 1000),
+		}
+		if len(t.Location) > 0 {
+			entry.Location = strings.TrimSpace(t.Location[0])
+		}
+		pl.Entries = append(pl.Entries, entry)
+	}
+	return pl, nil
+}
+
+// csvColumns maps the header names used by common exporters (including
+// Exportify's "Track Name" / "Artist Name(s)") to entry fields.
+var csvColumns = map[string]string{
+	"title": "title", "track": "title", "track name": "title", "name": "title", "song": "title",
+	"artist": "artist", "artists": "artist", "artist name": "artist", "artist name(s)": "artist", "creator": "artist",
+	"album": "album", "album name": "album",
+	"duration": "duration", "length": "duration", "time": "duration", "duration (ms)": "duration_ms", "duration_ms": "duration_ms",
+	"path": "location", "location": "location", "file": "location", "filename": "location", "url": "location",
+}
+
+func parseCSV(content []byte) (*ImportedPlaylist, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if firstLine, _, _ := strings.Cut(string(content), "\n"); !strings.Contains(firstLine, ",") {
+		switch {
+		case strings.Contains(firstLine, ";"):
+			reader.Comma = ';'
+		case strings.Contains(firstLine, "\t"):
+			reader.Comma = '\t'
+		}
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return &ImportedPlaylist{}, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		if field, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	_, hasTitle := columns["title"]
+	_, hasLocation := columns["location"]
+	if !hasTitle && !hasLocation {
+		return nil, errors.New("invalid CSV: the header needs a title or path column")
+	}
+
+	cell := func(row []string, field string) string {
+		if i, ok := columns[field]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	pl := &ImportedPlaylist{}
+	for _, row := range rows[1:] {
+		entry := ImportEntry{
+			Location: cell(row, "location"),
+			Title:    cell(row, "title"),
+			Artist:   cell(row, "artist"),
+			Album:    cell(row, "album"),
+			Duration: parseClockDuration(cell(row, "duration")),
+		}
+		if ms, err := strconv.Atoi(cell(row, "duration_ms")); err == nil {
+			entry.Duration = (ms + 500) / 1000
+		}
+		if entry.Location == "" && entry.Title == "" {
+			continue
+		}
+		pl.Entries = append(pl.Entries, entry)
+	}
+	return pl, nil
+}
+
+// parseClockDuration reads "245", "4:05" or "1:02:03" as seconds.
+func parseClockDuration(s string) int {
+	if s == "" {
+		return 0
+	}
+	total := 0
+	for _, part := range strings.Split(s, ":") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 {
+			return 0
+		}
+		total = total*60 + n
+	}
+	return total
+}
+
+// splitDisplayName splits "Artist - Title"; without a separator it is all title.
+func splitDisplayName(s string) (artist, title string) {
+	s = strings.TrimSpace(s)
+	if a, t, ok := strings.Cut(s, " - "); ok {
+		return strings.TrimSpace(a), strings.TrimSpace(t)
+	}
+	return "", s
+}
+
+// LocationBaseName returns the file name of a playlist location, whether it is
+// a URL, a file:// URI or a Windows or POSIX path, e.g. "C:\Music\a%20b.mp3"
+// and "file:///music/a%20b.mp3" both give "a b.mp3".
+func LocationBaseName(location string) string {
+	location = strings.ReplaceAll(location, "\\", "/")
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" && len(u.Scheme) > 1 {
+		location = u.Path
+	} else if unescaped, err := url.PathUnescape(location); err == nil {
+		location = unescaped
+	}
+	base := path.Base(location)
+	if base == "." || base == "/" {
+		return ""
+	}
+	return base
+}
+
+var audioExtensions = map[string]bool{
+	".mp3": true, ".flac": true, ".m4a": true, ".aac": true, ".ogg": true,
+	".opus": true, ".wav": true, ".wma": true, ".aiff": true, ".alac": true,
+}
+
+// TrimAudioExtension drops an audio file extension, e.g. "song.mp3" -> "song".
+func TrimAudioExtension(name string) string {
+	ext := path.Ext(name)
+	if audioExtensions[strings.ToLower(ext)] {
+		return strings.TrimSuffix(name, ext)
+	}
+	return name
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParsePlaylistFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     *ImportedPlaylist
+	}{
+		{
+			name:     "extended m3u",
+			filename: "mix.m3u8",
+			content: "\ufeff#EXTM3U\r\n#PLAYLIST:Road trip\r\n" +
+				"#EXTINF:259.6 tvg-id=\"x\",Sơn Tùng M-TP - Nơi Này Có Anh\r\n#EXTALB:m-tp M-TP\r\n/music/noi-nay-co-anh.mp3\r\n" +
+				"# a comment\r\n\r\n" +
+				"#EXTINF:-1,Untitled\r\nhttp://example.com/stream.mp3\r\n",
+			want: &ImportedPlaylist{Format: "m3u", Title: "Road trip", Entries: []ImportEntry{
+				{Location: "/music/noi-nay-co-anh.mp3", Title: "Nơi Này Có Anh", Artist: "Sơn Tùng M-TP", Album: "m-tp M-TP", Duration: 260},
+				{Location: "http://example.com/stream.mp3", Title: "Untitled"},
+			}},
+		},
+		{
+			name:     "plain m3u is just locations",
+			filename: "list.m3u",
+			content:  "a.mp3\nb.mp3\n",
+			want: &ImportedPlaylist{Format: "m3u", Entries: []ImportEntry{
+				{Location: "a.mp3"}, {Location: "b.mp3"},
+			}},
+		},
+		{
+			name:     "pls is ordered by entry number",
+			filename: "list.pls",
+			content: "[playlist]\nFile2=b.mp3\nTitle2=Artist B - Song B\nLength2=-1\n" +
+				"File1=a.mp3\nTitle1=Song A\nLength1=200\nTitle3=no file\nNumberOfEntries=3\nVersion=2\n",
+			want: &ImportedPlaylist{Format: "pls", Entries: []ImportEntry{
+				{Location: "a.mp3", Title: "Song A", Duration: 200},
+				{Location: "b.mp3", Title: "Song B", Artist: "Artist B"},
+			}},
+		},
+		{
+			name:     "xspf",
+			filename: "list.xspf",
+			content: `<?xml version="1.0"?><playlist version="1" xmlns="http://xspf.org/ns/0/"><title> Chill </title><trackList>` +
+				`<track><location>file:///a.mp3</location><location>ignored</location><title>A</title><creator>X</creator><album>Al</album><duration>61499</duration></track>` +
+				`<track><title>No location</title></track></trackList></playlist>`,
+			want: &ImportedPlaylist{Format: "xspf", Title: "Chill", Entries: []ImportEntry{
+				{Location: "file:///a.mp3", Title: "A", Artist: "X", Album: "Al", Duration: 61},
+				{Title: "No location"},
+			}},
+		},
+		{
+			name:     "exportify csv",
+			filename: "liked.csv",
+			content:  "Track Name,Artist Name(s),Album Name,Duration (ms)\n\"Hello, World\",Someone,Alb,185500\n,,,\n",
+			want: &ImportedPlaylist{Format: "csv", Entries: []ImportEntry{
+				{Title: "Hello, World", Artist: "Someone", Album: "Alb", Duration: 186},
+			}},
+		},
+		{
+			name:     "semicolon csv with clock durations",
+			filename: "list.csv",
+			content:  "title;artist;length;path\nA;X;4:05;a.mp3\nB;Y;1:02:03;\n",
+			want: &ImportedPlaylist{Format: "csv", Entries: []ImportEntry{
+				{Title: "A", Artist: "X", Duration: 245, Location: "a.mp3"},
+				{Title: "B", Artist: "Y", Duration: 3723},
+			}},
+		},
+		{
+			name:     "format sniffed from content",
+			filename: "download.txt",
+			content:  "[playlist]\nFile1=a.mp3\n",
+			want:     &ImportedPlaylist{Format: "pls", Entries: []ImportEntry{{Location: "a.mp3"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlaylistFile(tt.filename, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParsePlaylistFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePlaylistFile() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePlaylistFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		unknown  bool
+	}{
+		{"unknown format", "notes.txt", "just some text", true},
+		{"broken xspf", "a.xspf", "<playlist><trackList>", false},
+		{"csv without title or path", "a.csv", "artist,album\nX,Y\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePlaylistFile(tt.filename, []byte(tt.content))
+			if err == nil {
+				t.Fatal("ParsePlaylistFile() succeeded, want an error")
+			}
+			if errors.Is(err, ErrUnknownPlaylistFormat) != tt.unknown {
+				t.Errorf("ParsePlaylistFile() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParseClockDuration(t *testing.T) {
+	tests := map[string]int{"": 0, "245": 245, "4:05": 245, "1:02:03": 3723, "4:xx": 0, "-5": 0}
+	for input, want := range tests {
+		if got := parseClockDuration(input); got != want {
+			t.Errorf("parseClockDuration(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
+
+func TestLocationBaseName(t *testing.T) {
+	tests := []struct {
+		location string
+		want     string
+	}{
+		{`C:\Music\a%20b.mp3`, "a b.mp3"},
+		{"file:///music/a%20b.mp3", "a b.mp3"},
+		{"https://cdn.example.com/x/Song.flac?sig=1", "Song.flac"},
+		{"relative/dir/track.mp3", "track.mp3"},
+		{"", ""},
+		{"/", ""},
+	}
+
+	for _, tt := range tests {
+		if got := LocationBaseName(tt.location); got != tt.want {
+			t.Errorf("LocationBaseName(%q) = %q, want %q", tt.location, got, tt.want)
+		}
+	}
+}
+
+func TestTrimAudioExtension(t *testing.T) {
+	tests := map[string]string{"song.mp3": "song", "Song.FLAC": "Song", "notes.txt": "notes.txt", "v1.2 mix": "v1.2 mix"}
+	for input, want := range tests {
+		if got := TrimAudioExtension(input); got != want {
+			t.Errorf("TrimAudioExtension(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxGramLength caps the edge n-grams stored per word; longer query words are
+// matched on their first maxGramLength runes and then verified.
+const maxGramLength = 12
+
+// PrefixIndex is an in-memory edge-n-gram index for search-as-you-type.
+// Every word of a document is folded (case, accents) and indexed under each of
+// its prefixes, so "tuan" finds "Bùi Anh Tuấn". It is not safe for concurrent
+// writes; build it once and then only read from it.
+type PrefixIndex struct {
+	grams   map[string][]int          // prefix -> documents, ascending
+	words   map[rune]map[string][]int // first rune -> word -> documents, for typo tolerance
+	texts   []string                  // folded words of each document, space separated
+	weights []float64
+}
+
+// PrefixMatch is a document matching every query word.
+type PrefixMatch struct {
+	Doc int
+	// Fuzzy is set when at least one word only matched with a typo
+	Fuzzy bool
+	// Leading is set when the document text starts with the query
+	Leading bool
+}
+
+func NewPrefixIndex() *PrefixIndex {
+	return &PrefixIndex{
+		grams: map[string][]int{},
+		words: map[rune]map[string][]int{},
+	}
+}
+
+// Add indexes text and returns its document number (0, 1, 2, ...).
+// Among equally good matches, documents with a higher weight rank first.
+func (idx *PrefixIndex) Add(text string, weight float64) int {
+	doc := len(idx.texts)
+	terms := SearchTerms(text)
+	for _, word := range terms {
+		runes := []rune(word)
+		if idx.words[runes[0]] == nil {
+			idx.words[runes[0]] = map[string][]int{}
+		}
+		idx.words[runes[0]][word] = append(idx.words[runes[0]][word], doc)
+
+		for n := 1; n <= len(runes) && n <= maxGramLength; n++ {
+			gram := string(runes[:n])
+			postings := idx.grams[gram]
+			if len(postings) == 0 || postings[len(postings)-1] != doc {
+				idx.grams[gram] = append(postings, doc)
+			}
+		}
+	}
+	idx.texts = append(idx.texts, strings.Join(terms, " "))
+	idx.weights = append(idx.weights, weight)
+	return doc
+}
+
+// Search returns up to limit documents in which every query word is a prefix
+// of some word. Query words of 4+ runes that match nothing are retried allowing
+// one typo (two for 8+ runes). Results starting with the query come first,
+// then exact matches before fuzzy ones, then by weight.
+func (idx *PrefixIndex) Search(query string, limit int) []PrefixMatch {
+	terms := SearchTerms(query)
+	if len(terms) == 0 || limit <= 0 {
+		return nil
+	}
+
+	type termDocs struct {
+		docs  []int
+		fuzzy bool
+	}
+	lists := make([]termDocs, 0, len(terms))
+	for _, term := range terms {
+		docs := idx.prefixDocs(term)
+		fuzzy := false
+		if len(docs) == 0 {
+			docs = idx.fuzzyDocs(term)
+			fuzzy = true
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+		lists = append(lists, termDocs{docs: docs, fuzzy: fuzzy})
+	}
+
+	// Intersect starting from the rarest word
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i].docs) < len(lists[j].docs) })
+	docs := lists[0].docs
+	fuzzy := lists[0].fuzzy
+	for _, l := range lists[1:] {
+		docs = intersectSorted(docs, l.docs)
+		fuzzy = fuzzy || l.fuzzy
+		if len(docs) == 0 {
+			return nil
+		}
+	}
+
+	leading := strings.Join(terms, " ")
+	better := func(a, b PrefixMatch) bool {
+		if a.Leading != b.Leading {
+			return a.Leading
+		}
+		if a.Fuzzy != b.Fuzzy {
+			return !a.Fuzzy
+		}
+		if idx.weights[a.Doc] != idx.weights[b.Doc] {
+			return idx.weights[a.Doc] > idx.weights[b.Doc]
+		}
+		return len(idx.texts[a.Doc]) < len(idx.texts[b.Doc])
+	}
+
+	// Keep the best limit matches with insertion into a small sorted slice
+	top := make([]PrefixMatch, 0, limit)
+	for _, doc := range docs {
+		m := PrefixMatch{Doc: doc, Fuzzy: fuzzy, Leading: strings.HasPrefix(idx.texts[doc], leading)}
+		if len(top) == limit && !better(m, top[limit-1]) {
+			continue
+		}
+		i := sort.Search(len(top), func(i int) bool { return better(m, top[i]) })
+		if len(top) < limit {
+			top = append(top, PrefixMatch{})
+		}
+		copy(top[i+1:], top[i:])
+		top[i] = m
+	}
+	return top
+}
+
+func (idx *PrefixIndex) prefixDocs(term string) []int {
+	runes := []rune(term)
+	if len(runes) <= maxGramLength {
+		return idx.grams[term]
+	}
+
+	var docs []int
+	for _, doc := range idx.grams[string(runes[:maxGramLength])] {
+		for _, word := range strings.Fields(idx.texts[doc]) {
+			if strings.HasPrefix(word, term) {
+				docs = append(docs, doc)
+				break
+			}
+		}
+	}
+	return docs
+}
+
+// fuzzyDocs finds documents with a word close to term. Typos in the first
+// letter are rare, so only words sharing it are compared.
+func (idx *PrefixIndex) fuzzyDocs(term string) []int {
+	runes := []rune(term)
+	maxEdits := 0
+	switch {
+	case len(runes) >= 8:
+		maxEdits = 2
+	case len(runes) >= 4:
+		maxEdits = 1
+	default:
+		return nil
+	}
+
+	seen := map[int]bool{}
+	var docs []int
+	for word, postings := range idx.words[runes[0]] {
+		if !fuzzyPrefix(runes, []rune(word), maxEdits) {
+			continue
+		}
+		for _, doc := range postings {
+			if !seen[doc] {
+				seen[doc] = true
+				docs = append(docs, doc)
+			}
+		}
+	}
+	sort.Ints(docs)
+	return docs
+}
+
+// fuzzyPrefix reports whether term is within maxEdits edits of some prefix of word.
+func fuzzyPrefix(term, word []rune, maxEdits int) bool {
+	if len(word)+maxEdits < len(term) {
+		return false
+	}
+	for n := len(term) - maxEdits; n <= len(term)+maxEdits; n++ {
+		if n < 1 || n > len(word) {
+			continue
+		}
+		if EditDistance(term, word[:n]) <= maxEdits {
+			return true
+		}
+	}
+	return false
+}
+
+func intersectSorted(a, b []int) []int {
+	out := make([]int, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
+
+// EditDistance is the Damerau-Levenshtein (optimal string alignment) distance,
+// counting an adjacent transposition such as "tuna" -> "tuan" as one edit.
+func EditDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
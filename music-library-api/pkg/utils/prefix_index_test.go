@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrefixIndexSearch(t *testing.T) {
+	idx := NewPrefixIndex()
+	idx.Add("Bùi Anh Tuấn", 5)       // 0
+	idx.Add("Nơi Này Có Anh", 50)    // 1
+	idx.Add("Anh Nhà Ở Đâu Thế", 10) // 2
+	idx.Add("Tuần Trăng Mật", 1)     // 3
+	idx.Add("Supercalifragilistic Expialidocious", 0)
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []PrefixMatch
+	}{
+		{
+			name:  "accent-insensitive prefix",
+			query: "tuan",
+			limit: 10,
+			want:  []PrefixMatch{{Doc: 3, Leading: true}, {Doc: 0}},
+		},
+		{
+			name:  "leading match before weight",
+			query: "anh",
+			limit: 10,
+			want:  []PrefixMatch{{Doc: 2, Leading: true}, {Doc: 1}, {Doc: 0}},
+		},
+		{
+			name:  "every word must match",
+			query: "anh tua",
+			limit: 10,
+			want:  []PrefixMatch{{Doc: 0}},
+		},
+		{
+			name:  "limit keeps the best",
+			query: "anh",
+			limit: 1,
+			want:  []PrefixMatch{{Doc: 2, Leading: true}},
+		},
+		{
+			name:  "one typo in a word of four or more runes",
+			query: "tuna",
+			limit: 10,
+			want:  []PrefixMatch{{Doc: 0, Fuzzy: true}, {Doc: 3, Fuzzy: true}},
+		},
+		{
+			name:  "words longer than the stored grams are verified",
+			query: "supercalifragilis",
+			limit: 10,
+			want:  []PrefixMatch{{Doc: 4, Leading: true}},
+		},
+		{
+			name:  "short words get no typo tolerance",
+			query: "anx",
+			limit: 10,
+			want:  nil,
+		},
+		{
+			name:  "empty query",
+			query: "  ",
+			limit: 10,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idx.Search(tt.query, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"tuan", "tuan", 0},
+		{"tuna", "tuan", 1},
+		{"kitten", "sitting", 3},
+		{"anh", "ahn", 1},
+	}
+
+	for _, tt := range tests {
+		if got := EditDistance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxies is the set of reverse proxies (single IPs or CIDR ranges)
+// whose X-Forwarded-* headers may be believed.
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies parses entries such as "10.0.0.0/8" or "127.0.0.1".
+// Blank entries are skipped.
+func ParseTrustedProxies(entries []string) (TrustedProxies, error) {
+	var proxies TrustedProxies
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+			}
+			proxies = append(proxies, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+// Contains reports whether ip (as in gin's Context.RemoteIP) is a trusted proxy.
+func (t TrustedProxies) Contains(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range t {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Strings returns the entries in the form gin's Engine.SetTrustedProxies takes.
+func (t TrustedProxies) Strings() []string {
+	out := make([]string, len(t))
+	for i, prefix := range t {
+		out[i] = prefix.String()
+	}
+	return out
+}
@@ -0,0 +1,47 @@
+package utils
+
+import "testing"
+
+func TestTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 127.0.0.1 ", "", "fd00::/8", "192.168.1.77/24"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"127.0.0.1", true},
+		{"127.0.0.2", false},
+		{"::ffff:127.0.0.1", true},
+		{"fd12::1", true},
+		{"192.168.1.200", true},
+		{"192.168.2.1", false},
+		{"8.8.8.8", false},
+		{"", false},
+		{"not-an-ip", false},
+	}
+	for _, tt := range tests {
+		if got := proxies.Contains(tt.ip); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+
+	if got, want := proxies.Strings(), []string{"10.0.0.0/8", "127.0.0.1/32", "fd00::/8", "192.168.1.0/24"}; len(got) != len(want) || got[3] != want[3] || got[1] != want[1] {
+		t.Errorf("Strings() = %v, want %v", got, want)
+	}
+}
+
+func TestParseTrustedProxiesInvalid(t *testing.T) {
+	for _, entry := range []string{"10.0.0.0/33", "localhost", "1.2.3"} {
+		if _, err := ParseTrustedProxies([]string{entry}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded, want an error", entry)
+		}
+	}
+
+	if proxies, err := ParseTrustedProxies(nil); err != nil || proxies.Contains("127.0.0.1") {
+		t.Errorf("no proxies should trust nothing, got %v, %v", proxies, err)
+	}
+}
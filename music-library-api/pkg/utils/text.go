@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// FoldDiacritics lowercases s and strips accents and tone marks,
+// e.g. "Bùi Anh Tuấn" -> "bui anh tuan". "đ" has no decomposition, so it is mapped to "d" explicitly.
+func FoldDiacritics(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	folded = strings.NewReplacer("đ", "d", "Đ", "D").Replace(folded)
+	return strings.ToLower(folded)
+}
+
+// Slugify builds a URL-safe slug, e.g. "Nhạc Trẻ" -> "nhac-tre".
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range FoldDiacritics(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// NormalizeKey folds s to a comparison key ignoring case, accents, spaces and
+// punctuation, so "V-Pop", "vpop" and "V Pop" all compare equal.
+func NormalizeKey(s string) string {
+	var b strings.Builder
+	for _, r := range FoldDiacritics(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package utils
+
+import "testing"
+
+func TestFoldDiacritics(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"Bùi Anh Tuấn", "bui anh tuan"},
+		{"Đen Vâu", "den vau"},
+		{"đường", "duong"},
+		{"Sơn Tùng M-TP", "son tung m-tp"},
+		{"Beyoncé", "beyonce"},
+		{"Mötley Crüe", "motley crue"},
+		{"already plain", "already plain"},
+	}
+
+	for _, tt := range tests {
+		if got := FoldDiacritics(tt.input); got != tt.want {
+			t.Errorf("FoldDiacritics(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Nhạc Trẻ", "nhac-tre"},
+		{"  Rock & Roll!  ", "rock-roll"},
+		{"V-Pop", "v-pop"},
+		{"Lo-fi Hip Hop 2024", "lo-fi-hip-hop-2024"},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Slugify(tt.input); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"V-Pop", "vpop"},
+		{"vpop", "vpop"},
+		{"V Pop", "vpop"},
+		{"Nhạc Đỏ", "nhacdo"},
+		{" - ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeKey(tt.input); got != tt.want {
+			t.Errorf("NormalizeKey(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}